@@ -0,0 +1,556 @@
+package datatypes
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/driver/mysql"
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/clause"
+	"github.com/fangxing98/jx-gorm/gorm/schema"
+	"github.com/google/uuid"
+)
+
+// Array give a generic data type for one-dimensional array, stored as native array in postgres (e.g. text[], int8[],
+// uuid[]) and json array in other databases.
+type Array[T any] []T
+
+func NewArray[T any](s []T) Array[T] {
+	return Array[T](s)
+}
+
+// Value return json value, implement driver.Valuer interface, postgres array literal is used through GormValue
+func (a Array[T]) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	return json.Marshal(a)
+}
+
+// Scan scan value into Array[T], implements sql.Scanner interface, supports json arrays and postgres arrays
+// of text and binary format
+func (a *Array[T]) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New(fmt.Sprint("Failed to unmarshal array value:", value))
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) > 0 && trimmed[0] == '[':
+		return json.Unmarshal(trimmed, a)
+	case len(trimmed) > 0 && trimmed[0] == '{':
+		elems, err := parsePgArray(string(trimmed))
+		if err != nil {
+			return err
+		}
+
+		result := make(Array[T], len(elems))
+		for idx, elem := range elems {
+			if elem != nil {
+				if err := scanArrayElem(&result[idx], *elem); err != nil {
+					return err
+				}
+			}
+		}
+		*a = result
+		return nil
+	default:
+		return a.scanBinary(data)
+	}
+}
+
+// GormDataType gorm common data type
+func (Array[T]) GormDataType() string {
+	return "array"
+}
+
+// GormDBDataType gorm db data type
+func (Array[T]) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "sqlite":
+		return "JSON"
+	case "mysql":
+		return "JSON"
+	case "postgres":
+		return pgArrayElemType(reflect.TypeOf((*T)(nil)).Elem()) + "[]"
+	case "sqlserver":
+		return "NVARCHAR(MAX)"
+	}
+	return ""
+}
+
+// GormValue gorm value, postgres array literal for postgres, json for other databases
+func (a Array[T]) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	if a == nil {
+		return gorm.Expr("NULL")
+	}
+
+	switch db.Dialector.Name() {
+	case "postgres":
+		literal, err := a.pgLiteral()
+		if err != nil {
+			db.AddError(err)
+		}
+		return gorm.Expr("?", literal)
+	case "mysql":
+		data, _ := json.Marshal(a)
+		if v, ok := db.Dialector.(*mysql.Dialector); ok && !strings.Contains(v.ServerVersion, "MariaDB") {
+			return gorm.Expr("CAST(? AS JSON)", string(data))
+		}
+		return gorm.Expr("?", string(data))
+	}
+
+	data, _ := json.Marshal(a)
+	return gorm.Expr("?", string(data))
+}
+
+// pgLiteral encodes the array as postgres array literal in text format, e.g. {1,2,NULL}, {"a","b \"c\""}
+func (a Array[T]) pgLiteral() (string, error) {
+	var builder strings.Builder
+	builder.WriteByte('{')
+	for idx, elem := range a {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+
+		var value interface{} = elem
+		if valuer, ok := value.(driver.Valuer); ok {
+			v, err := valuer.Value()
+			if err != nil {
+				return "", err
+			}
+			value = v
+		}
+
+		switch v := value.(type) {
+		case nil:
+			builder.WriteString("NULL")
+		case bool:
+			if v {
+				builder.WriteByte('t')
+			} else {
+				builder.WriteByte('f')
+			}
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			builder.WriteString(fmt.Sprint(v))
+		case time.Time:
+			writePgArrayElem(&builder, v.Format(time.RFC3339Nano))
+		case []byte:
+			writePgArrayElem(&builder, string(v))
+		case string:
+			writePgArrayElem(&builder, v)
+		default:
+			if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+				builder.WriteString("NULL")
+			} else {
+				writePgArrayElem(&builder, fmt.Sprint(reflect.Indirect(rv).Interface()))
+			}
+		}
+	}
+	builder.WriteByte('}')
+	return builder.String(), nil
+}
+
+func writePgArrayElem(builder *strings.Builder, s string) {
+	builder.WriteByte('"')
+	for _, r := range s {
+		if r == '"' || r == '\\' {
+			builder.WriteByte('\\')
+		}
+		builder.WriteRune(r)
+	}
+	builder.WriteByte('"')
+}
+
+// parsePgArray parses one-dimensional postgres array literal, NULL elements are nil
+func parsePgArray(s string) ([]*string, error) {
+	// skip dimension decoration, e.g. [0:1]={1,2}
+	if idx := strings.Index(s, "={"); idx > 0 && s[0] == '[' {
+		s = s[idx+1:]
+	}
+
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, fmt.Errorf("invalid postgres array %q", s)
+	}
+
+	var (
+		elems  []*string
+		body   = s[1 : len(s)-1]
+		elem   strings.Builder
+		quoted bool
+		inElem bool
+	)
+
+	if body == "" {
+		return elems, nil
+	}
+
+	flush := func() {
+		value := elem.String()
+		if !quoted && strings.EqualFold(value, "NULL") {
+			elems = append(elems, nil)
+		} else {
+			elems = append(elems, &value)
+		}
+		elem.Reset()
+		quoted, inElem = false, false
+	}
+
+	for i := 0; i < len(body); i++ {
+		switch c := body[i]; {
+		case c == '{':
+			return nil, fmt.Errorf("multi-dimensional postgres array %q is not supported", s)
+		case c == '"':
+			quoted, inElem = true, true
+			for i++; i < len(body) && body[i] != '"'; i++ {
+				if body[i] == '\\' && i+1 < len(body) {
+					i++
+				}
+				elem.WriteByte(body[i])
+			}
+		case c == ',':
+			flush()
+		case !inElem && (c == ' ' || c == '\t' || c == '\n'):
+		default:
+			inElem = true
+			elem.WriteByte(c)
+		}
+	}
+	flush()
+	return elems, nil
+}
+
+// postgres type oid of binary array elements
+const (
+	pgBoolOID    = 16
+	pgInt8OID    = 20
+	pgInt2OID    = 21
+	pgInt4OID    = 23
+	pgTextOID    = 25
+	pgFloat4OID  = 700
+	pgFloat8OID  = 701
+	pgVarcharOID = 1043
+	pgUUIDOID    = 2950
+)
+
+// scanBinary decodes postgres array of binary format, e.g. ndim | flags | element oid | (length | lower bound) * ndim | (length | data) * n
+func (a *Array[T]) scanBinary(data []byte) error {
+	if len(data) < 12 {
+		return fmt.Errorf("invalid postgres binary array of %d bytes", len(data))
+	}
+
+	ndim, oid := int32(binary.BigEndian.Uint32(data[0:4])), binary.BigEndian.Uint32(data[8:12])
+	if ndim == 0 {
+		*a = Array[T]{}
+		return nil
+	} else if ndim != 1 || len(data) < 20 {
+		return fmt.Errorf("multi-dimensional postgres array is not supported")
+	}
+
+	var (
+		length = int(int32(binary.BigEndian.Uint32(data[12:16])))
+		pos    = 20
+		result = make(Array[T], length)
+	)
+
+	for idx := 0; idx < length; idx++ {
+		if pos+4 > len(data) {
+			return fmt.Errorf("invalid postgres binary array")
+		}
+
+		size := int(int32(binary.BigEndian.Uint32(data[pos : pos+4])))
+		if pos += 4; size < 0 {
+			continue
+		} else if pos+size > len(data) {
+			return fmt.Errorf("invalid postgres binary array")
+		}
+
+		elem := data[pos : pos+size]
+		pos += size
+
+		var text string
+		switch oid {
+		case pgBoolOID:
+			text = strconv.FormatBool(len(elem) > 0 && elem[0] != 0)
+		case pgInt2OID:
+			text = strconv.FormatInt(int64(int16(binary.BigEndian.Uint16(elem))), 10)
+		case pgInt4OID:
+			text = strconv.FormatInt(int64(int32(binary.BigEndian.Uint32(elem))), 10)
+		case pgInt8OID:
+			text = strconv.FormatInt(int64(binary.BigEndian.Uint64(elem)), 10)
+		case pgFloat4OID:
+			text = strconv.FormatFloat(float64(math.Float32frombits(binary.BigEndian.Uint32(elem))), 'g', -1, 32)
+		case pgFloat8OID:
+			text = strconv.FormatFloat(math.Float64frombits(binary.BigEndian.Uint64(elem)), 'g', -1, 64)
+		case pgUUIDOID:
+			u, err := uuid.FromBytes(elem)
+			if err != nil {
+				return err
+			}
+			text = u.String()
+		case pgTextOID, pgVarcharOID:
+			text = string(elem)
+		default:
+			return fmt.Errorf("unsupported element type oid %d of postgres binary array", oid)
+		}
+
+		if err := scanArrayElem(&result[idx], text); err != nil {
+			return err
+		}
+	}
+
+	*a = result
+	return nil
+}
+
+// scanArrayElem scans the text of array element into dest
+func scanArrayElem[T any](dest *T, text string) error {
+	if scanner, ok := interface{}(dest).(sql.Scanner); ok {
+		return scanner.Scan(text)
+	}
+
+	rv := reflect.ValueOf(dest).Elem()
+	if rv.Kind() == reflect.Ptr {
+		rv.Set(reflect.New(rv.Type().Elem()))
+		rv = rv.Elem()
+	}
+
+	var err error
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(text)
+	case reflect.Bool:
+		var v bool
+		if text == "t" || text == "f" {
+			v = text == "t"
+		} else if v, err = strconv.ParseBool(text); err != nil {
+			return err
+		}
+		rv.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var v int64
+		if v, err = strconv.ParseInt(text, 10, 64); err == nil {
+			rv.SetInt(v)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var v uint64
+		if v, err = strconv.ParseUint(text, 10, 64); err == nil {
+			rv.SetUint(v)
+		}
+	case reflect.Float32, reflect.Float64:
+		var v float64
+		if v, err = strconv.ParseFloat(text, 64); err == nil {
+			rv.SetFloat(v)
+		}
+	default:
+		if rv.Type() == reflect.TypeOf(time.Time{}) {
+			var t time.Time
+			for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999Z07", "2006-01-02 15:04:05.999999999", "2006-01-02"} {
+				if t, err = time.Parse(layout, text); err == nil {
+					rv.Set(reflect.ValueOf(t))
+					break
+				}
+			}
+		} else if err = json.Unmarshal([]byte(text), rv.Addr().Interface()); err != nil {
+			err = json.Unmarshal([]byte(strconv.Quote(text)), rv.Addr().Interface())
+		}
+	}
+	return err
+}
+
+// pgArrayElemType postgres data type of array elements
+func pgArrayElemType(elemType reflect.Type) string {
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+
+	switch elemType {
+	case reflect.TypeOf(UUID{}), reflect.TypeOf(BinUUID{}), reflect.TypeOf(uuid.UUID{}):
+		return "uuid"
+	case reflect.TypeOf(time.Time{}):
+		return "timestamptz"
+	}
+
+	switch elemType.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int8, reflect.Int16, reflect.Uint8:
+		return "smallint"
+	case reflect.Int32, reflect.Uint16:
+		return "integer"
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return "bigint"
+	case reflect.Float32:
+		return "real"
+	case reflect.Float64:
+		return "double precision"
+	}
+	return "text"
+}
+
+// ArrayQuery query column of Array
+//
+//	// tags @> '{"a","b"}'
+//	db.Where(datatypes.ArrayQuery("tags").Contains("a", "b")).Find(&users)
+func ArrayQuery(column string) *ArrayExpression {
+	return &ArrayExpression{column: column}
+}
+
+// ArrayExpression array query expression, implements clause.Expression interface to use as querier
+type ArrayExpression struct {
+	column   string
+	op       string
+	values   []interface{}
+	lengthOp string
+	length   int
+}
+
+// Contains checks the column contains all the values
+func (array *ArrayExpression) Contains(values ...interface{}) *ArrayExpression {
+	array.op = "contains"
+	array.values = values
+	return array
+}
+
+// Overlaps checks the column contains any of the values
+func (array *ArrayExpression) Overlaps(values ...interface{}) *ArrayExpression {
+	array.op = "overlaps"
+	array.values = values
+	return array
+}
+
+// Any checks the value is an element of the column
+func (array *ArrayExpression) Any(value interface{}) *ArrayExpression {
+	array.op = "any"
+	array.values = []interface{}{value}
+	return array
+}
+
+// Length compares the length of the column with the operator, e.g. =, <>, >, >=, <, <=
+func (array *ArrayExpression) Length(op string, length int) *ArrayExpression {
+	array.op = "length"
+	array.lengthOp = op
+	array.length = length
+	return array
+}
+
+// Build implements clause.Expression
+func (array *ArrayExpression) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok {
+		return
+	}
+
+	if array.op == "length" {
+		switch array.lengthOp {
+		case "=", "<>", "!=", ">", ">=", "<", "<=":
+		default:
+			stmt.AddError(fmt.Errorf("invalid array length operator %q", array.lengthOp))
+			return
+		}
+	}
+
+	switch stmt.Dialector.Name() {
+	case "postgres":
+		switch array.op {
+		case "contains", "overlaps":
+			literal, err := Array[interface{}](array.values).pgLiteral()
+			if err != nil {
+				stmt.AddError(err)
+				return
+			}
+
+			builder.WriteQuoted(array.column)
+			if array.op == "contains" {
+				builder.WriteString(" @> ")
+			} else {
+				builder.WriteString(" && ")
+			}
+			builder.AddVar(stmt, literal)
+		case "any":
+			builder.AddVar(stmt, array.values[0])
+			builder.WriteString(" = ANY(")
+			builder.WriteQuoted(array.column)
+			builder.WriteByte(')')
+		case "length":
+			builder.WriteString("COALESCE(cardinality(")
+			builder.WriteQuoted(array.column)
+			builder.WriteString("),0) " + array.lengthOp + " ")
+			builder.AddVar(stmt, array.length)
+		}
+	case "mysql":
+		switch array.op {
+		case "contains", "any":
+			data, _ := json.Marshal(array.values)
+			builder.WriteString("JSON_CONTAINS(")
+			builder.WriteQuoted(array.column)
+			builder.WriteByte(',')
+			builder.AddVar(stmt, string(data))
+			builder.WriteByte(')')
+		case "overlaps":
+			data, _ := json.Marshal(array.values)
+			builder.WriteString("JSON_OVERLAPS(")
+			builder.WriteQuoted(array.column)
+			builder.WriteByte(',')
+			builder.AddVar(stmt, string(data))
+			builder.WriteByte(')')
+		case "length":
+			builder.WriteString("COALESCE(JSON_LENGTH(")
+			builder.WriteQuoted(array.column)
+			builder.WriteString("),0) " + array.lengthOp + " ")
+			builder.AddVar(stmt, array.length)
+		}
+	case "sqlite", "sqlserver":
+		each := "json_each("
+		if stmt.Dialector.Name() == "sqlserver" {
+			each = "OPENJSON("
+		}
+
+		switch array.op {
+		case "contains", "any":
+			for idx, value := range array.values {
+				if idx > 0 {
+					builder.WriteString(" AND ")
+				}
+				builder.WriteString("EXISTS (SELECT 1 FROM " + each)
+				builder.WriteQuoted(array.column)
+				builder.WriteString(") WHERE value = ")
+				builder.AddVar(stmt, value)
+				builder.WriteByte(')')
+			}
+		case "overlaps":
+			builder.WriteString("EXISTS (SELECT 1 FROM " + each)
+			builder.WriteQuoted(array.column)
+			builder.WriteString(") WHERE value IN ")
+			builder.AddVar(stmt, array.values)
+			builder.WriteByte(')')
+		case "length":
+			builder.WriteString("(SELECT COUNT(*) FROM " + each)
+			builder.WriteQuoted(array.column)
+			builder.WriteString(")) " + array.lengthOp + " ")
+			builder.AddVar(stmt, array.length)
+		}
+	default:
+		stmt.AddError(fmt.Errorf("array query is not supported by %s", stmt.Dialector.Name()))
+	}
+}
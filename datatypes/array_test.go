@@ -0,0 +1,108 @@
+package datatypes_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/datatypes"
+	"github.com/fangxing98/jx-gorm/gorm"
+	. "github.com/fangxing98/jx-gorm/gorm/utils/tests"
+)
+
+var (
+	_ driver.Valuer = datatypes.Array[string]{}
+	_ sql.Scanner   = &datatypes.Array[string]{}
+)
+
+func TestArrayScan(t *testing.T) {
+	var tags datatypes.Array[string]
+	if err := tags.Scan(`{a,"b \"c\"","d,e",NULL}`); err != nil {
+		t.Fatalf("failed to scan postgres array, got error %v", err)
+	}
+	AssertEqual(t, tags, datatypes.Array[string]{"a", `b "c"`, "d,e", ""})
+
+	var nums datatypes.Array[int64]
+	if err := nums.Scan([]byte(`[1,2,3]`)); err != nil {
+		t.Fatalf("failed to scan json array, got error %v", err)
+	}
+	AssertEqual(t, nums, datatypes.Array[int64]{1, 2, 3})
+
+	if err := nums.Scan(nil); err != nil || nums != nil {
+		t.Fatalf("failed to scan null array, got %v, error %v", nums, err)
+	}
+}
+
+func TestArray(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres") {
+		type UserWithArray struct {
+			ID   uint
+			Name string
+			Tags datatypes.Array[string]
+			Nums datatypes.Array[int64]
+		}
+
+		DB.Migrator().DropTable(&UserWithArray{})
+		if err := DB.Migrator().AutoMigrate(&UserWithArray{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		users := []UserWithArray{{
+			Name: "array-1",
+			Tags: datatypes.NewArray([]string{"a", "b"}),
+			Nums: datatypes.NewArray([]int64{1, 2, 3}),
+		}, {
+			Name: "array-2",
+			Tags: datatypes.Array[string]{"c", `d "e"`},
+		}}
+
+		if err := DB.Create(&users).Error; err != nil {
+			t.Errorf("Failed to create users %v", err)
+		}
+
+		var result UserWithArray
+		if err := DB.First(&result, users[1].ID).Error; err != nil {
+			t.Fatalf("failed to find user with array, got error %v", err)
+		}
+		AssertEqual(t, result.Tags, users[1].Tags)
+
+		var results []UserWithArray
+		if err := DB.Where(datatypes.ArrayQuery("tags").Contains("a", "b")).Find(&results).Error; err != nil {
+			t.Fatalf("failed to find users with array contains, got error %v", err)
+		}
+		AssertEqual(t, len(results), 1)
+		AssertEqual(t, results[0].Name, "array-1")
+
+		if err := DB.Where(datatypes.ArrayQuery("tags").Overlaps("c", "z")).Find(&results).Error; err != nil {
+			t.Fatalf("failed to find users with array overlaps, got error %v", err)
+		}
+		AssertEqual(t, len(results), 1)
+		AssertEqual(t, results[0].Name, "array-2")
+
+		if err := DB.Where(datatypes.ArrayQuery("nums").Any(2)).Find(&results).Error; err != nil {
+			t.Fatalf("failed to find users with array any, got error %v", err)
+		}
+		AssertEqual(t, len(results), 1)
+		AssertEqual(t, results[0].Nums, users[0].Nums)
+
+		if err := DB.Where(datatypes.ArrayQuery("nums").Length("=", 0)).Find(&results).Error; err != nil {
+			t.Fatalf("failed to find users with array length, got error %v", err)
+		}
+		AssertEqual(t, len(results), 1)
+		AssertEqual(t, results[0].Name, "array-2")
+	}
+}
+
+func TestArrayQueryUnsupported(t *testing.T) {
+	db, err := gorm.Open(DummyDialector{}, gorm.DBType("dummy"), &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("failed to open dummy db, got error %v", err)
+	}
+
+	var results []struct {
+		Tags datatypes.Array[string]
+	}
+	if err := db.Table("users").Where(datatypes.ArrayQuery("tags").Contains("a")).Find(&results).Error; err == nil {
+		t.Errorf("array query should fail on unsupported dialector")
+	}
+}
@@ -0,0 +1,427 @@
+package datatypes
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/clause"
+	"github.com/fangxing98/jx-gorm/gorm/schema"
+)
+
+// RoundingMode rounding mode of Decimal
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to nearest, ties away from zero
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfDown rounds to nearest, ties toward zero
+	RoundHalfDown
+	// RoundHalfEven rounds to nearest, ties to even (banker's rounding)
+	RoundHalfEven
+	// RoundDown rounds toward zero (truncate)
+	RoundDown
+	// RoundUp rounds away from zero
+	RoundUp
+	// RoundCeiling rounds toward positive infinity
+	RoundCeiling
+	// RoundFloor rounds toward negative infinity
+	RoundFloor
+)
+
+var bigTen = big.NewInt(10)
+
+// Decimal arbitrary-precision decimal number, the value is coefficient * 10^exponent, the zero value is 0
+//
+//	type Order struct {
+//		ID     uint
+//		Amount datatypes.Decimal `gorm:"precision:20;scale:2"`
+//	}
+type Decimal struct {
+	// coefficient is never modified after created, nil means 0
+	coefficient *big.Int
+	exponent    int32
+}
+
+// NewDecimal returns coefficient * 10^exponent, e.g. NewDecimal(1230, -2) is 12.30
+func NewDecimal(coefficient int64, exponent int32) Decimal {
+	return Decimal{coefficient: big.NewInt(coefficient), exponent: exponent}
+}
+
+// NewDecimalFromFloat returns the decimal of the shortest representation of f, panics if f is NaN or infinity
+func NewDecimalFromFloat(f float64) Decimal {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		panic(fmt.Sprintf("datatypes: can't convert %v to Decimal", f))
+	}
+
+	d, _ := ParseDecimal(strconv.FormatFloat(f, 'g', -1, 64))
+	return d
+}
+
+// ParseDecimal parses decimal string, e.g. "12.30", "-0.5", "1.2e-3"
+func ParseDecimal(s string) (Decimal, error) {
+	var (
+		str      = strings.TrimSpace(s)
+		exponent int64
+	)
+
+	if idx := strings.IndexAny(str, "eE"); idx >= 0 {
+		exp, err := strconv.ParseInt(str[idx+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("datatypes: invalid decimal %q", s)
+		}
+		str, exponent = str[:idx], exp
+	}
+
+	if idx := strings.IndexByte(str, '.'); idx >= 0 {
+		exponent -= int64(len(str) - idx - 1)
+		str = str[:idx] + str[idx+1:]
+	}
+
+	digits := strings.TrimLeft(str, "+-")
+	if len(str)-len(digits) > 1 || digits == "" || strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return Decimal{}, fmt.Errorf("datatypes: invalid decimal %q", s)
+	}
+
+	if exponent < math.MinInt32 || exponent > math.MaxInt32 {
+		return Decimal{}, fmt.Errorf("datatypes: decimal %q out of range", s)
+	}
+
+	coefficient, _ := new(big.Int).SetString(str, 10)
+	return Decimal{coefficient: coefficient, exponent: int32(exponent)}, nil
+}
+
+// MustParseDecimal parses decimal string, panics if the string is invalid
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func (d Decimal) coef() *big.Int {
+	if d.coefficient == nil {
+		return new(big.Int)
+	}
+	return d.coefficient
+}
+
+// rescale returns the coefficient of d with the exponent, exponent should not be greater than d.exponent
+func (d Decimal) rescale(exponent int32) *big.Int {
+	if d.exponent == exponent {
+		return d.coef()
+	}
+
+	scale := new(big.Int).Exp(bigTen, big.NewInt(int64(d.exponent)-int64(exponent)), nil)
+	return scale.Mul(scale, d.coef())
+}
+
+// align returns the coefficients of d and d2 with the same exponent
+func (d Decimal) align(d2 Decimal) (*big.Int, *big.Int, int32) {
+	exponent := d.exponent
+	if d2.exponent < exponent {
+		exponent = d2.exponent
+	}
+	return d.rescale(exponent), d2.rescale(exponent), exponent
+}
+
+// Add returns d + d2
+func (d Decimal) Add(d2 Decimal) Decimal {
+	c1, c2, exponent := d.align(d2)
+	return Decimal{coefficient: new(big.Int).Add(c1, c2), exponent: exponent}
+}
+
+// Sub returns d - d2
+func (d Decimal) Sub(d2 Decimal) Decimal {
+	c1, c2, exponent := d.align(d2)
+	return Decimal{coefficient: new(big.Int).Sub(c1, c2), exponent: exponent}
+}
+
+// Mul returns d * d2
+func (d Decimal) Mul(d2 Decimal) Decimal {
+	return Decimal{coefficient: new(big.Int).Mul(d.coef(), d2.coef()), exponent: d.exponent + d2.exponent}
+}
+
+// Div returns d / d2 rounded to scale decimal places with the rounding mode, panics if d2 is zero
+func (d Decimal) Div(d2 Decimal, scale int32, mode RoundingMode) Decimal {
+	if d2.IsZero() {
+		panic("datatypes: decimal division by zero")
+	}
+
+	// d / d2 * 10^scale = c1 * 10^(e1 - e2 + scale) / c2
+	var (
+		num   = new(big.Int).Set(d.coef())
+		den   = new(big.Int).Set(d2.coef())
+		shift = int64(d.exponent) - int64(d2.exponent) + int64(scale)
+	)
+
+	if shift >= 0 {
+		num.Mul(num, new(big.Int).Exp(bigTen, big.NewInt(shift), nil))
+	} else {
+		den.Mul(den, new(big.Int).Exp(bigTen, big.NewInt(-shift), nil))
+	}
+	return Decimal{coefficient: roundQuo(num, den, mode), exponent: -scale}
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{coefficient: new(big.Int).Neg(d.coef()), exponent: d.exponent}
+}
+
+// Abs returns |d|
+func (d Decimal) Abs() Decimal {
+	return Decimal{coefficient: new(big.Int).Abs(d.coef()), exponent: d.exponent}
+}
+
+// Round returns d rounded (or padded with zeros) to scale decimal places with the rounding mode, e.g. 12.345 rounded
+// to 2 with RoundHalfUp is 12.35, 12.3 rounded to 2 is 12.30
+func (d Decimal) Round(scale int32, mode RoundingMode) Decimal {
+	if -scale <= d.exponent {
+		return Decimal{coefficient: d.rescale(-scale), exponent: -scale}
+	}
+
+	den := new(big.Int).Exp(bigTen, big.NewInt(int64(-scale)-int64(d.exponent)), nil)
+	return Decimal{coefficient: roundQuo(d.coef(), den, mode), exponent: -scale}
+}
+
+// roundQuo returns num / den rounded with the rounding mode
+func roundQuo(num, den *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	var (
+		sign = num.Sign() * den.Sign()
+		// compares the remainder with the half of the divisor
+		twice = new(big.Int).Abs(r)
+		half  = twice.Lsh(twice, 1).CmpAbs(den)
+		up    bool
+	)
+
+	switch mode {
+	case RoundHalfUp:
+		up = half >= 0
+	case RoundHalfDown:
+		up = half > 0
+	case RoundHalfEven:
+		up = half > 0 || (half == 0 && q.Bit(0) == 1)
+	case RoundUp:
+		up = true
+	case RoundCeiling:
+		up = sign > 0
+	case RoundFloor:
+		up = sign < 0
+	}
+
+	if up {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+	return q
+}
+
+// Cmp compares d and d2, returns -1 if d < d2, 0 if d == d2, +1 if d > d2
+func (d Decimal) Cmp(d2 Decimal) int {
+	c1, c2, _ := d.align(d2)
+	return c1.Cmp(c2)
+}
+
+// Equal returns whether d == d2, e.g. 1.0 equals 1.00
+func (d Decimal) Equal(d2 Decimal) bool {
+	return d.Cmp(d2) == 0
+}
+
+// LessThan returns whether d < d2
+func (d Decimal) LessThan(d2 Decimal) bool {
+	return d.Cmp(d2) < 0
+}
+
+// GreaterThan returns whether d > d2
+func (d Decimal) GreaterThan(d2 Decimal) bool {
+	return d.Cmp(d2) > 0
+}
+
+// Sign returns -1 if d < 0, 0 if d == 0, +1 if d > 0
+func (d Decimal) Sign() int {
+	return d.coef().Sign()
+}
+
+// IsZero returns whether d == 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Scale returns the number of decimal places, e.g. 2 of 12.30
+func (d Decimal) Scale() int32 {
+	if d.exponent < 0 {
+		return -d.exponent
+	}
+	return 0
+}
+
+// Float64 returns the nearest float64 of d
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String returns the decimal string without exponent, e.g. "12.30", "-0.005", "1200"
+func (d Decimal) String() string {
+	var (
+		coef   = d.coef()
+		digits = new(big.Int).Abs(coef).String()
+		buf    strings.Builder
+	)
+
+	if coef.Sign() < 0 {
+		buf.WriteByte('-')
+	}
+
+	if d.exponent >= 0 {
+		buf.WriteString(digits)
+		if coef.Sign() != 0 {
+			buf.WriteString(strings.Repeat("0", int(d.exponent)))
+		}
+		return buf.String()
+	}
+
+	scale := int(-d.exponent)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	buf.WriteString(digits[:len(digits)-scale])
+	buf.WriteByte('.')
+	buf.WriteString(digits[len(digits)-scale:])
+	return buf.String()
+}
+
+// Value return decimal string, implement driver.Valuer interface
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// GormValue gorm value, binds decimal string as database/sql passes values implemented the decimal interface to
+// drivers directly, which are not supported by some drivers
+func (d Decimal) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	return gorm.Expr("?", d.String())
+}
+
+// Scan scan value into Decimal, implements sql.Scanner interface
+func (d *Decimal) Scan(value interface{}) (err error) {
+	switch v := value.(type) {
+	case nil:
+		*d = Decimal{}
+	case []byte:
+		*d, err = ParseDecimal(string(v))
+	case string:
+		*d, err = ParseDecimal(v)
+	case int64:
+		*d = NewDecimal(v, 0)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("datatypes: can't scan %v into Decimal", v)
+		}
+		*d = NewDecimalFromFloat(v)
+	case decimalDecompose:
+		err = d.Compose(v.Decompose(nil))
+	default:
+		err = errors.New(fmt.Sprint("Failed to scan decimal value:", value))
+	}
+	return
+}
+
+// Decompose returns the internal decimal state in parts, implements the decimal interface of database/sql drivers
+func (d Decimal) Decompose(buf []byte) (form byte, negative bool, coefficient []byte, exponent int32) {
+	abs := new(big.Int).Abs(d.coef())
+	if size := (abs.BitLen() + 7) / 8; cap(buf) >= size {
+		coefficient = abs.FillBytes(buf[:size])
+	} else {
+		coefficient = abs.Bytes()
+	}
+	return 0, d.Sign() < 0, coefficient, d.exponent
+}
+
+// Compose sets the internal decimal value from parts, implements the decimal interface of database/sql drivers
+func (d *Decimal) Compose(form byte, negative bool, coefficient []byte, exponent int32) error {
+	if form != 0 {
+		return errors.New("datatypes: infinite and NaN are not supported by Decimal")
+	}
+
+	coef := new(big.Int).SetBytes(coefficient)
+	if negative {
+		coef.Neg(coef)
+	}
+	*d = Decimal{coefficient: coef, exponent: exponent}
+	return nil
+}
+
+// MarshalJSON marshals as json string, e.g. "12.30", use DecimalNumber to marshal as json number
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON unmarshals json string or json number
+func (d *Decimal) UnmarshalJSON(b []byte) (err error) {
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+
+	*d, err = ParseDecimal(strings.Trim(string(b), `"`))
+	return
+}
+
+// DecimalNumber Decimal marshaled as json number instead of json string, e.g. 12.30 instead of "12.30",
+// numbers may lose precision when decoded as float by javascript clients
+//
+//	type Order struct {
+//		ID     uint
+//		Amount datatypes.DecimalNumber `gorm:"precision:20;scale:2"`
+//	}
+type DecimalNumber struct {
+	Decimal
+}
+
+// MarshalJSON marshals as json number
+func (d DecimalNumber) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// GormDataType gorm common data type
+func (Decimal) GormDataType() string {
+	return "decimal"
+}
+
+// GormDBDataType gorm db data type, DECIMAL(p,s)/NUMERIC(p,s) with the precision and scale of the field
+func (Decimal) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "postgres":
+		if field.Precision > 0 {
+			return fmt.Sprintf("numeric(%d, %d)", field.Precision, field.Scale)
+		}
+		return "numeric"
+	case "mysql":
+		if field.Precision > 0 {
+			return fmt.Sprintf("decimal(%d, %d)", field.Precision, field.Scale)
+		}
+		return "decimal(65, 30)"
+	case "sqlserver":
+		if field.Precision > 0 {
+			return fmt.Sprintf("decimal(%d, %d)", field.Precision, field.Scale)
+		}
+		return "decimal(38, 18)"
+	case "sqlite":
+		if field.Precision > 0 {
+			return fmt.Sprintf("decimal(%d, %d)", field.Precision, field.Scale)
+		}
+		return "numeric"
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+package datatypes_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/datatypes"
+	. "github.com/fangxing98/jx-gorm/gorm/utils/tests"
+)
+
+func TestDecimalArithmetic(t *testing.T) {
+	a, b := datatypes.MustParseDecimal("12.345"), datatypes.NewDecimal(5, -1)
+
+	AssertEqual(t, a.Add(b).String(), "12.845")
+	AssertEqual(t, a.Sub(datatypes.NewDecimal(100, 0)).String(), "-87.655")
+	AssertEqual(t, a.Mul(b).String(), "6.1725")
+	AssertEqual(t, datatypes.NewDecimal(1, 0).Div(datatypes.NewDecimal(3, 0), 4, datatypes.RoundHalfUp).String(), "0.3333")
+	AssertEqual(t, datatypes.MustParseDecimal("12.3").Round(2, datatypes.RoundDown).String(), "12.30")
+	AssertEqual(t, datatypes.MustParseDecimal("1.2e-3").String(), "0.0012")
+	AssertEqual(t, a.Cmp(datatypes.MustParseDecimal("12.3450")), 0)
+	AssertEqual(t, b.LessThan(a), true)
+
+	if _, err := datatypes.ParseDecimal("1..2"); err == nil {
+		t.Errorf("should failed to parse invalid decimal")
+	}
+}
+
+func TestDecimalRound(t *testing.T) {
+	tests := []struct {
+		mode     datatypes.RoundingMode
+		value    string
+		expected string
+	}{
+		{datatypes.RoundHalfUp, "0.125", "0.13"},
+		{datatypes.RoundHalfUp, "-0.125", "-0.13"},
+		{datatypes.RoundHalfDown, "0.125", "0.12"},
+		{datatypes.RoundHalfEven, "0.125", "0.12"},
+		{datatypes.RoundHalfEven, "0.135", "0.14"},
+		{datatypes.RoundDown, "-0.129", "-0.12"},
+		{datatypes.RoundUp, "0.121", "0.13"},
+		{datatypes.RoundCeiling, "-0.129", "-0.12"},
+		{datatypes.RoundFloor, "-0.121", "-0.13"},
+	}
+
+	for _, test := range tests {
+		AssertEqual(t, datatypes.MustParseDecimal(test.value).Round(2, test.mode).String(), test.expected)
+	}
+}
+
+func TestDecimalJSONAndScan(t *testing.T) {
+	d := datatypes.MustParseDecimal("-12.30")
+
+	bytes, err := json.Marshal(d)
+	AssertEqual(t, err, nil)
+	AssertEqual(t, string(bytes), `"-12.30"`)
+
+	var result datatypes.Decimal
+	if err := json.Unmarshal([]byte("12.5"), &result); err != nil {
+		t.Fatalf("failed to unmarshal json number, got error %v", err)
+	}
+	AssertEqual(t, result.String(), "12.5")
+
+	var composed datatypes.Decimal
+	if err := composed.Compose(d.Decompose(nil)); err != nil {
+		t.Fatalf("failed to compose decimal, got error %v", err)
+	}
+	AssertEqual(t, composed.String(), "-12.30")
+
+	bytes, err = json.Marshal(struct{ Amount datatypes.DecimalNumber }{Amount: datatypes.DecimalNumber{Decimal: d}})
+	AssertEqual(t, err, nil)
+	AssertEqual(t, string(bytes), `{"Amount":-12.30}`)
+
+	var number datatypes.DecimalNumber
+	if err := json.Unmarshal([]byte(`"0.10"`), &number); err != nil {
+		t.Fatalf("failed to unmarshal json string, got error %v", err)
+	}
+	AssertEqual(t, number.String(), "0.10")
+
+	for _, value := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if err := result.Scan(value); err == nil {
+			t.Errorf("scanning %v should fail", value)
+		}
+	}
+
+	var null datatypes.Null[datatypes.Decimal]
+	if err := null.Scan([]byte("0.01")); err != nil {
+		t.Fatalf("failed to scan null decimal, got error %v", err)
+	}
+	AssertEqual(t, null.Valid, true)
+	AssertEqual(t, null.V.String(), "0.01")
+}
+
+func TestDecimal(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
+		type OrderWithDecimal struct {
+			ID     uint
+			Amount datatypes.Decimal `gorm:"precision:20;scale:2"`
+			Fee    datatypes.Null[datatypes.Decimal]
+			Tax    datatypes.DecimalNumber `gorm:"precision:20;scale:2"`
+		}
+
+		DB.Migrator().DropTable(&OrderWithDecimal{})
+		if err := DB.Migrator().AutoMigrate(&OrderWithDecimal{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		order := OrderWithDecimal{
+			Amount: datatypes.MustParseDecimal("1234567890123.45"),
+			Fee:    datatypes.NewNull(datatypes.MustParseDecimal("0.01")),
+			Tax:    datatypes.DecimalNumber{Decimal: datatypes.MustParseDecimal("12.30")},
+		}
+		if err := DB.Create(&order).Error; err != nil {
+			t.Errorf("Failed to create order %v", err)
+		}
+
+		var result OrderWithDecimal
+		if err := DB.First(&result, order.ID).Error; err != nil {
+			t.Fatalf("failed to find order with decimal, got error %v", err)
+		}
+		AssertEqual(t, result.Amount.Equal(order.Amount), true)
+		AssertEqual(t, result.Fee.V.Equal(order.Fee.V), true)
+		AssertEqual(t, result.Tax.Equal(order.Tax.Decimal), true)
+	}
+}
@@ -0,0 +1,130 @@
+package datatypes
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/schema"
+)
+
+// EnumType types of Enum, EnumValues returns the allowed values, e.g.
+//
+//	type Status string
+//
+//	func (Status) EnumValues() []Status { return []Status{"active", "inactive"} }
+type EnumType[T any] interface {
+	~string
+	EnumValues() []T
+}
+
+// Enum give a string type T with allowed values, migrated as mysql ENUM, postgres enum type,
+// sqlite and sqlserver CHECK constraint, empty value is NULL
+type Enum[T EnumType[T]] string
+
+// NewEnum new Enum of value
+func NewEnum[T EnumType[T]](value T) Enum[T] {
+	return Enum[T](value)
+}
+
+// Data return the enum value of T
+func (e Enum[T]) Data() T {
+	return T(e)
+}
+
+// Valid return whether the value is allowed
+func (e Enum[T]) Valid() bool {
+	for _, value := range e.Data().EnumValues() {
+		if value == e.Data() {
+			return true
+		}
+	}
+	return false
+}
+
+// GormEnumValues return the allowed values, used by migrator
+func (e Enum[T]) GormEnumValues() []string {
+	values := e.Data().EnumValues()
+	results := make([]string, len(values))
+	for idx, value := range values {
+		results[idx] = string(value)
+	}
+	return results
+}
+
+// Value return string value, implement driver.Valuer interface
+func (e Enum[T]) Value() (driver.Value, error) {
+	if e == "" {
+		return nil, nil
+	}
+	if !e.Valid() {
+		return nil, fmt.Errorf("invalid enum value %q of %T, allowed values: %v", string(e), e.Data(), e.GormEnumValues())
+	}
+	return string(e), nil
+}
+
+// Scan scan value into Enum, implements sql.Scanner interface
+func (e *Enum[T]) Scan(value interface{}) error {
+	var str string
+	switch v := value.(type) {
+	case nil:
+	case []byte:
+		str = string(v)
+	case string:
+		str = v
+	default:
+		return errors.New(fmt.Sprint("Failed to scan enum value:", value))
+	}
+
+	if result := Enum[T](str); str != "" && !result.Valid() {
+		return fmt.Errorf("invalid enum value %q of %T, allowed values: %v", str, result.Data(), result.GormEnumValues())
+	}
+	*e = Enum[T](str)
+	return nil
+}
+
+// GormDataType gorm common data type
+func (Enum[T]) GormDataType() string {
+	return "enum"
+}
+
+// GormDBDataType gorm db data type, the postgres enum type is named after the package and name of T,
+// e.g. orders_status, could be changed with tag `type`
+func (e Enum[T]) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "mysql":
+		return "ENUM(" + enumLiterals(e.GormEnumValues()) + ")"
+	case "postgres":
+		if dataType := string(field.DataType); dataType != "" && dataType != "enum" {
+			return dataType
+		}
+		// qualified with the package as types of the same name in different packages are different enums
+		typ := reflect.TypeOf(e.Data())
+		return db.NamingStrategy.ColumnName("", path.Base(typ.PkgPath())) + "_" + db.NamingStrategy.ColumnName("", typ.Name())
+	case "sqlite":
+		var builder strings.Builder
+		builder.WriteString("text CHECK (")
+		db.Dialector.QuoteTo(&builder, field.DBName)
+		builder.WriteString(" IN (" + enumLiterals(e.GormEnumValues()) + "))")
+		return builder.String()
+	case "sqlserver":
+		if field.Size > 0 {
+			return fmt.Sprintf("nvarchar(%d)", field.Size)
+		}
+		return "nvarchar(255)"
+	}
+	return ""
+}
+
+// enumLiterals return quoted values separated by comma, e.g. 'a','b'
+func enumLiterals(values []string) string {
+	literals := make([]string, len(values))
+	for idx, value := range values {
+		literals[idx] = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+	return strings.Join(literals, ",")
+}
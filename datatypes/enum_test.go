@@ -0,0 +1,119 @@
+package datatypes_test
+
+import (
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/datatypes"
+	"github.com/fangxing98/jx-gorm/driver/postgres"
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/schema"
+	. "github.com/fangxing98/jx-gorm/gorm/utils/tests"
+)
+
+type OrderStatus string
+
+func (OrderStatus) EnumValues() []OrderStatus {
+	return []OrderStatus{"pending", "paid"}
+}
+
+type OrderStatusV2 string
+
+func (OrderStatusV2) EnumValues() []OrderStatusV2 {
+	return []OrderStatusV2{"pending", "paid", "it's shipped"}
+}
+
+type OrderWithEnum struct {
+	ID     uint
+	Status datatypes.Enum[OrderStatus] `gorm:"type:order_status"`
+}
+
+func (OrderWithEnum) TableName() string { return "order_with_enums" }
+
+type OrderWithEnumV2 struct {
+	ID     uint
+	Status datatypes.Enum[OrderStatusV2] `gorm:"type:order_status"`
+}
+
+func (OrderWithEnumV2) TableName() string { return "order_with_enums" }
+
+func TestEnumValueAndScan(t *testing.T) {
+	status := datatypes.NewEnum[OrderStatus]("paid")
+	value, err := status.Value()
+	AssertEqual(t, err, nil)
+	AssertEqual(t, value, "paid")
+	AssertEqual(t, status.Data(), OrderStatus("paid"))
+
+	if _, err := datatypes.Enum[OrderStatus]("shipped").Value(); err == nil {
+		t.Errorf("should failed to get value of invalid enum")
+	}
+
+	value, err = datatypes.Enum[OrderStatus]("").Value()
+	AssertEqual(t, err, nil)
+	AssertEqual(t, value, nil)
+
+	var scanned datatypes.Enum[OrderStatus]
+	if err := scanned.Scan([]byte("pending")); err != nil {
+		t.Fatalf("failed to scan enum, got error %v", err)
+	}
+	AssertEqual(t, scanned, datatypes.Enum[OrderStatus]("pending"))
+
+	if err := scanned.Scan("shipped"); err == nil {
+		t.Errorf("should failed to scan invalid enum")
+	}
+	AssertEqual(t, scanned.GormEnumValues(), []string{"pending", "paid"})
+}
+
+func TestEnum(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
+		DB.Migrator().DropTable(&OrderWithEnum{})
+		if SupportedDriver("postgres") {
+			DB.Exec("DROP TYPE IF EXISTS order_status")
+		}
+		if err := DB.Migrator().AutoMigrate(&OrderWithEnum{}); err != nil {
+			t.Fatalf("failed to migrate, got error: %v", err)
+		}
+
+		if err := DB.Create(&OrderWithEnum{Status: "paid"}).Error; err != nil {
+			t.Errorf("Failed to create order %v", err)
+		}
+
+		if err := DB.Exec("INSERT INTO order_with_enums (status) VALUES (?)", "it's shipped").Error; err == nil {
+			t.Errorf("should failed to insert value not allowed by database")
+		}
+
+		if err := DB.Migrator().AutoMigrate(&OrderWithEnumV2{}); err != nil {
+			t.Fatalf("failed to migrate new enum values, got error: %v", err)
+		}
+
+		order := OrderWithEnumV2{Status: "it's shipped"}
+		if err := DB.Create(&order).Error; err != nil {
+			t.Errorf("Failed to create order with new enum value %v", err)
+		}
+
+		var result OrderWithEnumV2
+		if err := DB.First(&result, order.ID).Error; err != nil {
+			t.Fatalf("failed to find order, got error %v", err)
+		}
+		AssertEqual(t, result.Status.Data(), OrderStatusV2("it's shipped"))
+
+		var count int64
+		DB.Model(&OrderWithEnumV2{}).Count(&count)
+		AssertEqual(t, count, 2)
+
+		if SupportedDriver("postgres") {
+			if err := DB.Migrator().AutoMigrate(&OrderWithEnum{}); err == nil {
+				t.Errorf("should fail to migrate the enum type with values not declared")
+			}
+		}
+	}
+}
+
+func TestEnumPostgresTypeName(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost user=gorm dbname=gorm port=9920"}), gorm.DBTypePostgres, &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+
+	AssertEqual(t, datatypes.Enum[OrderStatus]("").GormDBDataType(db, &schema.Field{DBName: "status"}), "datatypes_test_order_status")
+	AssertEqual(t, datatypes.Enum[OrderStatus]("").GormDBDataType(db, &schema.Field{DBName: "status", DataType: "order_status"}), "order_status")
+}
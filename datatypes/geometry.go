@@ -0,0 +1,728 @@
+package datatypes
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/fangxing98/jx-gorm/driver/mysql"
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/clause"
+	"github.com/fangxing98/jx-gorm/gorm/schema"
+)
+
+// Shape geometry shapes, implemented by Point, LineString, Polygon and Geometry
+type Shape interface {
+	// GeometryType returns the geometry type, Point, LineString, Polygon
+	GeometryType() string
+	geometrySRID() int
+	appendWKB(buf []byte) []byte
+}
+
+// Point geometry point, X is longitude and Y is latitude for geographic coordinates
+//
+//	type Store struct {
+//		ID       uint
+//		Location datatypes.Point `gorm:"srid:4326;index:,class:SPATIAL"`
+//	}
+type Point struct {
+	X, Y float64
+	SRID int
+}
+
+// LineString geometry line string, the SRID of points is ignored
+type LineString struct {
+	Points []Point
+	SRID   int
+}
+
+// Polygon geometry polygon, the first ring is the exterior ring, the SRID of points is ignored
+type Polygon struct {
+	Rings [][]Point
+	SRID  int
+}
+
+// Geometry geometry of any shape, the shape is Point, LineString or Polygon, nil if NULL
+type Geometry struct {
+	Shape Shape
+}
+
+const (
+	wkbPoint      uint32 = 1
+	wkbLineString uint32 = 2
+	wkbPolygon    uint32 = 3
+	// ewkbSRID flag of the geometry type when EWKB has SRID
+	ewkbSRID uint32 = 0x20000000
+	// ewkbZ, ewkbM flags of the geometry type when EWKB has Z, M coordinates
+	ewkbZ uint32 = 0x80000000
+	ewkbM uint32 = 0x40000000
+)
+
+var wkbTypes = map[string]uint32{"Point": wkbPoint, "LineString": wkbLineString, "Polygon": wkbPolygon}
+
+// GeometryType implements Shape
+func (Point) GeometryType() string { return "Point" }
+
+// GeometryType implements Shape
+func (LineString) GeometryType() string { return "LineString" }
+
+// GeometryType implements Shape
+func (Polygon) GeometryType() string { return "Polygon" }
+
+// GeometryType implements Shape, returns Geometry if the shape is nil
+func (g Geometry) GeometryType() string {
+	if g.Shape == nil {
+		return "Geometry"
+	}
+	return g.Shape.GeometryType()
+}
+
+func (p Point) geometrySRID() int      { return p.SRID }
+func (l LineString) geometrySRID() int { return l.SRID }
+func (p Polygon) geometrySRID() int    { return p.SRID }
+
+func (g Geometry) geometrySRID() int {
+	if g.Shape == nil {
+		return 0
+	}
+	return g.Shape.geometrySRID()
+}
+
+func (p Point) appendWKB(buf []byte) []byte {
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(p.X))
+	return binary.LittleEndian.AppendUint64(buf, math.Float64bits(p.Y))
+}
+
+func (l LineString) appendWKB(buf []byte) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(l.Points)))
+	for _, point := range l.Points {
+		buf = point.appendWKB(buf)
+	}
+	return buf
+}
+
+func (p Polygon) appendWKB(buf []byte) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(p.Rings)))
+	for _, ring := range p.Rings {
+		buf = LineString{Points: ring}.appendWKB(buf)
+	}
+	return buf
+}
+
+func (g Geometry) appendWKB(buf []byte) []byte {
+	if g.Shape == nil {
+		return buf
+	}
+	return g.Shape.appendWKB(buf)
+}
+
+// MarshalWKB encodes the shape as WKB (little endian), without SRID, returns nil if the shape is nil
+func MarshalWKB(s Shape) []byte {
+	return marshalWKB(s, false)
+}
+
+// MarshalEWKB encodes the shape as EWKB (little endian) of PostGIS, includes SRID if not zero, returns nil if
+// the shape is nil
+func MarshalEWKB(s Shape) []byte {
+	return marshalWKB(s, true)
+}
+
+func marshalWKB(s Shape, ewkb bool) []byte {
+	for {
+		if g, ok := s.(Geometry); ok {
+			s = g.Shape
+		} else {
+			break
+		}
+	}
+
+	if s == nil {
+		return nil
+	}
+
+	var (
+		typ  = wkbTypes[s.GeometryType()]
+		srid = s.geometrySRID()
+		buf  = []byte{1}
+	)
+
+	if ewkb && srid != 0 {
+		buf = binary.LittleEndian.AppendUint32(buf, typ|ewkbSRID)
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(srid))
+	} else {
+		buf = binary.LittleEndian.AppendUint32(buf, typ)
+	}
+	return s.appendWKB(buf)
+}
+
+// UnmarshalGeometry decodes geometry of WKB, EWKB (binary or hex), MySQL internal format (SRID + WKB) and
+// SQL Server geography serialization format
+func UnmarshalGeometry(data []byte) (Geometry, error) {
+	if len(data) > 0 && data[0] == '0' {
+		decoded := make([]byte, hex.DecodedLen(len(data)))
+		if _, err := hex.Decode(decoded, data); err == nil {
+			data = decoded
+		}
+	}
+
+	reader := &wkbReader{data: data}
+	if shape, err := reader.readShape(0); err == nil && reader.pos == len(data) {
+		return Geometry{Shape: shape}, nil
+	}
+
+	if len(data) > 5 {
+		// mysql internal format, 4 bytes SRID in little endian and WKB
+		srid := int(binary.LittleEndian.Uint32(data[:4]))
+		reader = &wkbReader{data: data[4:]}
+		if shape, err := reader.readShape(srid); err == nil && reader.pos == len(data)-4 {
+			return Geometry{Shape: shape}, nil
+		}
+
+		if data[4] == 1 || data[4] == 2 {
+			return unmarshalSQLServerGeography(data)
+		}
+	}
+	return Geometry{}, fmt.Errorf("invalid geometry of %d bytes", len(data))
+}
+
+type wkbReader struct {
+	data  []byte
+	pos   int
+	order binary.ByteOrder
+}
+
+func (reader *wkbReader) next(n int) ([]byte, error) {
+	if n < 0 || reader.pos+n > len(reader.data) {
+		return nil, errors.New("unexpected end of WKB")
+	}
+	reader.pos += n
+	return reader.data[reader.pos-n : reader.pos], nil
+}
+
+func (reader *wkbReader) uint32() (uint32, error) {
+	b, err := reader.next(4)
+	if err != nil {
+		return 0, err
+	}
+	return reader.order.Uint32(b), nil
+}
+
+func (reader *wkbReader) point() (p Point, err error) {
+	b, err := reader.next(16)
+	if err != nil {
+		return p, err
+	}
+	return Point{X: math.Float64frombits(reader.order.Uint64(b[:8])), Y: math.Float64frombits(reader.order.Uint64(b[8:]))}, nil
+}
+
+func (reader *wkbReader) points() ([]Point, error) {
+	count, err := reader.uint32()
+	if err != nil {
+		return nil, err
+	} else if int(count) > (len(reader.data)-reader.pos)/16 {
+		return nil, errors.New("unexpected end of WKB")
+	}
+
+	points := make([]Point, count)
+	for idx := range points {
+		if points[idx], err = reader.point(); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+// readShape reads the shape of WKB or EWKB, srid is the SRID if EWKB has no SRID
+func (reader *wkbReader) readShape(srid int) (Shape, error) {
+	order, err := reader.next(1)
+	if err != nil {
+		return nil, err
+	}
+
+	switch order[0] {
+	case 0:
+		reader.order = binary.BigEndian
+	case 1:
+		reader.order = binary.LittleEndian
+	default:
+		return nil, fmt.Errorf("invalid WKB byte order %d", order[0])
+	}
+
+	typ, err := reader.uint32()
+	if err != nil {
+		return nil, err
+	}
+
+	if typ&ewkbSRID != 0 {
+		value, err := reader.uint32()
+		if err != nil {
+			return nil, err
+		}
+		srid = int(value)
+	}
+
+	if typ&(ewkbZ|ewkbM) != 0 || typ&0xffff > 1000 {
+		return nil, errors.New("geometry with Z or M coordinates is not supported")
+	}
+
+	switch typ & 0xffff {
+	case wkbPoint:
+		point, err := reader.point()
+		point.SRID = srid
+		return point, err
+	case wkbLineString:
+		points, err := reader.points()
+		return LineString{Points: points, SRID: srid}, err
+	case wkbPolygon:
+		count, err := reader.uint32()
+		if err != nil {
+			return nil, err
+		}
+
+		polygon := Polygon{SRID: srid}
+		for i := uint32(0); i < count; i++ {
+			ring, err := reader.points()
+			if err != nil {
+				return nil, err
+			}
+			polygon.Rings = append(polygon.Rings, ring)
+		}
+		return polygon, nil
+	}
+	return nil, fmt.Errorf("unsupported geometry type %d", typ&0xffff)
+}
+
+// unmarshalSQLServerGeography decodes SQL Server geography serialization format, points are stored as latitude
+// and longitude
+func unmarshalSQLServerGeography(data []byte) (Geometry, error) {
+	const (
+		propZ           = 0x01
+		propM           = 0x02
+		propSinglePoint = 0x08
+		propSingleLine  = 0x10
+	)
+
+	reader := &wkbReader{data: data, order: binary.LittleEndian}
+	srid, _ := reader.uint32()
+	header, err := reader.next(2)
+	if err != nil {
+		return Geometry{}, err
+	}
+
+	props := header[1]
+	if props&(propZ|propM) != 0 {
+		return Geometry{}, errors.New("geography with Z or M coordinates is not supported")
+	}
+
+	readPoint := func() (Point, error) {
+		p, err := reader.point()
+		return Point{X: p.Y, Y: p.X, SRID: int(srid)}, err
+	}
+
+	switch {
+	case props&propSinglePoint != 0:
+		point, err := readPoint()
+		return Geometry{Shape: point}, err
+	case props&propSingleLine != 0:
+		line := LineString{SRID: int(srid), Points: make([]Point, 2)}
+		for idx := range line.Points {
+			if line.Points[idx], err = readPoint(); err != nil {
+				return Geometry{}, err
+			}
+		}
+		return Geometry{Shape: line}, nil
+	}
+
+	numPoints, err := reader.uint32()
+	if err != nil || int(numPoints) > len(data)/16 {
+		return Geometry{}, errors.New("invalid geography")
+	}
+
+	points := make([]Point, numPoints)
+	for idx := range points {
+		if points[idx], err = readPoint(); err != nil {
+			return Geometry{}, err
+		}
+	}
+
+	numFigures, err := reader.uint32()
+	if err != nil || int(numFigures) > len(data)/5 {
+		return Geometry{}, errors.New("invalid geography")
+	}
+
+	figures := make([]int, numFigures+1)
+	for idx := 0; idx < int(numFigures); idx++ {
+		figure, err := reader.next(5)
+		if err != nil {
+			return Geometry{}, err
+		}
+		figures[idx] = int(int32(binary.LittleEndian.Uint32(figure[1:])))
+	}
+	figures[numFigures] = len(points)
+
+	numShapes, err := reader.uint32()
+	if err != nil || numShapes == 0 {
+		return Geometry{}, errors.New("invalid geography")
+	}
+
+	shape, err := reader.next(9)
+	if err != nil {
+		return Geometry{}, err
+	}
+
+	var (
+		figureOffset = int(int32(binary.LittleEndian.Uint32(shape[4:8])))
+		rings        [][]Point
+	)
+
+	for idx := figureOffset; idx >= 0 && idx < int(numFigures); idx++ {
+		if figures[idx] < 0 || figures[idx] > figures[idx+1] {
+			return Geometry{}, errors.New("invalid geography")
+		}
+		rings = append(rings, points[figures[idx]:figures[idx+1]])
+	}
+
+	switch uint32(shape[8]) {
+	case wkbPoint:
+		if len(rings) == 0 || len(rings[0]) == 0 {
+			return Geometry{Shape: Point{SRID: int(srid)}}, nil
+		}
+		return Geometry{Shape: rings[0][0]}, nil
+	case wkbLineString:
+		line := LineString{SRID: int(srid)}
+		if len(rings) > 0 {
+			line.Points = rings[0]
+		}
+		return Geometry{Shape: line}, nil
+	case wkbPolygon:
+		return Geometry{Shape: Polygon{Rings: rings, SRID: int(srid)}}, nil
+	}
+	return Geometry{}, fmt.Errorf("unsupported geography type %d", shape[8])
+}
+
+func scanShape(value interface{}) (Shape, error) {
+	switch v := value.(type) {
+	case []byte:
+		geometry, err := UnmarshalGeometry(v)
+		return geometry.Shape, err
+	case string:
+		geometry, err := UnmarshalGeometry([]byte(v))
+		return geometry.Shape, err
+	}
+	return nil, errors.New(fmt.Sprint("Failed to unmarshal geometry value:", value))
+}
+
+// Scan scan value into Point, implements sql.Scanner interface
+func (p *Point) Scan(value interface{}) error {
+	if value == nil {
+		*p = Point{}
+		return nil
+	}
+
+	shape, err := scanShape(value)
+	if err == nil {
+		if point, ok := shape.(Point); ok {
+			*p = point
+		} else {
+			err = fmt.Errorf("failed to scan %s into Point", shape.GeometryType())
+		}
+	}
+	return err
+}
+
+// Scan scan value into LineString, implements sql.Scanner interface
+func (l *LineString) Scan(value interface{}) error {
+	if value == nil {
+		*l = LineString{}
+		return nil
+	}
+
+	shape, err := scanShape(value)
+	if err == nil {
+		if line, ok := shape.(LineString); ok {
+			*l = line
+		} else {
+			err = fmt.Errorf("failed to scan %s into LineString", shape.GeometryType())
+		}
+	}
+	return err
+}
+
+// Scan scan value into Polygon, implements sql.Scanner interface
+func (p *Polygon) Scan(value interface{}) error {
+	if value == nil {
+		*p = Polygon{}
+		return nil
+	}
+
+	shape, err := scanShape(value)
+	if err == nil {
+		if polygon, ok := shape.(Polygon); ok {
+			*p = polygon
+		} else {
+			err = fmt.Errorf("failed to scan %s into Polygon", shape.GeometryType())
+		}
+	}
+	return err
+}
+
+// Scan scan value into Geometry, implements sql.Scanner interface
+func (g *Geometry) Scan(value interface{}) (err error) {
+	if value == nil {
+		*g = Geometry{}
+		return nil
+	}
+
+	g.Shape, err = scanShape(value)
+	return
+}
+
+// Value return EWKB value, implement driver.Valuer interface
+func (p Point) Value() (driver.Value, error) {
+	return MarshalEWKB(p), nil
+}
+
+// Value return EWKB value, implement driver.Valuer interface
+func (l LineString) Value() (driver.Value, error) {
+	return MarshalEWKB(l), nil
+}
+
+// Value return EWKB value, implement driver.Valuer interface
+func (p Polygon) Value() (driver.Value, error) {
+	return MarshalEWKB(p), nil
+}
+
+// Value return EWKB value, implement driver.Valuer interface
+func (g Geometry) Value() (driver.Value, error) {
+	if g.Shape == nil {
+		return nil, nil
+	}
+	return MarshalEWKB(g.Shape), nil
+}
+
+// GormValue gorm value
+func (p Point) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	return geometryValue(db, p)
+}
+
+// GormValue gorm value
+func (l LineString) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	return geometryValue(db, l)
+}
+
+// GormValue gorm value
+func (p Polygon) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	return geometryValue(db, p)
+}
+
+// GormValue gorm value
+func (g Geometry) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	if g.Shape == nil {
+		return gorm.Expr("NULL")
+	}
+	return geometryValue(db, g.Shape)
+}
+
+// GormDataType gorm common data type
+func (Point) GormDataType() string {
+	return "geometry"
+}
+
+// GormDataType gorm common data type
+func (LineString) GormDataType() string {
+	return "geometry"
+}
+
+// GormDataType gorm common data type
+func (Polygon) GormDataType() string {
+	return "geometry"
+}
+
+// GormDataType gorm common data type
+func (Geometry) GormDataType() string {
+	return "geometry"
+}
+
+// GormDBDataType gorm db data type
+func (Point) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return geometryDBDataType(db, field, "Point")
+}
+
+// GormDBDataType gorm db data type
+func (LineString) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return geometryDBDataType(db, field, "LineString")
+}
+
+// GormDBDataType gorm db data type
+func (Polygon) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return geometryDBDataType(db, field, "Polygon")
+}
+
+// GormDBDataType gorm db data type
+func (Geometry) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return geometryDBDataType(db, field, "Geometry")
+}
+
+// geometryDBDataType db data type of geometry, the SRID is specified with tag, e.g. `gorm:"srid:4326"`
+func geometryDBDataType(db *gorm.DB, field *schema.Field, typ string) string {
+	srid, _ := strconv.Atoi(field.TagSettings["SRID"])
+
+	switch db.Dialector.Name() {
+	case "mysql":
+		if srid != 0 {
+			return fmt.Sprintf("%s SRID %d", strings.ToUpper(typ), srid)
+		}
+		return strings.ToUpper(typ)
+	case "postgres":
+		if srid != 0 {
+			return fmt.Sprintf("geometry(%s,%d)", typ, srid)
+		} else if typ != "Geometry" {
+			return fmt.Sprintf("geometry(%s)", typ)
+		}
+		return "geometry"
+	case "sqlserver":
+		return "geography"
+	case "sqlite":
+		return "BLOB"
+	}
+	return ""
+}
+
+// wkbValue binds WKB as a single value, as slices in parentheses of expressions are expanded as lists
+type wkbValue []byte
+
+// Value implements driver.Valuer interface
+func (v wkbValue) Value() (driver.Value, error) {
+	return []byte(v), nil
+}
+
+// geometryValue returns the expression to construct the geometry in databases
+func geometryValue(db *gorm.DB, s Shape) clause.Expr {
+	srid := s.geometrySRID()
+
+	switch db.Dialector.Name() {
+	case "mysql":
+		if srid == 0 {
+			return gorm.Expr("ST_GeomFromWKB(?)", wkbValue(MarshalWKB(s)))
+		}
+
+		if v, ok := db.Dialector.(*mysql.Dialector); ok && !strings.Contains(v.ServerVersion, "MariaDB") && !strings.HasPrefix(v.ServerVersion, "5.") {
+			// mysql 8 uses latitude-longitude axis order of geographic SRS by default
+			return gorm.Expr("ST_GeomFromWKB(?, ?, 'axis-order=long-lat')", wkbValue(MarshalWKB(s)), srid)
+		}
+		return gorm.Expr("ST_GeomFromWKB(?, ?)", wkbValue(MarshalWKB(s)), srid)
+	case "postgres":
+		return gorm.Expr("ST_GeomFromEWKB(?)", wkbValue(MarshalEWKB(s)))
+	case "sqlserver":
+		if srid == 0 {
+			srid = 4326
+		}
+		return gorm.Expr("geography::STGeomFromWKB(?, ?)", wkbValue(MarshalWKB(s)), srid)
+	}
+	return gorm.Expr("?", wkbValue(MarshalEWKB(s)))
+}
+
+// GeometryQuery query column of geometry types
+//
+//	// ST_DWithin("location", ST_GeomFromEWKB(?), 0.01)
+//	db.Where(datatypes.GeometryQuery("location").DWithin(point, 0.01)).Find(&stores)
+//	// ORDER BY ST_Distance("location", ST_GeomFromEWKB(?))
+//	db.Order(clause.OrderBy{Expression: datatypes.GeometryQuery("location").Distance(point)}).Find(&stores)
+func GeometryQuery(column string) *GeometryExpression {
+	return &GeometryExpression{column: column}
+}
+
+// GeometryExpression geometry query expression, implements clause.Expression interface to use as querier
+type GeometryExpression struct {
+	column   string
+	op       string
+	shape    Shape
+	distance float64
+}
+
+// Contains checks the column contains the shape
+func (expr *GeometryExpression) Contains(s Shape) *GeometryExpression {
+	expr.op = "contains"
+	expr.shape = s
+	return expr
+}
+
+// Intersects checks the bounding boxes of the column and the shape intersect, uses spatial indexes
+func (expr *GeometryExpression) Intersects(s Shape) *GeometryExpression {
+	expr.op = "intersects"
+	expr.shape = s
+	return expr
+}
+
+// DWithin checks the distance between the column and the shape is within the distance, in units of the SRID (meters
+// for SQL Server geography)
+func (expr *GeometryExpression) DWithin(s Shape, distance float64) *GeometryExpression {
+	expr.op = "dwithin"
+	expr.shape = s
+	expr.distance = distance
+	return expr
+}
+
+// Distance the distance between the column and the shape, to select or order by
+func (expr *GeometryExpression) Distance(s Shape) *GeometryExpression {
+	expr.op = "distance"
+	expr.shape = s
+	return expr
+}
+
+// Build implements clause.Expression
+func (expr *GeometryExpression) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok {
+		return
+	}
+
+	switch stmt.Dialector.Name() {
+	case "postgres":
+		builder.WriteString(map[string]string{"contains": "ST_Contains(", "intersects": "", "dwithin": "ST_DWithin(", "distance": "ST_Distance("}[expr.op])
+		builder.WriteQuoted(expr.column)
+		if expr.op == "intersects" {
+			builder.WriteString(" && ")
+			builder.AddVar(stmt, expr.shape)
+			return
+		}
+
+		builder.WriteByte(',')
+		builder.AddVar(stmt, expr.shape)
+		if expr.op == "dwithin" {
+			builder.WriteByte(',')
+			builder.AddVar(stmt, expr.distance)
+		}
+		builder.WriteByte(')')
+	case "mysql":
+		builder.WriteString(map[string]string{"contains": "ST_Contains(", "intersects": "MBRIntersects(", "dwithin": "ST_Distance(", "distance": "ST_Distance("}[expr.op])
+		builder.WriteQuoted(expr.column)
+		builder.WriteByte(',')
+		builder.AddVar(stmt, expr.shape)
+		builder.WriteByte(')')
+		if expr.op == "dwithin" {
+			builder.WriteString(" <= ")
+			builder.AddVar(stmt, expr.distance)
+		}
+	case "sqlserver":
+		builder.WriteQuoted(expr.column)
+		builder.WriteString(map[string]string{"contains": ".STContains(", "intersects": ".Filter(", "dwithin": ".STDistance(", "distance": ".STDistance("}[expr.op])
+		builder.AddVar(stmt, expr.shape)
+		builder.WriteByte(')')
+		switch expr.op {
+		case "contains", "intersects":
+			builder.WriteString(" = 1")
+		case "dwithin":
+			builder.WriteString(" <= ")
+			builder.AddVar(stmt, expr.distance)
+		}
+	default:
+		stmt.AddError(fmt.Errorf("geometry query is not supported by %s", stmt.Dialector.Name()))
+	}
+}
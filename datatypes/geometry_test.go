@@ -0,0 +1,92 @@
+package datatypes_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/datatypes"
+	"github.com/fangxing98/jx-gorm/driver/mysql"
+	"github.com/fangxing98/jx-gorm/gorm"
+	. "github.com/fangxing98/jx-gorm/gorm/utils/tests"
+)
+
+func TestGeometryEncoding(t *testing.T) {
+	point := datatypes.Point{X: 1, Y: 2, SRID: 4326}
+	AssertEqual(t, hex.EncodeToString(datatypes.MarshalWKB(point)), "0101000000000000000000f03f0000000000000040")
+	AssertEqual(t, hex.EncodeToString(datatypes.MarshalEWKB(point)), "0101000020e6100000000000000000f03f0000000000000040")
+
+	// hex EWKB of postgis
+	geometry, err := datatypes.UnmarshalGeometry([]byte("0101000020E6100000000000000000F03F0000000000000040"))
+	if err != nil {
+		t.Fatalf("failed to decode EWKB, got error %v", err)
+	}
+	AssertEqual(t, geometry.Shape, point)
+
+	// mysql internal format, SRID and WKB
+	polygon := datatypes.Polygon{Rings: [][]datatypes.Point{{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 0}}}, SRID: 4326}
+	geometry, err = datatypes.UnmarshalGeometry(append([]byte{0xE6, 0x10, 0, 0}, datatypes.MarshalWKB(polygon)...))
+	if err != nil {
+		t.Fatalf("failed to decode mysql geometry, got error %v", err)
+	}
+	AssertEqual(t, geometry.Shape, polygon)
+
+	// sql server geography point, latitude 2 and longitude 1
+	data, _ := hex.DecodeString("E6100000010C0000000000000040000000000000F03F")
+	var location datatypes.Point
+	if err := location.Scan(data); err != nil {
+		t.Fatalf("failed to scan sql server geography, got error %v", err)
+	}
+	AssertEqual(t, location, point)
+
+	var line datatypes.LineString
+	if err := line.Scan(datatypes.MarshalEWKB(point)); err == nil {
+		t.Errorf("should failed to scan point into line string")
+	}
+
+	if datatypes.MarshalWKB(nil) != nil || datatypes.MarshalEWKB(datatypes.Geometry{}) != nil {
+		t.Errorf("nil shape should be marshaled as nil")
+	}
+	AssertEqual(t, datatypes.MarshalEWKB(datatypes.Geometry{Shape: point}), datatypes.MarshalEWKB(point))
+
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), gorm.DBTypeMySQL, &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("failed to open mysql, got error %v", err)
+	}
+
+	stmt := db.Table("stores").Where("location = ?", datatypes.Point{X: 1, Y: 2}).Find(&[]map[string]interface{}{}).Statement
+	AssertEqual(t, stmt.SQL.String(), "SELECT * FROM `stores` WHERE location = ST_GeomFromWKB(?)")
+	AssertEqual(t, len(stmt.Vars), 1)
+}
+
+func TestGeometry(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres") {
+		type StoreWithGeometry struct {
+			ID       uint
+			Location datatypes.Point `gorm:"srid:4326"`
+			Route    datatypes.LineString
+			Shape    datatypes.Geometry
+		}
+
+		DB.Migrator().DropTable(&StoreWithGeometry{})
+		if err := DB.Migrator().AutoMigrate(&StoreWithGeometry{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		store := StoreWithGeometry{
+			Location: datatypes.Point{X: 116.4, Y: 39.9, SRID: 4326},
+			Route:    datatypes.LineString{Points: []datatypes.Point{{X: 1, Y: 1}, {X: 2, Y: 2}}},
+			Shape:    datatypes.Geometry{Shape: datatypes.Point{X: 3, Y: 4}},
+		}
+		if err := DB.Create(&store).Error; err != nil {
+			t.Errorf("Failed to create store %v", err)
+		}
+
+		var result StoreWithGeometry
+		if err := DB.First(&result, store.ID).Error; err != nil {
+			t.Fatalf("failed to find store with geometry, got error %v", err)
+		}
+		AssertEqual(t, result.Location, store.Location)
+		AssertEqual(t, result.Route, store.Route)
+		AssertEqual(t, result.Shape, store.Shape)
+	}
+}
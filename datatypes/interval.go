@@ -0,0 +1,147 @@
+package datatypes
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/clause"
+	"github.com/fangxing98/jx-gorm/gorm/schema"
+)
+
+// Interval duration stored as postgres interval, and as seconds in other databases
+type Interval time.Duration
+
+// Value return seconds, implement driver.Valuer interface, postgres interval is used through GormValue
+func (i Interval) Value() (driver.Value, error) {
+	return int64(time.Duration(i) / time.Second), nil
+}
+
+// Scan scan value into Interval, implements sql.Scanner interface, supports seconds and postgres interval of
+// postgres style, e.g. "1 year 2 mons 3 days 04:05:06.5"
+func (i *Interval) Scan(value interface{}) (err error) {
+	switch v := value.(type) {
+	case nil:
+		*i = 0
+	case int64:
+		*i = Interval(time.Duration(v) * time.Second)
+	case float64:
+		*i = Interval(time.Duration(math.Round(v * float64(time.Second))))
+	case []byte:
+		*i, err = parseInterval(string(v))
+	case string:
+		*i, err = parseInterval(v)
+	default:
+		err = errors.New(fmt.Sprint("Failed to scan interval value:", value))
+	}
+	return
+}
+
+// GormDataType gorm common data type
+func (Interval) GormDataType() string {
+	return "interval"
+}
+
+// GormDBDataType gorm db data type
+func (Interval) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return "interval"
+	case "mysql", "sqlite", "sqlserver":
+		return "bigint"
+	}
+	return ""
+}
+
+// GormValue gorm value, postgres interval for postgres, seconds for other databases
+func (i Interval) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	if db.Dialector.Name() == "postgres" {
+		return gorm.Expr("?", strconv.FormatInt(time.Duration(i).Microseconds(), 10)+" microseconds")
+	}
+	return gorm.Expr("?", int64(time.Duration(i)/time.Second))
+}
+
+// Duration returns the time.Duration of the interval
+func (i Interval) Duration() time.Duration {
+	return time.Duration(i)
+}
+
+func (i Interval) String() string {
+	return time.Duration(i).String()
+}
+
+// interval units of postgres, a month is 30 days and a year is 365.25 days like extract(epoch from interval)
+var intervalUnits = map[string]time.Duration{
+	"year": 8766 * time.Hour, "years": 8766 * time.Hour,
+	"mon": 720 * time.Hour, "mons": 720 * time.Hour,
+	"day": 24 * time.Hour, "days": 24 * time.Hour,
+}
+
+// parseInterval parses seconds or postgres interval of postgres style
+func parseInterval(str string) (Interval, error) {
+	if seconds, err := strconv.ParseFloat(str, 64); err == nil {
+		return Interval(time.Duration(math.Round(seconds * float64(time.Second)))), nil
+	}
+
+	var (
+		duration time.Duration
+		fields   = strings.Fields(str)
+	)
+
+	for idx := 0; idx < len(fields); idx++ {
+		if field := fields[idx]; strings.Contains(field, ":") {
+			clock, err := parseIntervalClock(field)
+			if err != nil {
+				return 0, err
+			}
+			duration += clock
+		} else if idx+1 < len(fields) {
+			n, err := strconv.ParseInt(field, 10, 64)
+			unit, ok := intervalUnits[fields[idx+1]]
+			if err != nil || !ok {
+				return 0, fmt.Errorf("invalid interval %q", str)
+			}
+			duration += time.Duration(n) * unit
+			idx++
+		} else {
+			return 0, fmt.Errorf("invalid interval %q", str)
+		}
+	}
+	return Interval(duration), nil
+}
+
+// parseIntervalClock parses the time part of interval, e.g. 04:05:06.5, -00:00:01
+func parseIntervalClock(str string) (time.Duration, error) {
+	sign := time.Duration(1)
+	if strings.HasPrefix(str, "-") {
+		sign, str = -1, str[1:]
+	} else {
+		str = strings.TrimPrefix(str, "+")
+	}
+
+	parts := strings.Split(str, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid interval time %q", str)
+	}
+
+	hours, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	minutes, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return sign * (time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(math.Round(seconds*float64(time.Second)))), nil
+}
@@ -0,0 +1,51 @@
+package datatypes_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/datatypes"
+	. "github.com/fangxing98/jx-gorm/gorm/utils/tests"
+)
+
+func TestIntervalScan(t *testing.T) {
+	tests := map[interface{}]time.Duration{
+		"1 year 2 mons 3 days 04:05:06.5": 8766*time.Hour + 60*24*time.Hour + 3*24*time.Hour + 4*time.Hour + 5*time.Minute + 6500*time.Millisecond,
+		"-00:00:01":                       -time.Second,
+		int64(3600):                       time.Hour,
+		"90":                              90 * time.Second,
+	}
+
+	for value, expected := range tests {
+		var interval datatypes.Interval
+		if err := interval.Scan(value); err != nil {
+			t.Fatalf("failed to scan interval %v, got error %v", value, err)
+		}
+		AssertEqual(t, interval.Duration(), expected)
+	}
+}
+
+func TestInterval(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
+		type Plan struct {
+			ID       uint
+			Duration datatypes.Interval
+		}
+
+		DB.Migrator().DropTable(&Plan{})
+		if err := DB.Migrator().AutoMigrate(&Plan{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		plan := Plan{Duration: datatypes.Interval(90 * time.Minute)}
+		if err := DB.Create(&plan).Error; err != nil {
+			t.Errorf("Failed to create plan %v", err)
+		}
+
+		var result Plan
+		if err := DB.First(&result, plan.ID).Error; err != nil {
+			t.Fatalf("failed to find plan with interval, got error %v", err)
+		}
+		AssertEqual(t, result.Duration, plan.Duration)
+	}
+}
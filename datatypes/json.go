@@ -82,6 +82,8 @@ func (JSON) GormDBDataType(db *gorm.DB, field *schema.Field) string {
 		return "JSON"
 	case "postgres":
 		return "JSONB"
+	case "sqlserver":
+		return "nvarchar(max)"
 	}
 	return ""
 }
@@ -153,7 +155,7 @@ func (jsonQuery *JSONQueryExpression) Likes(value interface{}, keys ...string) *
 // Build implements clause.Expression
 func (jsonQuery *JSONQueryExpression) Build(builder clause.Builder) {
 	if stmt, ok := builder.(*gorm.Statement); ok {
-		switch stmt.Dialector.Name() {
+		switch dialect := jsonDialect(stmt); dialect {
 		case "mysql", "sqlite":
 			switch {
 			case jsonQuery.extract:
@@ -252,6 +254,84 @@ func (jsonQuery *JSONQueryExpression) Build(builder clause.Builder) {
 					}
 				}
 			}
+		case "kingbase":
+			switch {
+			case jsonQuery.extract:
+				builder.WriteString(stmt.Quote(jsonQuery.column) + "::jsonb ->> ")
+				stmt.AddVar(builder, jsonQuery.path)
+			case jsonQuery.hasKeys:
+				if len(jsonQuery.keys) > 0 {
+					stmt.WriteQuoted(jsonQuery.column)
+					stmt.WriteString("::jsonb")
+					if len(jsonQuery.keys) > 1 {
+						stmt.WriteString(" #> ")
+						stmt.AddVar(builder, jsonbPath(jsonQuery.keys[0:len(jsonQuery.keys)-1]))
+					}
+					stmt.WriteString(" ? ")
+					stmt.AddVar(builder, jsonQuery.keys[len(jsonQuery.keys)-1])
+				}
+			case jsonQuery.equals, jsonQuery.likes:
+				if len(jsonQuery.keys) > 0 {
+					builder.WriteString(stmt.Quote(jsonQuery.column) + "::jsonb #>> ")
+					stmt.AddVar(builder, jsonbPath(jsonQuery.keys))
+					if jsonQuery.equals {
+						builder.WriteString(" = ")
+					} else {
+						builder.WriteString(" LIKE ")
+					}
+
+					if _, ok := jsonQuery.equalsValue.(string); ok {
+						stmt.AddVar(builder, jsonQuery.equalsValue)
+					} else {
+						stmt.AddVar(builder, fmt.Sprint(jsonQuery.equalsValue))
+					}
+				}
+			}
+		case "sqlserver":
+			switch {
+			case jsonQuery.extract:
+				builder.WriteString("COALESCE(JSON_QUERY(")
+				builder.WriteQuoted(jsonQuery.column)
+				builder.WriteByte(',')
+				builder.AddVar(stmt, prefix+jsonQuery.path)
+				builder.WriteString("),JSON_VALUE(")
+				builder.WriteQuoted(jsonQuery.column)
+				builder.WriteByte(',')
+				builder.AddVar(stmt, prefix+jsonQuery.path)
+				builder.WriteString("))")
+			case jsonQuery.hasKeys:
+				if len(jsonQuery.keys) > 0 {
+					builder.WriteString("(JSON_VALUE(")
+					builder.WriteQuoted(jsonQuery.column)
+					builder.WriteByte(',')
+					builder.AddVar(stmt, jsonQueryJoin(jsonQuery.keys))
+					builder.WriteString(") IS NOT NULL OR JSON_QUERY(")
+					builder.WriteQuoted(jsonQuery.column)
+					builder.WriteByte(',')
+					builder.AddVar(stmt, jsonQueryJoin(jsonQuery.keys))
+					builder.WriteString(") IS NOT NULL)")
+				}
+			case jsonQuery.equals, jsonQuery.likes:
+				if len(jsonQuery.keys) > 0 {
+					builder.WriteString("JSON_VALUE(")
+					builder.WriteQuoted(jsonQuery.column)
+					builder.WriteByte(',')
+					builder.AddVar(stmt, jsonQueryJoin(jsonQuery.keys))
+					if jsonQuery.equals {
+						builder.WriteString(") = ")
+					} else {
+						builder.WriteString(") LIKE ")
+					}
+
+					if value, ok := jsonQuery.equalsValue.(bool); ok {
+						stmt.AddVar(builder, strconv.FormatBool(value))
+					} else {
+						stmt.AddVar(builder, jsonQuery.equalsValue)
+					}
+				}
+			}
+		default:
+			stmt.AddError(fmt.Errorf("json query is not supported by %s", dialect))
 		}
 	}
 }
@@ -274,13 +354,15 @@ func JSONOverlaps(column clause.Expression, value string) *JSONOverlapsExpressio
 // only mysql support JSON_OVERLAPS
 func (json *JSONOverlapsExpression) Build(builder clause.Builder) {
 	if stmt, ok := builder.(*gorm.Statement); ok {
-		switch stmt.Dialector.Name() {
+		switch dialect := jsonDialect(stmt); dialect {
 		case "mysql":
 			builder.WriteString("JSON_OVERLAPS(")
 			json.column.Build(builder)
 			builder.WriteString(",")
 			builder.AddVar(stmt, json.val)
 			builder.WriteString(")")
+		default:
+			stmt.AddError(fmt.Errorf("json overlaps is not supported by %s", dialect))
 		}
 	}
 }
@@ -293,15 +375,36 @@ func Column(col string) columnExpression {
 
 func (col columnExpression) Build(builder clause.Builder) {
 	if stmt, ok := builder.(*gorm.Statement); ok {
-		switch stmt.Dialector.Name() {
-		case "mysql", "sqlite", "postgres":
+		switch dialect := jsonDialect(stmt); dialect {
+		case "mysql", "sqlite", "postgres", "kingbase", "sqlserver":
 			builder.WriteString(stmt.Quote(string(col)))
+		default:
+			stmt.AddError(fmt.Errorf("json column is not supported by %s", dialect))
 		}
 	}
 }
 
 const prefix = "$."
 
+// jsonDialect returns the dialect of json functions, KingBase shares the postgres dialector but uses jsonb operators
+func jsonDialect(stmt *gorm.Statement) string {
+	if stmt.DB != nil && stmt.DB.DBType == gorm.DBTypeKingBase {
+		return "kingbase"
+	}
+	return stmt.Dialector.Name()
+}
+
+// jsonbValue returns value marshaled as jsonb
+func jsonbValue(value interface{}) clause.Expr {
+	b, _ := json.Marshal(value)
+	return gorm.Expr("?::jsonb", string(b))
+}
+
+// jsonbPath returns text array path of jsonb #> and #>> operators, e.g. {orgs,orga}
+func jsonbPath(keys []string) string {
+	return "{" + strings.Join(keys, ",") + "}"
+}
+
 func jsonQueryJoin(keys []string) string {
 	if len(keys) == 1 {
 		return prefix + keys[0]
@@ -358,10 +461,10 @@ func (jsonSet *JSONSetExpression) Set(path string, value interface{}) *JSONSetEx
 }
 
 // Build implements clause.Expression
-// support mysql, sqlite and postgres
+// support mysql, sqlite, postgres, kingbase and sqlserver
 func (jsonSet *JSONSetExpression) Build(builder clause.Builder) {
 	if stmt, ok := builder.(*gorm.Statement); ok {
-		switch stmt.Dialector.Name() {
+		switch dialect := jsonDialect(stmt); dialect {
 		case "mysql":
 
 			var isMariaDB bool
@@ -428,7 +531,7 @@ func (jsonSet *JSONSetExpression) Build(builder clause.Builder) {
 			}
 			builder.WriteString(")")
 
-		case "postgres":
+		case "postgres", "kingbase":
 			var expr clause.Expression = columnExpression(jsonSet.column)
 			for path, value := range jsonSet.path2value {
 				if _, ok = value.(clause.Expression); ok {
@@ -440,6 +543,41 @@ func (jsonSet *JSONSetExpression) Build(builder clause.Builder) {
 				}
 			}
 			stmt.AddVar(builder, expr)
+
+		case "sqlserver":
+			builder.WriteString(strings.Repeat("JSON_MODIFY(", len(jsonSet.path2value)))
+			builder.WriteQuoted(jsonSet.column)
+			for path, value := range jsonSet.path2value {
+				builder.WriteByte(',')
+				builder.AddVar(stmt, prefix+path)
+				builder.WriteByte(',')
+
+				if _, ok := value.(clause.Expression); ok {
+					stmt.AddVar(builder, value)
+				} else {
+					rv := reflect.ValueOf(value)
+					if rv.Kind() == reflect.Ptr {
+						rv = rv.Elem()
+					}
+					switch rv.Kind() {
+					case reflect.Slice, reflect.Array, reflect.Struct, reflect.Map:
+						b, _ := json.Marshal(value)
+						stmt.AddVar(builder, gorm.Expr("JSON_QUERY(?)", string(b)))
+					case reflect.Bool:
+						if rv.Bool() {
+							builder.WriteString("CAST(1 AS BIT)")
+						} else {
+							builder.WriteString("CAST(0 AS BIT)")
+						}
+					default:
+						stmt.AddVar(builder, value)
+					}
+				}
+				builder.WriteByte(')')
+			}
+
+		default:
+			stmt.AddError(fmt.Errorf("json set is not supported by %s", dialect))
 		}
 	}
 }
@@ -458,7 +596,7 @@ type JSONArrayExpression struct {
 	equalsValue interface{}
 }
 
-// Contains checks if column[keys] contains the value given. The keys parameter is only supported for MySQL and SQLite.
+// Contains checks if column[keys] contains the value given. The keys parameter is only supported for MySQL, SQLite, SQLServer and KingBase.
 func (json *JSONArrayExpression) Contains(value interface{}, keys ...string) *JSONArrayExpression {
 	json.contains = true
 	json.equalsValue = value
@@ -466,7 +604,7 @@ func (json *JSONArrayExpression) Contains(value interface{}, keys ...string) *JS
 	return json
 }
 
-// In checks if columns[keys] is in the array value given. This method is only supported for MySQL and SQLite.
+// In checks if columns[keys] is in the array value given.
 func (json *JSONArrayExpression) In(value interface{}, keys ...string) *JSONArrayExpression {
 	json.in = true
 	json.keys = keys
@@ -477,7 +615,7 @@ func (json *JSONArrayExpression) In(value interface{}, keys ...string) *JSONArra
 // Build implements clause.Expression
 func (json *JSONArrayExpression) Build(builder clause.Builder) {
 	if stmt, ok := builder.(*gorm.Statement); ok {
-		switch stmt.Dialector.Name() {
+		switch dialect := jsonDialect(stmt); dialect {
 		case "mysql":
 			switch {
 			case json.contains:
@@ -551,13 +689,66 @@ func (json *JSONArrayExpression) Build(builder clause.Builder) {
 				builder.AddVar(stmt, json.equalsValue)
 				builder.WriteString(" END")
 			}
-		case "postgres":
+		case "postgres", "kingbase":
 			switch {
-			case json.contains:
+			case json.contains && dialect == "postgres":
 				builder.WriteString(stmt.Quote(json.column))
 				builder.WriteString(" ? ")
 				builder.AddVar(stmt, json.equalsValue)
+			case json.contains:
+				json.writeJSONB(stmt)
+				builder.WriteString(" @> ")
+				builder.AddVar(stmt, jsonbValue([]interface{}{json.equalsValue}))
+			case json.in:
+				json.writeJSONB(stmt)
+				builder.WriteString(" <@ ")
+				builder.AddVar(stmt, jsonbValue(json.equalsValue))
+			}
+		case "sqlserver":
+			path := "$"
+			if len(json.keys) > 0 {
+				path = jsonQueryJoin(json.keys)
 			}
+
+			switch {
+			case json.contains:
+				builder.WriteString("EXISTS(SELECT 1 FROM OPENJSON(")
+				builder.WriteQuoted(json.column)
+				builder.WriteByte(',')
+				builder.AddVar(stmt, path)
+				builder.WriteString(") WHERE [value] = ")
+				builder.AddVar(stmt, json.equalsValue)
+				builder.WriteByte(')')
+			case json.in:
+				builder.WriteString("((JSON_QUERY(")
+				builder.WriteQuoted(json.column)
+				builder.WriteByte(',')
+				builder.AddVar(stmt, path)
+				builder.WriteString(") IS NOT NULL AND NOT EXISTS(SELECT 1 FROM OPENJSON(")
+				builder.WriteQuoted(json.column)
+				builder.WriteByte(',')
+				builder.AddVar(stmt, path)
+				builder.WriteString(") WHERE [value] NOT IN ")
+				builder.AddVar(stmt, json.equalsValue)
+				builder.WriteString(")) OR JSON_VALUE(")
+				builder.WriteQuoted(json.column)
+				builder.WriteByte(',')
+				builder.AddVar(stmt, path)
+				builder.WriteString(") IN ")
+				builder.AddVar(stmt, json.equalsValue)
+				builder.WriteByte(')')
+			}
+		default:
+			stmt.AddError(fmt.Errorf("json array query is not supported by %s", dialect))
 		}
 	}
 }
+
+// writeJSONB writes column[keys] as jsonb
+func (json *JSONArrayExpression) writeJSONB(stmt *gorm.Statement) {
+	stmt.WriteString(stmt.Quote(json.column) + "::jsonb")
+	if len(json.keys) > 0 {
+		stmt.WriteString(" #> ")
+		stmt.AddVar(stmt, jsonbPath(json.keys))
+	}
+}
@@ -73,6 +73,8 @@ func (JSONType[T]) GormDBDataType(db *gorm.DB, field *schema.Field) string {
 		return "JSON"
 	case "postgres":
 		return "JSONB"
+	case "sqlserver":
+		return "nvarchar(max)"
 	}
 	return ""
 }
@@ -130,6 +132,8 @@ func (JSONSlice[T]) GormDBDataType(db *gorm.DB, field *schema.Field) string {
 		return "JSON"
 	case "postgres":
 		return "JSONB"
+	case "sqlserver":
+		return "nvarchar(max)"
 	}
 	return ""
 }
@@ -0,0 +1,663 @@
+package datatypes
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/clause"
+	"github.com/fangxing98/jx-gorm/gorm/schema"
+)
+
+// RangeValue bound types of Range
+type RangeValue interface {
+	~int | ~int32 | ~int64 | Decimal | time.Time | Date
+}
+
+// Range give a generic data type for postgres range types (int4range, int8range, numrange, tsrange, tstzrange,
+// daterange), stored as json object with the bounds in other databases
+//
+//	type Booking struct {
+//		ID     uint
+//		Period datatypes.Range[time.Time]
+//		// tsrange instead of tstzrange
+//		Local  datatypes.Range[time.Time] `gorm:"type:tsrange"`
+//	}
+type Range[T RangeValue] struct {
+	Lower          T
+	Upper          T
+	LowerInclusive bool
+	UpperInclusive bool
+	// LowerInfinite unbounded lower, Lower is ignored
+	LowerInfinite bool
+	// UpperInfinite unbounded upper, Upper is ignored
+	UpperInfinite bool
+	Empty         bool
+}
+
+// NewRange returns the range [lower, upper)
+func NewRange[T RangeValue](lower, upper T) Range[T] {
+	return Range[T]{Lower: lower, Upper: upper, LowerInclusive: true}
+}
+
+// rangeBounds bounds of range, values are nil if infinite
+type rangeBounds struct {
+	lower, upper       interface{}
+	lowerInc, upperInc bool
+	empty              bool
+}
+
+type rangeInterface interface {
+	rangeBounds() rangeBounds
+}
+
+// rangeBounds returns the canonical bounds, the bounds of discrete ranges (integer, date) are [), like postgres
+func (r Range[T]) rangeBounds() rangeBounds {
+	if r.Empty {
+		return rangeBounds{empty: true}
+	}
+
+	bounds := rangeBounds{lowerInc: r.LowerInclusive && !r.LowerInfinite, upperInc: r.UpperInclusive && !r.UpperInfinite}
+	if !r.LowerInfinite {
+		bounds.lower = r.Lower
+		if !bounds.lowerInc {
+			if next, ok := rangeNext(bounds.lower); ok {
+				bounds.lower, bounds.lowerInc = next, true
+			}
+		}
+	}
+
+	if !r.UpperInfinite {
+		bounds.upper = r.Upper
+		if bounds.upperInc {
+			if next, ok := rangeNext(bounds.upper); ok {
+				bounds.upper, bounds.upperInc = next, false
+			}
+		}
+	}
+
+	if bounds.lower != nil && bounds.upper != nil {
+		if cmp := rangeCompare(bounds.lower, bounds.upper); cmp > 0 || (cmp == 0 && !(bounds.lowerInc && bounds.upperInc)) {
+			return rangeBounds{empty: true}
+		}
+	}
+	return bounds
+}
+
+// Value return postgres range literal, implement driver.Valuer interface
+func (r Range[T]) Value() (driver.Value, error) {
+	return r.rangeBounds().literal(), nil
+}
+
+// Scan scan value into Range[T], implements sql.Scanner interface, supports postgres range literal and json object
+func (r *Range[T]) Scan(value interface{}) error {
+	var str string
+	switch v := value.(type) {
+	case nil:
+		*r = Range[T]{}
+		return nil
+	case []byte:
+		str = string(v)
+	case string:
+		str = v
+	default:
+		return errors.New(fmt.Sprint("Failed to unmarshal range value:", value))
+	}
+
+	if str = strings.TrimSpace(str); strings.HasPrefix(str, "{") {
+		return r.scanJSON(str)
+	} else if strings.EqualFold(str, "empty") {
+		*r = Range[T]{Empty: true}
+		return nil
+	}
+
+	if len(str) < 3 || (str[0] != '[' && str[0] != '(') || (str[len(str)-1] != ']' && str[len(str)-1] != ')') {
+		return fmt.Errorf("invalid range %q", str)
+	}
+
+	bounds, err := splitRangeBounds(str[1 : len(str)-1])
+	if err != nil {
+		return err
+	}
+
+	result := Range[T]{LowerInclusive: str[0] == '[', UpperInclusive: str[len(str)-1] == ']'}
+	if result.LowerInfinite, err = parseRangeBound(bounds[0], &result.Lower); err != nil {
+		return err
+	}
+	if result.UpperInfinite, err = parseRangeBound(bounds[1], &result.Upper); err != nil {
+		return err
+	}
+	*r = result
+	return nil
+}
+
+func (r *Range[T]) scanJSON(str string) error {
+	var data struct {
+		Lower    *json.RawMessage `json:"lower"`
+		Upper    *json.RawMessage `json:"upper"`
+		LowerInc int              `json:"lower_inc"`
+		UpperInc int              `json:"upper_inc"`
+		Empty    int              `json:"empty"`
+	}
+
+	if err := json.Unmarshal([]byte(str), &data); err != nil {
+		return err
+	}
+
+	result := Range[T]{LowerInclusive: data.LowerInc == 1, UpperInclusive: data.UpperInc == 1, Empty: data.Empty == 1}
+	for _, bound := range []struct {
+		raw      *json.RawMessage
+		value    *T
+		infinite *bool
+	}{{data.Lower, &result.Lower, &result.LowerInfinite}, {data.Upper, &result.Upper, &result.UpperInfinite}} {
+		if bound.raw == nil {
+			*bound.infinite = !result.Empty
+			continue
+		}
+
+		text := string(*bound.raw)
+		if unquoted, err := strconv.Unquote(text); err == nil {
+			text = unquoted
+		}
+
+		if _, err := parseRangeBound(text, bound.value); err != nil {
+			return err
+		}
+	}
+	*r = result
+	return nil
+}
+
+// GormDataType gorm common data type
+func (Range[T]) GormDataType() string {
+	return "range"
+}
+
+// GormDBDataType gorm db data type
+func (Range[T]) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "postgres":
+		// range types specified with tag, e.g. `gorm:"type:tsrange"`
+		if dataType := strings.ToLower(string(field.DataType)); dataType != "range" && strings.HasSuffix(dataType, "range") {
+			return string(field.DataType)
+		}
+
+		switch any(*new(T)).(type) {
+		case Decimal:
+			return "numrange"
+		case time.Time:
+			return "tstzrange"
+		case Date:
+			return "daterange"
+		}
+
+		if reflect.TypeOf(*new(T)).Kind() == reflect.Int32 {
+			return "int4range"
+		}
+		return "int8range"
+	case "mysql":
+		return "JSON"
+	case "sqlite":
+		return "JSON"
+	case "sqlserver":
+		return "NVARCHAR(MAX)"
+	}
+	return ""
+}
+
+// GormValue gorm value, postgres range literal for postgres, json object for other databases
+func (r Range[T]) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	bounds := r.rangeBounds()
+	if db.Dialector.Name() == "postgres" {
+		return gorm.Expr("?", bounds.literal())
+	}
+
+	data, err := bounds.json()
+	if err != nil {
+		db.AddError(err)
+	}
+	return gorm.Expr("?", string(data))
+}
+
+// literal returns postgres range literal, e.g. [1,5), ["2024-01-01 00:00:00Z",)
+func (bounds rangeBounds) literal() string {
+	if bounds.empty {
+		return "empty"
+	}
+
+	var builder strings.Builder
+	if bounds.lowerInc {
+		builder.WriteByte('[')
+	} else {
+		builder.WriteByte('(')
+	}
+
+	for idx, value := range []interface{}{bounds.lower, bounds.upper} {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+
+		if value != nil {
+			builder.WriteString(strconv.Quote(rangeBoundText(value)))
+		}
+	}
+
+	if bounds.upperInc {
+		builder.WriteByte(']')
+	} else {
+		builder.WriteByte(')')
+	}
+	return builder.String()
+}
+
+// json returns the json object of the bounds, infinite bounds are omitted
+func (bounds rangeBounds) json() ([]byte, error) {
+	if bounds.empty {
+		return []byte(`{"empty":1}`), nil
+	}
+
+	data := map[string]interface{}{"lower_inc": 0, "upper_inc": 0}
+	if bounds.lower != nil {
+		data["lower"] = rangeJSONValue(bounds.lower)
+	}
+	if bounds.upper != nil {
+		data["upper"] = rangeJSONValue(bounds.upper)
+	}
+	if bounds.lowerInc {
+		data["lower_inc"] = 1
+	}
+	if bounds.upperInc {
+		data["upper_inc"] = 1
+	}
+	return json.Marshal(data)
+}
+
+// rangeJSONTimeLayout time layout of json stored ranges, fixed width in UTC to compare as strings
+const rangeJSONTimeLayout = "2006-01-02 15:04:05.000000"
+
+func rangeBoundText(value interface{}) string {
+	switch v := value.(type) {
+	case Decimal:
+		return v.String()
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05.999999999Z07:00")
+	case Date:
+		return time.Time(v).Format("2006-01-02")
+	}
+	return strconv.FormatInt(reflect.ValueOf(value).Int(), 10)
+}
+
+func rangeJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case Decimal:
+		return json.Number(v.String())
+	case time.Time:
+		return v.UTC().Format(rangeJSONTimeLayout)
+	case Date:
+		return time.Time(v).Format("2006-01-02")
+	}
+	return reflect.ValueOf(value).Int()
+}
+
+// rangeVar returns the value to compare with the bounds in the database
+func rangeVar(value interface{}, dialect string) interface{} {
+	if dialect == "postgres" {
+		switch v := value.(type) {
+		case time.Time:
+			return v
+		case Decimal, Date:
+			return rangeBoundText(v)
+		}
+		return reflect.ValueOf(value).Int()
+	}
+
+	if v, ok := value.(Decimal); ok {
+		return v.Float64()
+	}
+	return rangeJSONValue(value)
+}
+
+// rangeNext returns the next value of discrete types
+func rangeNext(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case Date:
+		return Date(time.Time(v).AddDate(0, 0, 1)), true
+	case Decimal, time.Time:
+		return nil, false
+	}
+
+	rv := reflect.New(reflect.TypeOf(value)).Elem()
+	rv.SetInt(reflect.ValueOf(value).Int() + 1)
+	return rv.Interface(), true
+}
+
+func rangeCompare(a, b interface{}) int {
+	switch v := a.(type) {
+	case Decimal:
+		return v.Cmp(b.(Decimal))
+	case time.Time:
+		return v.Compare(b.(time.Time))
+	case Date:
+		return time.Time(v).Compare(time.Time(b.(Date)))
+	}
+
+	if x, y := reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int(); x < y {
+		return -1
+	} else if x > y {
+		return 1
+	}
+	return 0
+}
+
+// splitRangeBounds splits the bounds of range literal, quoted bounds are unquoted
+func splitRangeBounds(str string) ([2]string, error) {
+	var (
+		bounds [2]string
+		idx    int
+		quoted bool
+		buf    strings.Builder
+	)
+
+	for i := 0; i < len(str); i++ {
+		switch c := str[i]; {
+		case c == '"':
+			quoted = !quoted
+		case c == '\\' && i+1 < len(str):
+			i++
+			buf.WriteByte(str[i])
+		case c == ',' && !quoted:
+			if idx > 0 {
+				return bounds, fmt.Errorf("invalid range bounds %q", str)
+			}
+			bounds[idx] = buf.String()
+			idx++
+			buf.Reset()
+		default:
+			buf.WriteByte(c)
+		}
+	}
+
+	if idx != 1 {
+		return bounds, fmt.Errorf("invalid range bounds %q", str)
+	}
+	bounds[1] = buf.String()
+	return bounds, nil
+}
+
+// parseRangeBound parses the bound text into dest, returns true if the bound is infinite
+func parseRangeBound[T RangeValue](text string, dest *T) (infinite bool, err error) {
+	if text == "" || text == "infinity" || text == "-infinity" {
+		return true, nil
+	}
+
+	switch d := any(dest).(type) {
+	case *Decimal:
+		*d, err = ParseDecimal(text)
+	case *time.Time:
+		*d, err = parseRangeTime(text)
+	case *Date:
+		var t time.Time
+		t, err = time.Parse("2006-01-02", text)
+		*d = Date(t)
+	default:
+		var i int64
+		if i, err = strconv.ParseInt(text, 10, 64); err == nil {
+			reflect.ValueOf(dest).Elem().SetInt(i)
+		}
+	}
+	return false, err
+}
+
+func parseRangeTime(text string) (t time.Time, err error) {
+	for _, layout := range []string{"2006-01-02 15:04:05.999999999Z07:00", "2006-01-02 15:04:05.999999999Z07", "2006-01-02 15:04:05.999999999", time.RFC3339Nano} {
+		if t, err = time.Parse(layout, text); err == nil {
+			return t, nil
+		}
+	}
+	return t, fmt.Errorf("invalid range bound %q", text)
+}
+
+// RangeQuery query column of Range
+//
+//	// period @> '["2024-01-01 10:00:00Z","2024-01-01 10:00:00Z"]'
+//	db.Where(datatypes.RangeQuery("period").Contains(time.Now())).Find(&bookings)
+//	// period && '["2024-01-01 00:00:00Z","2024-01-02 00:00:00Z")'
+//	db.Where(datatypes.RangeQuery("period").Overlaps(datatypes.NewRange(start, end))).Find(&bookings)
+func RangeQuery(column string) *RangeExpression {
+	return &RangeExpression{column: column}
+}
+
+// RangeExpression range query expression, implements clause.Expression interface to use as querier
+type RangeExpression struct {
+	column string
+	op     string
+	value  interface{}
+	bounds rangeBounds
+}
+
+// Contains checks the column contains the value
+func (expr *RangeExpression) Contains(value interface{}) *RangeExpression {
+	expr.op = "contains"
+	expr.value = value
+	return expr
+}
+
+// ContainsRange checks the column contains the range
+func (expr *RangeExpression) ContainsRange(r rangeInterface) *RangeExpression {
+	expr.op = "contains_range"
+	expr.bounds = r.rangeBounds()
+	return expr
+}
+
+// Overlaps checks the column and the range have points in common
+func (expr *RangeExpression) Overlaps(r rangeInterface) *RangeExpression {
+	expr.op = "overlaps"
+	expr.bounds = r.rangeBounds()
+	return expr
+}
+
+// Adjacent checks the column and the range are adjacent
+func (expr *RangeExpression) Adjacent(r rangeInterface) *RangeExpression {
+	expr.op = "adjacent"
+	expr.bounds = r.rangeBounds()
+	return expr
+}
+
+// Lower compares the lower bound of the column with the operator, e.g. =, <>, >, >=, <, <=
+func (expr *RangeExpression) Lower(op string, value interface{}) *RangeExpression {
+	expr.op = "lower " + op
+	expr.value = value
+	return expr
+}
+
+// Upper compares the upper bound of the column with the operator, e.g. =, <>, >, >=, <, <=
+func (expr *RangeExpression) Upper(op string, value interface{}) *RangeExpression {
+	expr.op = "upper " + op
+	expr.value = value
+	return expr
+}
+
+// Build implements clause.Expression
+func (expr *RangeExpression) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok {
+		return
+	}
+
+	dialect := stmt.Dialector.Name()
+	op, compare, _ := strings.Cut(expr.op, " ")
+	if op == "lower" || op == "upper" {
+		switch compare {
+		case "=", "<>", "!=", ">", ">=", "<", "<=":
+		default:
+			stmt.AddError(fmt.Errorf("invalid range bound operator %q", compare))
+			return
+		}
+	}
+
+	switch dialect {
+	case "postgres":
+		switch op {
+		case "contains":
+			builder.WriteQuoted(expr.column)
+			builder.WriteString(" @> ")
+			builder.AddVar(stmt, rangeBounds{lower: expr.value, upper: expr.value, lowerInc: true, upperInc: true}.literal())
+		case "contains_range", "overlaps", "adjacent":
+			builder.WriteQuoted(expr.column)
+			builder.WriteString(map[string]string{"contains_range": " @> ", "overlaps": " && ", "adjacent": " -|- "}[op])
+			builder.AddVar(stmt, expr.bounds.literal())
+		case "lower", "upper":
+			builder.WriteString(op + "(")
+			builder.WriteQuoted(expr.column)
+			builder.WriteString(") " + compare + " ")
+			builder.AddVar(stmt, rangeVar(expr.value, dialect))
+		}
+	case "mysql", "sqlite":
+		var (
+			bounds  = expr.bounds
+			extract = func(key string) {
+				if dialect == "mysql" {
+					builder.WriteString("JSON_EXTRACT(")
+				} else {
+					builder.WriteString("json_extract(")
+				}
+				builder.WriteQuoted(expr.column)
+				builder.WriteString(",'$." + key + "')")
+			}
+			// writes conditions of the column bound compared with the value, e.g. (lower < ? OR (lower = ? AND lower_inc = 1))
+			write = func(key, op string, value interface{}, inc string) {
+				builder.WriteString("(")
+				extract(key)
+				builder.WriteString(" IS NULL OR ")
+				extract(key)
+				builder.WriteString(" " + op + " ")
+				builder.AddVar(stmt, rangeVar(value, dialect))
+				if inc != "" {
+					builder.WriteString(" OR (")
+					extract(key)
+					builder.WriteString(" = ")
+					builder.AddVar(stmt, rangeVar(value, dialect))
+					if inc != "true" {
+						builder.WriteString(" AND ")
+						extract(inc)
+						builder.WriteString(" = 1")
+					}
+					builder.WriteString(")")
+				}
+				builder.WriteString(")")
+			}
+			notEmpty = func() {
+				builder.WriteString("COALESCE(")
+				extract("empty")
+				builder.WriteString(",0) = 0")
+			}
+		)
+
+		switch op {
+		case "contains":
+			notEmpty()
+			builder.WriteString(" AND ")
+			write("lower", "<", expr.value, "lower_inc")
+			builder.WriteString(" AND ")
+			write("upper", ">", expr.value, "upper_inc")
+		case "contains_range":
+			if bounds.empty {
+				builder.WriteString("1 = 1")
+				return
+			}
+
+			notEmpty()
+			builder.WriteString(" AND ")
+			if bounds.lower == nil {
+				extract("lower")
+				builder.WriteString(" IS NULL")
+			} else if bounds.lowerInc {
+				write("lower", "<", bounds.lower, "lower_inc")
+			} else {
+				write("lower", "<", bounds.lower, "true")
+			}
+
+			builder.WriteString(" AND ")
+			if bounds.upper == nil {
+				extract("upper")
+				builder.WriteString(" IS NULL")
+			} else if bounds.upperInc {
+				write("upper", ">", bounds.upper, "upper_inc")
+			} else {
+				write("upper", ">", bounds.upper, "true")
+			}
+		case "overlaps":
+			if bounds.empty {
+				builder.WriteString("1 <> 1")
+				return
+			}
+
+			notEmpty()
+			if bounds.upper != nil {
+				builder.WriteString(" AND ")
+				if bounds.upperInc {
+					write("lower", "<", bounds.upper, "lower_inc")
+				} else {
+					write("lower", "<", bounds.upper, "")
+				}
+			}
+
+			if bounds.lower != nil {
+				builder.WriteString(" AND ")
+				if bounds.lowerInc {
+					write("upper", ">", bounds.lower, "upper_inc")
+				} else {
+					write("upper", ">", bounds.lower, "")
+				}
+			}
+		case "adjacent":
+			if bounds.empty || (bounds.lower == nil && bounds.upper == nil) {
+				builder.WriteString("1 <> 1")
+				return
+			}
+
+			notEmpty()
+			builder.WriteString(" AND (")
+			// the upper bound of the column is the lower bound of the range, exactly one of them is inclusive
+			for idx, adjacent := range []struct {
+				key, inc string
+				value    interface{}
+				valueInc bool
+			}{{"upper", "upper_inc", bounds.lower, bounds.lowerInc}, {"lower", "lower_inc", bounds.upper, bounds.upperInc}} {
+				if adjacent.value == nil {
+					continue
+				}
+
+				if idx > 0 && bounds.lower != nil {
+					builder.WriteString(" OR ")
+				}
+
+				builder.WriteString("(")
+				extract(adjacent.key)
+				builder.WriteString(" = ")
+				builder.AddVar(stmt, rangeVar(adjacent.value, dialect))
+				builder.WriteString(" AND ")
+				extract(adjacent.inc)
+				if adjacent.valueInc {
+					builder.WriteString(" = 0)")
+				} else {
+					builder.WriteString(" = 1)")
+				}
+			}
+			builder.WriteString(")")
+		case "lower", "upper":
+			extract(op)
+			builder.WriteString(" " + compare + " ")
+			builder.AddVar(stmt, rangeVar(expr.value, dialect))
+		}
+	default:
+		stmt.AddError(fmt.Errorf("range query is not supported by %s", dialect))
+	}
+}
@@ -0,0 +1,88 @@
+package datatypes_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/datatypes"
+	. "github.com/fangxing98/jx-gorm/gorm/utils/tests"
+)
+
+func TestRangeScan(t *testing.T) {
+	var seats datatypes.Range[int32]
+	if err := seats.Scan("(,5]"); err != nil {
+		t.Fatalf("failed to scan range, got error %v", err)
+	}
+	AssertEqual(t, seats, datatypes.Range[int32]{Upper: 5, UpperInclusive: true, LowerInfinite: true})
+
+	value, _ := seats.Value()
+	AssertEqual(t, value, `(,"6")`)
+
+	var period datatypes.Range[time.Time]
+	if err := period.Scan(`["2024-01-01 10:00:00+00","2024-01-02 10:00:00+00")`); err != nil {
+		t.Fatalf("failed to scan time range, got error %v", err)
+	}
+	AssertEqual(t, period.Lower, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+	AssertEqual(t, period.UpperInclusive, false)
+
+	var price datatypes.Range[datatypes.Decimal]
+	if err := price.Scan(`{"lower":1.50,"upper":2,"lower_inc":1,"upper_inc":0}`); err != nil {
+		t.Fatalf("failed to scan json range, got error %v", err)
+	}
+	AssertEqual(t, price.Lower.String(), "1.50")
+
+	value, _ = datatypes.NewRange[int64](3, 3).Value()
+	AssertEqual(t, value, "empty")
+}
+
+func TestRange(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres") {
+		type Booking struct {
+			ID     uint
+			Seats  datatypes.Range[int32]
+			Period datatypes.Range[time.Time]
+		}
+
+		DB.Migrator().DropTable(&Booking{})
+		if err := DB.Migrator().AutoMigrate(&Booking{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+		bookings := []Booking{
+			{Seats: datatypes.NewRange[int32](1, 5), Period: datatypes.NewRange(day(1), day(3))},
+			{Seats: datatypes.Range[int32]{Lower: 5, LowerInclusive: true, UpperInfinite: true}, Period: datatypes.NewRange(day(3), day(5))},
+		}
+		if err := DB.Create(&bookings).Error; err != nil {
+			t.Errorf("Failed to create bookings %v", err)
+		}
+
+		var result Booking
+		if err := DB.First(&result, bookings[1].ID).Error; err != nil {
+			t.Fatalf("failed to find booking with range, got error %v", err)
+		}
+		AssertEqual(t, result.Seats, bookings[1].Seats)
+
+		tests := []struct {
+			name     string
+			query    *datatypes.RangeExpression
+			expected []uint
+		}{
+			{"contains", datatypes.RangeQuery("seats").Contains(int32(3)), []uint{bookings[0].ID}},
+			{"contains infinite", datatypes.RangeQuery("seats").Contains(int32(100)), []uint{bookings[1].ID}},
+			{"contains range", datatypes.RangeQuery("seats").ContainsRange(datatypes.Range[int32]{Lower: 2, Upper: 4, LowerInclusive: true, UpperInclusive: true}), []uint{bookings[0].ID}},
+			{"overlaps", datatypes.RangeQuery("seats").Overlaps(datatypes.NewRange[int32](4, 6)), []uint{bookings[0].ID, bookings[1].ID}},
+			{"adjacent", datatypes.RangeQuery("period").Adjacent(datatypes.NewRange(day(5), day(6))), []uint{bookings[1].ID}},
+			{"lower", datatypes.RangeQuery("seats").Lower(">=", int32(5)), []uint{bookings[1].ID}},
+			{"upper", datatypes.RangeQuery("period").Upper("<=", day(3)), []uint{bookings[0].ID}},
+		}
+
+		for _, test := range tests {
+			var ids []uint
+			if err := DB.Model(&Booking{}).Where(test.query).Order("id").Pluck("id", &ids).Error; err != nil {
+				t.Fatalf("failed to query %v, got error %v", test.name, err)
+			}
+			AssertEqual(t, ids, test.expected)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/fangxing98/jx-gorm/datatypes"
 	"net/url"
 	"testing"
+
+	. "github.com/fangxing98/jx-gorm/gorm/utils/tests"
 )
 
 func TestURL(t *testing.T) {
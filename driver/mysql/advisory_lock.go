@@ -0,0 +1,37 @@
+package mysql
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+// maxLockNameLength max length of lock names of GET_LOCK
+const maxLockNameLength = 64
+
+// AdvisoryLock implements gorm.AdvisoryLockerDialectorInterface with GET_LOCK
+func (dialector Dialector) AdvisoryLock(tx *gorm.DB, key string, wait bool) (bool, error) {
+	timeout := 0
+	if wait {
+		timeout = -1
+	}
+
+	var acquired *int
+	err := tx.Raw("SELECT GET_LOCK(?, ?)", lockName(key), timeout).Scan(&acquired).Error
+	return acquired != nil && *acquired == 1, err
+}
+
+// AdvisoryUnlock implements gorm.AdvisoryLockerDialectorInterface with RELEASE_LOCK
+func (dialector Dialector) AdvisoryUnlock(tx *gorm.DB, key string) error {
+	return tx.Exec("SELECT RELEASE_LOCK(?)", lockName(key)).Error
+}
+
+// lockName hashes keys longer than the max length of lock names
+func lockName(key string) string {
+	if len(key) > maxLockNameLength {
+		sum := sha1.Sum([]byte(key))
+		return hex.EncodeToString(sum[:])
+	}
+	return key
+}
@@ -0,0 +1,118 @@
+package mysql
+
+import (
+	"bufio"
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/clause"
+)
+
+var bulkLoadSeq uint64
+
+// MaxBindVars implements gorm.BindVarsLimiter interface, the max placeholders of a prepared statement
+func (dialector Dialector) MaxBindVars() int {
+	return 65535
+}
+
+// BulkLoad implements gorm.BulkLoader interface, loads rows with LOAD DATA LOCAL INFILE from a registered reader,
+// requires local_infile enabled on the server
+func (dialector Dialector) BulkLoad(ctx context.Context, db *gorm.DB, table string, columns []string, source gorm.BulkLoadSource) (int64, error) {
+	name := fmt.Sprintf("gorm_bulk_load_%d", atomic.AddUint64(&bulkLoadSeq, 1))
+
+	reader, writer := io.Pipe()
+	mysql.RegisterReaderHandler(name, func() io.Reader { return reader })
+	defer mysql.DeregisterReaderHandler(name)
+
+	go func() {
+		writer.CloseWithError(writeBulkLoadRows(writer, source))
+	}()
+
+	stmt := &gorm.Statement{DB: db, Table: table, Context: ctx}
+	stmt.WriteString("LOAD DATA LOCAL INFILE 'Reader::" + name + "' INTO TABLE ")
+	stmt.WriteQuoted(clause.Table{Name: table})
+	stmt.WriteString(" CHARACTER SET utf8mb4 FIELDS TERMINATED BY '\\t' ESCAPED BY '\\\\' LINES TERMINATED BY '\\n' (")
+	for idx, column := range columns {
+		if idx > 0 {
+			stmt.WriteByte(',')
+		}
+		stmt.WriteQuoted(column)
+	}
+	stmt.WriteByte(')')
+
+	result, err := db.Statement.ConnPool.ExecContext(ctx, stmt.SQL.String())
+	// stop writing rows if the statement failed before reading all of them
+	reader.CloseWithError(io.ErrClosedPipe)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// writeBulkLoadRows writes rows as tab separated values, NULL is written as \N
+func writeBulkLoadRows(w io.Writer, source gorm.BulkLoadSource) error {
+	buf := bufio.NewWriter(w)
+	for source.Next() {
+		values, err := source.Values()
+		if err != nil {
+			return err
+		}
+
+		for idx, value := range values {
+			if idx > 0 {
+				buf.WriteByte('\t')
+			}
+			writeBulkLoadValue(buf, value)
+		}
+
+		if err := buf.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+
+	if err := source.Err(); err != nil {
+		return err
+	}
+	return buf.Flush()
+}
+
+var bulkLoadEscaper = strings.NewReplacer("\\", "\\\\", "\t", "\\t", "\n", "\\n", "\r", "\\r", "\x00", "\\0")
+
+func writeBulkLoadValue(buf *bufio.Writer, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("\\N")
+	case string:
+		bulkLoadEscaper.WriteString(buf, v)
+	case []byte:
+		bulkLoadEscaper.WriteString(buf, string(v))
+	case bool:
+		if v {
+			buf.WriteByte('1')
+		} else {
+			buf.WriteByte('0')
+		}
+	case time.Time:
+		buf.WriteString(v.Format("2006-01-02 15:04:05.999999"))
+	case int64:
+		buf.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		buf.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	default:
+		// convert ints, floats, pointers and valuers to driver values
+		if converted, err := driver.DefaultParameterConverter.ConvertValue(v); err == nil {
+			writeBulkLoadValue(buf, converted)
+		} else {
+			bulkLoadEscaper.WriteString(buf, fmt.Sprint(v))
+		}
+	}
+}
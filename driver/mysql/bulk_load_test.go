@@ -0,0 +1,35 @@
+package mysql
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+type sliceSource struct {
+	rows  [][]interface{}
+	index int
+}
+
+func (s *sliceSource) Next() bool                     { s.index++; return s.index <= len(s.rows) }
+func (s *sliceSource) Values() ([]interface{}, error) { return s.rows[s.index-1], nil }
+func (s *sliceSource) Err() error                     { return nil }
+
+func TestWriteBulkLoadRows(t *testing.T) {
+	name := "tab\tline\nslash\\"
+	source := &sliceSource{rows: [][]interface{}{
+		{1, "jinzhu", true, nil, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{int64(2), []byte(name), false, &name, 1.5},
+	}}
+
+	var buf bytes.Buffer
+	if err := writeBulkLoadRows(&buf, source); err != nil {
+		t.Fatalf("failed to write rows, got error %v", err)
+	}
+
+	expected := "1\tjinzhu\t1\t\\N\t2024-01-02 03:04:05\n" +
+		"2\ttab\\tline\\nslash\\\\\t0\ttab\\tline\\nslash\\\\\t1.5\n"
+	if buf.String() != expected {
+		t.Errorf("expects %q, got %q", expected, buf.String())
+	}
+}
@@ -1,6 +1,8 @@
 package mysql
 
 import (
+	"errors"
+
 	"github.com/go-sql-driver/mysql"
 
 	"github.com/fangxing98/jx-gorm/gorm"
@@ -11,6 +13,13 @@ var errCodes = map[uint16]error{
 	1062: gorm.ErrDuplicatedKey,
 	1451: gorm.ErrForeignKeyViolated,
 	1452: gorm.ErrForeignKeyViolated,
+	1205: gorm.ErrLockNotAvailable,
+	3572: gorm.ErrLockNotAvailable,
+}
+
+// The error codes that transactions could be retried, 1213 deadlock found when trying to get lock.
+var retryableErrCodes = map[uint16]bool{
+	1213: true,
 }
 
 func (dialector Dialector) Translate(err error) error {
@@ -23,3 +32,9 @@ func (dialector Dialector) Translate(err error) error {
 
 	return err
 }
+
+// Retryable returns whether the transaction could be retried, e.g. deadlocks.
+func (dialector Dialector) Retryable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && retryableErrCodes[mysqlErr.Number]
+}
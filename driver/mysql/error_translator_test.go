@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/fangxing98/jx-gorm/gorm"
@@ -39,6 +40,11 @@ func TestDialector_Translate(t *testing.T) {
 			args: args{err: &mysql.MySQLError{Number: uint16(1452)}},
 			want: gorm.ErrForeignKeyViolated,
 		},
+		{
+			name: "it should translate error to ErrLockNotAvailable when the error number is 3572",
+			args: args{err: &mysql.MySQLError{Number: uint16(3572)}},
+			want: gorm.ErrLockNotAvailable,
+		},
 		{
 			name: "it should not translate the error when the error number is not registered in translated error codes",
 			args: args{err: &mysql.MySQLError{Number: uint16(8888)}},
@@ -61,3 +67,13 @@ func TestDialector_Translate(t *testing.T) {
 		})
 	}
 }
+
+func TestDialector_Retryable(t *testing.T) {
+	dialector := Dialector{}
+	if !dialector.Retryable(fmt.Errorf("wrapped: %w", &mysql.MySQLError{Number: 1213})) {
+		t.Errorf("deadlock error should be retryable")
+	}
+	if dialector.Retryable(&mysql.MySQLError{Number: 1062}) || dialector.Retryable(errors.New("normal error")) {
+		t.Errorf("other errors should not be retryable")
+	}
+}
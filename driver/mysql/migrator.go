@@ -275,7 +275,7 @@ func (m Migrator) RenameIndex(value interface{}, oldName, newName string) error
 					}
 					createIndexSQL += "INDEX ? ON ??"
 
-					if idx.Type != "" {
+					if idx.Type != "" && idx.Class != "SPATIAL" {
 						createIndexSQL += " USING " + idx.Type
 					}
 
@@ -47,6 +47,12 @@ type Config struct {
 	// for dropping and altering existing constraints of any type.
 	// see https://dev.mysql.com/doc/refman/8.0/en/alter-table.html
 	DontSupportDropConstraint bool
+	// ConnInitStatements statements executed on every new connection, e.g. SET time_zone = '+00:00'
+	ConnInitStatements []string
+	// OnConnect called on every new connection after ConnInitStatements
+	OnConnect gorm.OnConnectFunc
+	// Pool settings of the connection pool opened with DSN
+	Pool gorm.PoolConfig
 }
 
 type Dialector struct {
@@ -119,10 +125,12 @@ func (dialector Dialector) Initialize(db *gorm.DB) (err error) {
 	if dialector.Conn != nil {
 		db.ConnPool = dialector.Conn
 	} else {
-		db.ConnPool, err = sql.Open(dialector.DriverName, dialector.DSN)
-		if err != nil {
+		var sqlDB *sql.DB
+		if sqlDB, err = gorm.OpenDB(dialector.DriverName, dialector.DSN, dialector.ConnInitStatements, dialector.OnConnect); err != nil {
 			return err
 		}
+		dialector.Pool.Apply(sqlDB)
+		db.ConnPool = sqlDB
 	}
 
 	withReturning := false
@@ -511,6 +519,15 @@ func (dialector Dialector) RollbackTo(tx *gorm.DB, name string) error {
 	return tx.Exec("ROLLBACK TO SAVEPOINT " + name).Error
 }
 
+func (dialector Dialector) ReleaseSavePoint(tx *gorm.DB, name string) error {
+	return tx.Exec("RELEASE SAVEPOINT " + name).Error
+}
+
+// LockTimeout implements gorm.LockTimeoutDialectorInterface, innodb_lock_wait_timeout is in seconds
+func (dialector Dialector) LockTimeout(timeout time.Duration) (string, string) {
+	return fmt.Sprintf("SET SESSION innodb_lock_wait_timeout = %d", max(int64(math.Ceil(timeout.Seconds())), 1)), "SET SESSION innodb_lock_wait_timeout = DEFAULT"
+}
+
 // checkVersion newer or equal returns true, old returns false
 func checkVersion(newVersion, oldVersion string) bool {
 	if newVersion == oldVersion {
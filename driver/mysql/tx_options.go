@@ -0,0 +1,27 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+// TranslateTxOptions implements gorm.TxOptionsDialectorInterface, isolation level and read-only mode are set by the driver
+func (dialector Dialector) TranslateTxOptions(opts gorm.TxOptions) (gorm.TxBeginPlan, error) {
+	switch opts.Isolation {
+	case sql.LevelDefault, sql.LevelReadUncommitted, sql.LevelReadCommitted, sql.LevelRepeatableRead, sql.LevelSerializable:
+	default:
+		return gorm.TxBeginPlan{}, fmt.Errorf("%w: isolation level %s is not supported by mysql", gorm.ErrUnsupportedTxOptions, opts.Isolation)
+	}
+
+	if opts.Deferrable {
+		return gorm.TxBeginPlan{}, fmt.Errorf("%w: deferrable transaction is not supported by mysql", gorm.ErrUnsupportedTxOptions)
+	}
+
+	if opts.LockMode != "" {
+		return gorm.TxBeginPlan{}, fmt.Errorf("%w: lock mode is not supported by mysql", gorm.ErrUnsupportedTxOptions)
+	}
+
+	return gorm.TxBeginPlan{Options: &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly}}, nil
+}
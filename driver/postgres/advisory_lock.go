@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"hash/fnv"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+// AdvisoryLock implements gorm.AdvisoryLockerDialectorInterface with session level advisory locks
+func (dialector Dialector) AdvisoryLock(tx *gorm.DB, key string, wait bool) (bool, error) {
+	if wait {
+		return true, tx.Exec("SELECT pg_advisory_lock(?)", advisoryLockKey(key)).Error
+	}
+
+	var acquired bool
+	err := tx.Raw("SELECT pg_try_advisory_lock(?)", advisoryLockKey(key)).Scan(&acquired).Error
+	return acquired, err
+}
+
+// AdvisoryUnlock implements gorm.AdvisoryLockerDialectorInterface
+func (dialector Dialector) AdvisoryUnlock(tx *gorm.DB, key string) error {
+	return tx.Exec("SELECT pg_advisory_unlock(?)", advisoryLockKey(key)).Error
+}
+
+// advisoryLockKey hashes string keys to the int64 keys of advisory locks
+func advisoryLockKey(key string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(key))
+	return int64(hash.Sum64())
+}
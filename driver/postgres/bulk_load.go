@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// MaxBindVars implements gorm.BindVarsLimiter interface, the max parameters of the extended query protocol
+func (dialector Dialector) MaxBindVars() int {
+	return 65535
+}
+
+// BulkLoad implements gorm.BulkLoader interface, loads rows with COPY FROM of pgx, falls back to prepared inserts
+// in transactions or non-pgx connections, as the pgx connection of a database/sql transaction is unreachable
+func (dialector Dialector) BulkLoad(ctx context.Context, db *gorm.DB, table string, columns []string, source gorm.BulkLoadSource) (rowsAffected int64, err error) {
+	if _, ok := db.Statement.ConnPool.(gorm.TxCommitter); ok {
+		return 0, gorm.ErrNotImplemented
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return 0, gorm.ErrNotImplemented
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		stdConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return gorm.ErrNotImplemented
+		}
+
+		rowsAffected, err = stdConn.Conn().CopyFrom(ctx, pgx.Identifier(strings.Split(table, ".")), columns, source)
+		return err
+	})
+	return
+}
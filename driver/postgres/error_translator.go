@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"encoding/json"
+	"errors"
 
 	"github.com/fangxing98/jx-gorm/gorm"
 
@@ -14,6 +15,13 @@ var errCodes = map[string]error{
 	"23503": gorm.ErrForeignKeyViolated,
 	"42703": gorm.ErrInvalidField,
 	"23514": gorm.ErrCheckConstraintViolated,
+	"55P03": gorm.ErrLockNotAvailable,
+}
+
+// The error codes that transactions could be retried, 40001 serialization_failure, 40P01 deadlock_detected.
+var retryableErrCodes = map[string]bool{
+	"40001": true,
+	"40P01": true,
 }
 
 type ErrMessage struct {
@@ -48,3 +56,22 @@ func (dialector Dialector) Translate(err error) error {
 	}
 	return err
 }
+
+// Retryable returns whether the transaction could be retried, e.g. serialization failures and deadlocks.
+func (dialector Dialector) Retryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return retryableErrCodes[pgErr.Code]
+	}
+
+	parsedErr, marshalErr := json.Marshal(err)
+	if marshalErr != nil {
+		return false
+	}
+
+	var errMsg ErrMessage
+	if unmarshalErr := json.Unmarshal(parsedErr, &errMsg); unmarshalErr != nil {
+		return false
+	}
+	return retryableErrCodes[errMsg.Code]
+}
@@ -41,6 +41,11 @@ func TestDialector_Translate(t *testing.T) {
 			args: args{err: &pgconn.PgError{Code: "23514"}},
 			want: gorm.ErrCheckConstraintViolated,
 		},
+		{
+			name: "it should return gorm.ErrLockNotAvailable error if the status code is 55P03",
+			args: args{err: &pgconn.PgError{Code: "55P03"}},
+			want: gorm.ErrLockNotAvailable,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -53,3 +58,15 @@ func TestDialector_Translate(t *testing.T) {
 		})
 	}
 }
+
+func TestDialector_Retryable(t *testing.T) {
+	dialector := Dialector{}
+	for _, code := range []string{"40001", "40P01"} {
+		if !dialector.Retryable(&pgconn.PgError{Code: code}) {
+			t.Errorf("error %v should be retryable", code)
+		}
+	}
+	if dialector.Retryable(&pgconn.PgError{Code: "23505"}) || dialector.Retryable(errors.New("normal error")) {
+		t.Errorf("other errors should not be retryable")
+	}
+}
@@ -125,7 +125,12 @@ func (m Migrator) CreateIndex(value interface{}, name string) error {
 				values := []interface{}{clause.Column{Name: idx.Name}, m.CurrentTable(stmt), opts}
 
 				createIndexSQL := "CREATE "
-				if idx.Class != "" {
+				if idx.Class == "SPATIAL" {
+					// spatial indexes are gist indexes in postgres
+					if idx.Type == "" {
+						idx.Type = "gist"
+					}
+				} else if idx.Class != "" {
 					createIndexSQL += idx.Class + " "
 				}
 				createIndexSQL += "INDEX "
@@ -186,6 +191,21 @@ func (m Migrator) GetTables() (tableList []string, err error) {
 }
 
 func (m Migrator) CreateTable(values ...interface{}) (err error) {
+	for _, value := range m.ReorderModels(values, false) {
+		if err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+			if stmt.Schema != nil {
+				for _, fieldName := range stmt.Schema.DBNames {
+					if err := m.migrateEnumType(stmt, stmt.Schema.FieldsByDBName[fieldName]); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}); err != nil {
+			return
+		}
+	}
+
 	if err = m.Migrator.CreateTable(values...); err != nil {
 		return
 	}
@@ -235,10 +255,20 @@ func (m Migrator) DropTable(values ...interface{}) error {
 }
 
 func (m Migrator) AddColumn(value interface{}, field string) error {
+	if err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if stmt.Schema != nil {
+			if field := stmt.Schema.LookUpField(field); field != nil {
+				return m.migrateEnumType(stmt, field)
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
 	if err := m.Migrator.AddColumn(value, field); err != nil {
 		return err
 	}
-	m.resetPreparedStmts()
 
 	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
 		if stmt.Schema != nil {
@@ -278,6 +308,12 @@ func (m Migrator) HasColumn(value interface{}, field string) bool {
 }
 
 func (m Migrator) MigrateColumn(value interface{}, field *schema.Field, columnType gorm.ColumnType) error {
+	if err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.migrateEnumType(stmt, field)
+	}); err != nil {
+		return err
+	}
+
 	// skip primary field
 	if !field.PrimaryKey {
 		if err := m.Migrator.MigrateColumn(value, field, columnType); err != nil {
@@ -408,11 +444,7 @@ func (m Migrator) AlterColumn(value interface{}, field string) error {
 		return fmt.Errorf("failed to look up field with name: %s", field)
 	})
 
-	if err != nil {
-		return err
-	}
-	m.resetPreparedStmts()
-	return nil
+	return err
 }
 
 func (m Migrator) modifyColumn(stmt *gorm.Statement, field *schema.Field, targetType clause.Expr, existingColumn *migrator.ColumnType) error {
@@ -427,8 +459,10 @@ func (m Migrator) modifyColumn(stmt *gorm.Statement, field *schema.Field, target
 		isUncastableDefaultValue = true
 	}
 
-	// kingbase 类型处理
-	targetType.SQL = gorm.PgDBTypeMap(stmt.Table, field)
+	// kingbase 类型处理，enum 字段使用数据库原生枚举类型
+	if _, isEnum := m.EnumValuesOf(field); !isEnum {
+		targetType.SQL = gorm.PgDBTypeMap(stmt.Table, field)
+	}
 
 	if dv, _ := existingColumn.DefaultValue(); dv != "" && isUncastableDefaultValue {
 		if err := m.DB.Exec("ALTER TABLE ? ALTER COLUMN ? DROP DEFAULT", m.CurrentTable(stmt), clause.Column{Name: field.DBName}).Error; err != nil {
@@ -441,6 +475,52 @@ func (m Migrator) modifyColumn(stmt *gorm.Statement, field *schema.Field, target
 	return nil
 }
 
+// migrateEnumType create the enum type of field if not exists, otherwise add the missing values to it
+func (m Migrator) migrateEnumType(stmt *gorm.Statement, field *schema.Field) error {
+	values, ok := m.EnumValuesOf(field)
+	if !ok || field.IgnoreMigration {
+		return nil
+	}
+
+	var (
+		currentValues []string
+		typeName      = m.DataTypeOf(field)
+		currentSchema = interface{}(clause.Expr{SQL: "CURRENT_SCHEMA()"})
+	)
+	if names := strings.Split(typeName, "."); len(names) == 2 {
+		currentSchema, typeName = names[0], names[1]
+	}
+
+	if err := m.queryRaw(
+		"SELECT e.enumlabel FROM pg_catalog.pg_enum e JOIN pg_catalog.pg_type t ON t.oid = e.enumtypid JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace WHERE t.typname = ? AND n.nspname = ? ORDER BY e.enumsortorder",
+		typeName, currentSchema,
+	).Scan(&currentValues).Error; err != nil {
+		return err
+	}
+
+	if len(currentValues) == 0 {
+		literals := make([]string, len(values))
+		for idx, value := range values {
+			literals[idx] = m.Dialector.Explain("$1", value)
+		}
+		return m.DB.Exec("CREATE TYPE ? AS ENUM (?)", clause.Table{Name: m.DataTypeOf(field)}, gorm.Expr(strings.Join(literals, ","))).Error
+	}
+
+	// the type might be shared with other enums, values can't be removed from postgres enum types
+	if undeclared := migrator.MissingEnumValues(values, currentValues); len(undeclared) > 0 {
+		return fmt.Errorf("enum type %s has values %v not declared by field %s, rename the type with tag `type`", typeName, undeclared, field.Name)
+	}
+
+	for _, value := range migrator.MissingEnumValues(currentValues, values) {
+		if err := m.DB.Exec(
+			"ALTER TYPE ? ADD VALUE IF NOT EXISTS ?", clause.Table{Name: m.DataTypeOf(field)}, gorm.Expr(m.Dialector.Explain("$1", value)),
+		).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m Migrator) HasConstraint(value interface{}, name string) bool {
 	var count int64
 	m.RunWithValue(value, func(stmt *gorm.Statement) error {
@@ -785,33 +865,6 @@ func (m Migrator) GetTypeAliases(databaseTypeName string) []string {
 	return typeAliasMap[databaseTypeName]
 }
 
-// should reset prepared stmts when table changed
-func (m Migrator) resetPreparedStmts() {
-	if m.DB.PrepareStmt {
-		if pdb, ok := m.DB.ConnPool.(*gorm.PreparedStmtDB); ok {
-			pdb.Reset()
-		}
-	}
-}
-
-func (m Migrator) DropColumn(dst interface{}, field string) error {
-	if err := m.Migrator.DropColumn(dst, field); err != nil {
-		return err
-	}
-
-	m.resetPreparedStmts()
-	return nil
-}
-
-func (m Migrator) RenameColumn(dst interface{}, oldName, field string) error {
-	if err := m.Migrator.RenameColumn(dst, oldName, field); err != nil {
-		return err
-	}
-
-	m.resetPreparedStmts()
-	return nil
-}
-
 func parseDefaultValueValue(defaultValue string) string {
 	value := regexp.MustCompile(`^(.*?)(?:::.*)?$`).ReplaceAllString(defaultValue, "$1")
 	return strings.Trim(value, "'")
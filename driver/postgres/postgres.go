@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fangxing98/jx-gorm/gorm"
 	"github.com/fangxing98/jx-gorm/gorm/callbacks"
@@ -28,6 +29,12 @@ type Config struct {
 	PreferSimpleProtocol bool
 	WithoutReturning     bool
 	Conn                 gorm.ConnPool
+	// ConnInitStatements statements executed on every new connection, e.g. SET search_path TO app
+	ConnInitStatements []string
+	// OnConnect called on every new connection after ConnInitStatements
+	OnConnect gorm.OnConnectFunc
+	// Pool settings of the connection pool opened with DSN
+	Pool gorm.PoolConfig
 }
 
 var (
@@ -84,10 +91,14 @@ func (dialector Dialector) Initialize(db *gorm.DB) (err error) {
 	}
 	callbacks.RegisterDefaultCallbacks(db, callbackConfig)
 
+	var sqlDB *sql.DB
 	if dialector.Conn != nil {
 		db.ConnPool = dialector.Conn
+		return
 	} else if dialector.DriverName != "" {
-		db.ConnPool, err = sql.Open(dialector.DriverName, dialector.Config.DSN)
+		if sqlDB, err = gorm.OpenDB(dialector.DriverName, dialector.Config.DSN, dialector.ConnInitStatements, dialector.OnConnect); err != nil {
+			return
+		}
 	} else {
 		var config *pgx.ConnConfig
 
@@ -102,8 +113,11 @@ func (dialector Dialector) Initialize(db *gorm.DB) (err error) {
 		if len(result) > 2 {
 			config.RuntimeParams["timezone"] = result[2]
 		}
-		db.ConnPool = stdlib.OpenDB(*config)
+		sqlDB = sql.OpenDB(gorm.WithConnInit(stdlib.GetConnector(*config), dialector.ConnInitStatements, dialector.OnConnect))
 	}
+
+	dialector.Pool.Apply(sqlDB)
+	db.ConnPool = sqlDB
 	return
 }
 
@@ -275,6 +289,15 @@ func (dialector Dialector) RollbackTo(tx *gorm.DB, name string) error {
 	return nil
 }
 
+func (dialector Dialector) ReleaseSavePoint(tx *gorm.DB, name string) error {
+	return tx.Exec("RELEASE SAVEPOINT " + name).Error
+}
+
+// LockTimeout implements gorm.LockTimeoutDialectorInterface, lock_timeout is reverted at the end of the transaction
+func (dialector Dialector) LockTimeout(timeout time.Duration) (string, string) {
+	return fmt.Sprintf("SET LOCAL lock_timeout = %d", max(timeout.Milliseconds(), 1)), "SET LOCAL lock_timeout = DEFAULT"
+}
+
 func getSerialDatabaseType(s string) (dbType string, ok bool) {
 	switch s {
 	case "smallserial":
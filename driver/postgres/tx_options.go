@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+// TranslateTxOptions implements gorm.TxOptionsDialectorInterface, isolation level and read-only mode are set by the driver,
+// DEFERRABLE is set after the transaction began as database/sql doesn't support it
+func (dialector Dialector) TranslateTxOptions(opts gorm.TxOptions) (gorm.TxBeginPlan, error) {
+	switch opts.Isolation {
+	case sql.LevelDefault, sql.LevelReadUncommitted, sql.LevelReadCommitted, sql.LevelRepeatableRead, sql.LevelSerializable:
+	default:
+		return gorm.TxBeginPlan{}, fmt.Errorf("%w: isolation level %s is not supported by postgres", gorm.ErrUnsupportedTxOptions, opts.Isolation)
+	}
+
+	if opts.LockMode != "" {
+		return gorm.TxBeginPlan{}, fmt.Errorf("%w: lock mode is not supported by postgres", gorm.ErrUnsupportedTxOptions)
+	}
+
+	plan := gorm.TxBeginPlan{Options: &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly}}
+	if opts.Deferrable {
+		if opts.Isolation != sql.LevelSerializable || !opts.ReadOnly {
+			return gorm.TxBeginPlan{}, fmt.Errorf("%w: deferrable transaction should be serializable and read-only", gorm.ErrUnsupportedTxOptions)
+		}
+		plan.Statements = []string{"SET TRANSACTION DEFERRABLE"}
+	}
+	return plan, nil
+}
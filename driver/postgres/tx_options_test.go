@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+func TestDialector_TranslateTxOptions(t *testing.T) {
+	dialector := Dialector{}
+
+	plan, err := dialector.TranslateTxOptions(gorm.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true, Deferrable: true})
+	if err != nil {
+		t.Fatalf("failed to translate options, got error %v", err)
+	}
+	if !reflect.DeepEqual(plan.Options, &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}) || !reflect.DeepEqual(plan.Statements, []string{"SET TRANSACTION DEFERRABLE"}) {
+		t.Errorf("deferrable should be set after the transaction began, got %+v", plan)
+	}
+
+	for _, opts := range []gorm.TxOptions{
+		{Deferrable: true},
+		{Isolation: sql.LevelSerializable, Deferrable: true},
+		{Isolation: sql.LevelSnapshot},
+		{LockMode: gorm.TxLockModeImmediate},
+	} {
+		if _, err := dialector.TranslateTxOptions(opts); !errors.Is(err, gorm.ErrUnsupportedTxOptions) {
+			t.Errorf("options %+v should be unsupported, got %v", opts, err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+const (
+	// advisoryLockTable the table keeping advisory locks as SQLite doesn't support them
+	advisoryLockTable = "gorm_advisory_locks"
+	// advisoryLockPollInterval interval to retry acquiring the lock when waiting
+	advisoryLockPollInterval = 50 * time.Millisecond
+	// defaultAdvisoryLockTTL lease of advisory locks if Dialector.AdvisoryLockTTL is not set
+	defaultAdvisoryLockTTL = 30 * time.Second
+)
+
+// advisoryLockLeases leases held by this process, the lease is refreshed until the lock released
+var advisoryLockLeases sync.Map
+
+type advisoryLockHolder struct {
+	conn gorm.ConnPool
+	key  string
+}
+
+type advisoryLockLease struct {
+	owner  string
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (dialector Dialector) advisoryLockTTL() time.Duration {
+	if dialector.AdvisoryLockTTL > 0 {
+		return dialector.AdvisoryLockTTL
+	}
+	return defaultAdvisoryLockTTL
+}
+
+// AdvisoryLock implements gorm.AdvisoryLockerDialectorInterface with a lock table, a lock is acquired by inserting the key.
+// The lock is a lease refreshed while held, so the lock of a crashed process is taken over by others after AdvisoryLockTTL
+func (dialector Dialector) AdvisoryLock(tx *gorm.DB, key string, wait bool) (bool, error) {
+	if err := tx.Exec("CREATE TABLE IF NOT EXISTS " + advisoryLockTable + " (`key` text PRIMARY KEY, `owner` text, `acquired_at` integer)").Error; err != nil {
+		return false, err
+	}
+
+	owner, err := newAdvisoryLockOwner()
+	if err != nil {
+		return false, err
+	}
+
+	ttl := dialector.advisoryLockTTL()
+	for {
+		now := tx.NowFunc()
+		result := tx.Exec("INSERT INTO "+advisoryLockTable+" (`key`, `owner`, `acquired_at`) VALUES (?, ?, ?) "+
+			"ON CONFLICT (`key`) DO UPDATE SET `owner` = excluded.`owner`, `acquired_at` = excluded.`acquired_at` WHERE `acquired_at` < ?",
+			key, owner, now.UnixMilli(), now.Add(-ttl).UnixMilli())
+		if result.Error != nil {
+			return false, result.Error
+		}
+
+		if result.RowsAffected == 1 {
+			dialector.keepAdvisoryLock(tx, key, owner, ttl)
+			return true, nil
+		} else if !wait {
+			return false, nil
+		}
+
+		select {
+		case <-tx.Statement.Context.Done():
+			return false, tx.Statement.Context.Err()
+		case <-time.After(advisoryLockPollInterval):
+		}
+	}
+}
+
+// keepAdvisoryLock refreshes the lease of the lock until it is released or taken over by others
+func (dialector Dialector) keepAdvisoryLock(tx *gorm.DB, key, owner string, ttl time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lease := &advisoryLockLease{owner: owner, cancel: cancel, done: make(chan struct{})}
+	advisoryLockLeases.Store(advisoryLockHolder{conn: tx.Statement.ConnPool, key: key}, lease)
+
+	go func() {
+		defer close(lease.done)
+
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			result := tx.Session(&gorm.Session{NewDB: true, Context: ctx}).Exec(
+				"UPDATE "+advisoryLockTable+" SET `acquired_at` = ? WHERE `key` = ? AND `owner` = ?", tx.NowFunc().UnixMilli(), key, owner,
+			)
+			if result.Error != nil || result.RowsAffected == 0 {
+				return
+			}
+		}
+	}()
+}
+
+// AdvisoryUnlock implements gorm.AdvisoryLockerDialectorInterface
+func (dialector Dialector) AdvisoryUnlock(tx *gorm.DB, key string) error {
+	value, ok := advisoryLockLeases.LoadAndDelete(advisoryLockHolder{conn: tx.Statement.ConnPool, key: key})
+	if !ok {
+		return tx.Exec("DELETE FROM "+advisoryLockTable+" WHERE `key` = ?", key).Error
+	}
+
+	lease := value.(*advisoryLockLease)
+	lease.cancel()
+	<-lease.done
+	// the lock might be taken over by others if the lease expired
+	return tx.Exec("DELETE FROM "+advisoryLockTable+" WHERE `key` = ? AND `owner` = ?", key, lease.owner).Error
+}
+
+func newAdvisoryLockOwner() (string, error) {
+	owner := make([]byte, 16)
+	if _, err := rand.Read(owner); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(owner), nil
+}
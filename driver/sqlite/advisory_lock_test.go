@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+func TestAdvisoryLockLease(t *testing.T) {
+	ttl := 150 * time.Millisecond
+	db, err := gorm.Open(New(Config{DSN: filepath.Join(t.TempDir(), "gorm.db"), AdvisoryLockTTL: ttl}), gorm.DBTypeSqlite, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open database, got error %v", err)
+	}
+	ctx := context.Background()
+
+	unlock, err := db.AdvisoryLock(ctx, "daily-report")
+	if err != nil {
+		t.Fatalf("failed to acquire lock, got error %v", err)
+	}
+
+	// the lease is refreshed while the lock is held
+	time.Sleep(3 * ttl)
+	if _, err := db.TryAdvisoryLock(ctx, "daily-report"); !errors.Is(err, gorm.ErrLockNotAvailable) {
+		t.Errorf("lock should be held after the ttl, got error %v", err)
+	}
+	if err := unlock(); err != nil {
+		t.Fatalf("failed to release lock, got error %v", err)
+	}
+
+	// lock left by a crashed process is taken over after the ttl
+	if err := db.Exec("INSERT INTO "+advisoryLockTable+" (`key`, `owner`, `acquired_at`) VALUES (?, ?, ?)",
+		"daily-report", "crashed", db.NowFunc().UnixMilli()).Error; err != nil {
+		t.Fatalf("failed to insert lock, got error %v", err)
+	}
+
+	if _, err := db.TryAdvisoryLock(ctx, "daily-report"); !errors.Is(err, gorm.ErrLockNotAvailable) {
+		t.Errorf("lock should be held before the ttl, got error %v", err)
+	}
+
+	time.Sleep(2 * ttl)
+	unlock, err = db.TryAdvisoryLock(ctx, "daily-report")
+	if err != nil {
+		t.Fatalf("expired lock should be taken over, got error %v", err)
+	}
+	if err := unlock(); err != nil {
+		t.Errorf("failed to release lock, got error %v", err)
+	}
+
+	var count int64
+	if err := db.Table(advisoryLockTable).Where("`key` = ?", "daily-report").Count(&count).Error; err != nil || count != 0 {
+		t.Errorf("lock should be removed after released, got %v, error %v", count, err)
+	}
+}
@@ -283,3 +283,33 @@ func (d *ddl) removeColumn(name string) bool {
 
 	return false
 }
+
+// parseCheckDefinition returns the CHECK (...) definition of column constraints
+func parseCheckDefinition(constraints string) string {
+	idx := strings.Index(strings.ToUpper(constraints), "CHECK")
+	if idx < 0 {
+		return ""
+	}
+
+	var (
+		bracketLevel int
+		quote        rune
+	)
+	for i, c := range constraints[idx:] {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			bracketLevel++
+		case c == ')':
+			if bracketLevel--; bracketLevel == 0 {
+				return constraints[idx : idx+i+1]
+			}
+		}
+	}
+	return constraints[idx:]
+}
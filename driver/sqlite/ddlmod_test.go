@@ -430,3 +430,21 @@ func TestGetColumns(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCheckDefinition(t *testing.T) {
+	params := []struct {
+		constraints string
+		expect      string
+	}{
+		{"NOT NULL", ""},
+		{"NOT NULL CHECK (`status` IN ('pending','paid')) DEFAULT 'pending'", "CHECK (`status` IN ('pending','paid'))"},
+		{"check(status IN ('a)','it''s (shipped)'))", "check(status IN ('a)','it''s (shipped)'))"},
+		{"CHECK (length(`name`) > 0", "CHECK (length(`name`) > 0"},
+	}
+
+	for _, p := range params {
+		if got := parseCheckDefinition(p.constraints); got != p.expect {
+			t.Errorf("parse check definition of %q, expects %q, got %q", p.constraints, p.expect, got)
+		}
+	}
+}
@@ -13,6 +13,12 @@ var errCodes = map[int]error{
 	787:  gorm.ErrForeignKeyViolated,
 }
 
+// The primary result codes that transactions could be retried, 5 SQLITE_BUSY, 6 SQLITE_LOCKED.
+var retryableErrCodes = map[int]bool{
+	5: true,
+	6: true,
+}
+
 type ErrMessage struct {
 	Code         int `json:"Code"`
 	ExtendedCode int `json:"ExtendedCode"`
@@ -38,3 +44,17 @@ func (dialector Dialector) Translate(err error) error {
 	}
 	return err
 }
+
+// Retryable returns whether the transaction could be retried, e.g. the database file is locked.
+func (dialector Dialector) Retryable(err error) bool {
+	parsedErr, marshalErr := json.Marshal(err)
+	if marshalErr != nil {
+		return false
+	}
+
+	var errMsg ErrMessage
+	if unmarshalErr := json.Unmarshal(parsedErr, &errMsg); unmarshalErr != nil {
+		return false
+	}
+	return retryableErrCodes[errMsg.Code]
+}
@@ -105,6 +105,40 @@ func (m Migrator) AlterColumn(value interface{}, name string) error {
 	})
 }
 
+func (m Migrator) MigrateColumn(value interface{}, field *schema.Field, columnType gorm.ColumnType) error {
+	if err := m.Migrator.MigrateColumn(value, field, columnType); err != nil {
+		return err
+	}
+
+	// enum values are checked by the CHECK constraint of column definition, alter column if there are new values
+	values, ok := m.EnumValuesOf(field)
+	if !ok {
+		return nil
+	}
+
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		rawDDL, err := m.getRawDDL(stmt.Table)
+		if err != nil {
+			return err
+		}
+
+		ddl, err := parseDDL(rawDDL)
+		if err != nil {
+			return err
+		}
+
+		for _, f := range ddl.fields {
+			if matches := columnRegexp.FindStringSubmatch(f); len(matches) > 1 && matches[1] == field.DBName {
+				if len(migrator.MissingEnumValues(migrator.ParseEnumValues(parseCheckDefinition(matches[3])), values)) > 0 {
+					return m.AlterColumn(value, field.DBName)
+				}
+				break
+			}
+		}
+		return nil
+	})
+}
+
 // ColumnTypes return columnTypes []gorm.ColumnType and execErr error
 func (m Migrator) ColumnTypes(value interface{}) ([]gorm.ColumnType, error) {
 	columnTypes := make([]gorm.ColumnType, 0)
@@ -257,12 +291,13 @@ func (m Migrator) CreateIndex(value interface{}, name string) error {
 				values := []interface{}{clause.Column{Name: idx.Name}, clause.Table{Name: stmt.Table}, opts}
 
 				createIndexSQL := "CREATE "
-				if idx.Class != "" {
+				// sqlite has no spatial indexes, creates b-tree indexes instead
+				if idx.Class != "" && idx.Class != "SPATIAL" {
 					createIndexSQL += idx.Class + " "
 				}
 				createIndexSQL += "INDEX ?"
 
-				if idx.Type != "" {
+				if idx.Type != "" && idx.Class != "SPATIAL" {
 					createIndexSQL += " USING " + idx.Type
 				}
 				createIndexSQL += " ON ??"
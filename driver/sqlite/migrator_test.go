@@ -0,0 +1,79 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/schema"
+)
+
+type enumStatus string
+
+func (enumStatus) GormEnumValues() []string { return []string{"pending", "paid"} }
+
+func (s enumStatus) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return enumCheckType(s.GormEnumValues(), field)
+}
+
+type enumStatusV2 string
+
+func (enumStatusV2) GormEnumValues() []string { return []string{"pending", "paid", "it's shipped"} }
+
+func (s enumStatusV2) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return enumCheckType(s.GormEnumValues(), field)
+}
+
+func enumCheckType(values []string, field *schema.Field) string {
+	literals := make([]string, len(values))
+	for idx, value := range values {
+		literals[idx] = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+	return "text CHECK (`" + field.DBName + "` IN (" + strings.Join(literals, ",") + "))"
+}
+
+type enumOrder struct {
+	ID     uint
+	Status enumStatus
+}
+
+type enumOrderV2 struct {
+	ID     uint
+	Status enumStatusV2
+}
+
+func (enumOrderV2) TableName() string { return "enum_orders" }
+
+func TestMigrateEnumValues(t *testing.T) {
+	db, err := gorm.Open(Open(filepath.Join(t.TempDir(), "enum.db")), gorm.DBTypeSqlite, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+
+	if err := db.AutoMigrate(&enumOrder{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if err := db.Create(&enumOrderV2{Status: "it's shipped"}).Error; err == nil {
+		t.Fatalf("value not allowed by the CHECK constraint should fail")
+	}
+
+	if err := db.AutoMigrate(&enumOrderV2{}); err != nil {
+		t.Fatalf("failed to migrate new enum values, got error %v", err)
+	}
+
+	if err := db.Create(&enumOrderV2{Status: "it's shipped"}).Error; err != nil {
+		t.Errorf("new enum values should be allowed after migrated, got error %v", err)
+	}
+
+	if err := db.AutoMigrate(&enumOrderV2{}); err != nil {
+		t.Errorf("failed to migrate unchanged enum values, got error %v", err)
+	}
+
+	var count int64
+	db.Model(&enumOrderV2{}).Count(&count)
+	if count != 1 {
+		t.Errorf("rows should be kept after altering column, got %v", count)
+	}
+}
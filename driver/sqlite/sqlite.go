@@ -2,8 +2,8 @@ package sqlite
 
 import (
 	"context"
-	"database/sql"
 	"strconv"
+	"time"
 
 	"github.com/fangxing98/jx-gorm/gorm/callbacks"
 
@@ -22,12 +22,28 @@ type Dialector struct {
 	DriverName string
 	DSN        string
 	Conn       gorm.ConnPool
+	// ConnInitStatements statements executed on every new connection, e.g. PRAGMA foreign_keys = ON
+	ConnInitStatements []string
+	// OnConnect called on every new connection after ConnInitStatements
+	OnConnect gorm.OnConnectFunc
+	// Pool settings of the connection pool opened with DSN
+	Pool gorm.PoolConfig
+	// AdvisoryLockTTL lease of advisory locks, locks not refreshed within it are taken over by others, 30s if zero
+	AdvisoryLockTTL time.Duration
 }
 
 type Config struct {
 	DriverName string
 	DSN        string
 	Conn       gorm.ConnPool
+	// ConnInitStatements statements executed on every new connection, e.g. PRAGMA foreign_keys = ON
+	ConnInitStatements []string
+	// OnConnect called on every new connection after ConnInitStatements
+	OnConnect gorm.OnConnectFunc
+	// Pool settings of the connection pool opened with DSN
+	Pool gorm.PoolConfig
+	// AdvisoryLockTTL lease of advisory locks, locks not refreshed within it are taken over by others, 30s if zero
+	AdvisoryLockTTL time.Duration
 }
 
 func Open(dsn string) gorm.Dialector {
@@ -35,7 +51,15 @@ func Open(dsn string) gorm.Dialector {
 }
 
 func New(config Config) gorm.Dialector {
-	return &Dialector{DSN: config.DSN, DriverName: config.DriverName, Conn: config.Conn}
+	return &Dialector{
+		DSN:                config.DSN,
+		DriverName:         config.DriverName,
+		Conn:               config.Conn,
+		ConnInitStatements: config.ConnInitStatements,
+		OnConnect:          config.OnConnect,
+		Pool:               config.Pool,
+		AdvisoryLockTTL:    config.AdvisoryLockTTL,
+	}
 }
 
 func (dialector Dialector) Name() string {
@@ -50,10 +74,11 @@ func (dialector Dialector) Initialize(db *gorm.DB) (err error) {
 	if dialector.Conn != nil {
 		db.ConnPool = dialector.Conn
 	} else {
-		conn, err := sql.Open(dialector.DriverName, dialector.DSN)
+		conn, err := gorm.OpenDB(dialector.DriverName, dialector.DSN, dialector.ConnInitStatements, dialector.OnConnect)
 		if err != nil {
 			return err
 		}
+		dialector.Pool.Apply(conn)
 		db.ConnPool = conn
 	}
 
@@ -151,6 +176,11 @@ func (dialector Dialector) BindVarTo(writer clause.Writer, stmt *gorm.Statement,
 	writer.WriteByte('?')
 }
 
+// MaxBindVars implements gorm.BindVarsLimiter interface, the default SQLITE_MAX_VARIABLE_NUMBER before 3.32.0
+func (dialector Dialector) MaxBindVars() int {
+	return 999
+}
+
 func (dialector Dialector) QuoteTo(writer clause.Writer, str string) {
 	var (
 		underQuoted, selfQuoted bool
@@ -243,6 +273,10 @@ func (dialectopr Dialector) RollbackTo(tx *gorm.DB, name string) error {
 	return nil
 }
 
+func (dialectopr Dialector) ReleaseSavePoint(tx *gorm.DB, name string) error {
+	return tx.Exec("RELEASE SAVEPOINT " + name).Error
+}
+
 func compareVersion(version1, version2 string) int {
 	n, m := len(version1), len(version2)
 	i, j := 0, 0
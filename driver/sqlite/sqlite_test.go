@@ -100,7 +100,7 @@ func TestDialector(t *testing.T) {
 	}
 	for rowIndex, row := range rows {
 		t.Run(fmt.Sprintf("%d/%s", rowIndex, row.description), func(t *testing.T) {
-			db, err := gorm.Open(row.dialector, &gorm.Config{})
+			db, err := gorm.Open(row.dialector, gorm.DBTypeSqlite, &gorm.Config{})
 			if !row.openSuccess {
 				if err == nil {
 					t.Errorf("Expected Open to fail.")
@@ -0,0 +1,41 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+// TranslateTxOptions implements gorm.TxOptionsDialectorInterface, transactions are serializable in SQLite,
+// the lock mode is set with BEGIN DEFERRED, IMMEDIATE or EXCLUSIVE, read-only transactions are enforced by
+// PRAGMA query_only, which is turned off after the transaction finished
+func (dialector Dialector) TranslateTxOptions(opts gorm.TxOptions) (gorm.TxBeginPlan, error) {
+	switch opts.Isolation {
+	case sql.LevelDefault, sql.LevelSerializable:
+	default:
+		return gorm.TxBeginPlan{}, fmt.Errorf("%w: isolation level %s is not supported by sqlite", gorm.ErrUnsupportedTxOptions, opts.Isolation)
+	}
+
+	if opts.Deferrable {
+		return gorm.TxBeginPlan{}, fmt.Errorf("%w: deferrable transaction is not supported by sqlite", gorm.ErrUnsupportedTxOptions)
+	}
+
+	plan := gorm.TxBeginPlan{Options: &sql.TxOptions{}}
+	switch opts.LockMode {
+	case "":
+	case gorm.TxLockModeDeferred, gorm.TxLockModeImmediate, gorm.TxLockModeExclusive:
+		if opts.ReadOnly && opts.LockMode != gorm.TxLockModeDeferred {
+			return gorm.TxBeginPlan{}, fmt.Errorf("%w: read-only transaction can't be began with %s lock", gorm.ErrUnsupportedTxOptions, opts.LockMode)
+		}
+		plan.Begin = "BEGIN " + string(opts.LockMode)
+	default:
+		return gorm.TxBeginPlan{}, fmt.Errorf("%w: lock mode %s is not supported by sqlite", gorm.ErrUnsupportedTxOptions, opts.LockMode)
+	}
+
+	if opts.ReadOnly {
+		plan.Setup = []string{"PRAGMA query_only = ON"}
+		plan.Teardown = []string{"PRAGMA query_only = OFF"}
+	}
+	return plan, nil
+}
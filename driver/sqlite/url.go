@@ -0,0 +1,19 @@
+package sqlite
+
+import (
+	"strings"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+func init() {
+	gorm.RegisterDialector("sqlite", gorm.DBTypeSqlite, openURL)
+	gorm.RegisterDialector("file", gorm.DBTypeSqlite, Open)
+}
+
+// openURL opens the dialector of sqlite://path/to/gorm.db or sqlite:///absolute/path/to/gorm.db,
+// file: URIs are passed to SQLite as they are, e.g. file:gorm.db?cache=shared
+func openURL(rawURL string) gorm.Dialector {
+	_, path, _ := strings.Cut(rawURL, ":")
+	return Open(strings.TrimPrefix(path, "//"))
+}
@@ -0,0 +1,23 @@
+package sqlserver
+
+import (
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+// AdvisoryLock implements gorm.AdvisoryLockerDialectorInterface with session owned application locks
+func (dialector Dialector) AdvisoryLock(tx *gorm.DB, key string, wait bool) (bool, error) {
+	timeout := 0
+	if wait {
+		timeout = -1
+	}
+
+	var result int
+	err := tx.Raw("DECLARE @result int; EXEC @result = sp_getapplock @Resource = ?, @LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = ?; SELECT @result", key, timeout).Scan(&result).Error
+	// 0 granted synchronously, 1 granted after waiting, negative values are failures
+	return result >= 0, err
+}
+
+// AdvisoryUnlock implements gorm.AdvisoryLockerDialectorInterface
+func (dialector Dialector) AdvisoryUnlock(tx *gorm.DB, key string) error {
+	return tx.Exec("EXEC sp_releaseapplock @Resource = ?, @LockOwner = 'Session'", key).Error
+}
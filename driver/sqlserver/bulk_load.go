@@ -0,0 +1,58 @@
+package sqlserver
+
+import (
+	"context"
+
+	mssql "github.com/microsoft/go-mssqldb"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+// MaxBindVars implements gorm.BindVarsLimiter interface, the max parameters of a request
+func (dialector Dialector) MaxBindVars() int {
+	return 2100
+}
+
+// BulkLoad implements gorm.BulkLoader interface, loads rows with the bulk copy protocol of mssql,
+// the rows are loaded in a transaction as the bulk copy statement must run on a single connection
+func (dialector Dialector) BulkLoad(ctx context.Context, db *gorm.DB, table string, columns []string, source gorm.BulkLoadSource) (rowsAffected int64, err error) {
+	if _, ok := db.Statement.ConnPool.(gorm.TxCommitter); ok {
+		return copyIn(ctx, db.Statement.ConnPool, table, columns, source)
+	}
+
+	err = db.Session(&gorm.Session{NewDB: true}).Transaction(func(tx *gorm.DB) (err error) {
+		rowsAffected, err = copyIn(ctx, tx.Statement.ConnPool, table, columns, source)
+		return err
+	})
+	return
+}
+
+func copyIn(ctx context.Context, conn gorm.ConnPool, table string, columns []string, source gorm.BulkLoadSource) (int64, error) {
+	stmt, err := conn.PrepareContext(ctx, mssql.CopyIn(table, mssql.BulkOptions{}, columns...))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	for source.Next() {
+		values, err := source.Values()
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err = stmt.ExecContext(ctx, values...); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := source.Err(); err != nil {
+		return 0, err
+	}
+
+	// flush the rows
+	result, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
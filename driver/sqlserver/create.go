@@ -19,6 +19,7 @@ func Create(db *gorm.DB) {
 		}
 	}
 
+	var withUpsertStatus bool
 	if db.Statement.SQL.String() == "" {
 		var (
 			values                  = callbacks.ConvertToCreateValues(db.Statement)
@@ -27,14 +28,21 @@ func Create(db *gorm.DB) {
 		)
 
 		if hasConflict {
-			if len(db.Statement.Schema.PrimaryFields) > 0 {
+			conflictColumns := onConflict.Columns
+			if len(conflictColumns) == 0 {
+				for _, field := range db.Statement.Schema.PrimaryFields {
+					conflictColumns = append(conflictColumns, clause.Column{Name: field.DBName})
+				}
+			}
+
+			if len(conflictColumns) > 0 {
 				columnsMap := map[string]bool{}
 				for _, column := range values.Columns {
 					columnsMap[column.Name] = true
 				}
 
-				for _, field := range db.Statement.Schema.PrimaryFields {
-					if _, ok := columnsMap[field.DBName]; !ok {
+				for _, column := range conflictColumns {
+					if _, ok := columnsMap[column.Name]; !ok {
 						hasConflict = false
 					}
 				}
@@ -44,6 +52,7 @@ func Create(db *gorm.DB) {
 		}
 
 		if hasConflict {
+			_, withUpsertStatus = db.InstanceGet("gorm:upsert_statuses")
 			MergeCreate(db, onConflict, values)
 		} else {
 			setIdentityInsert := false
@@ -118,7 +127,7 @@ func Create(db *gorm.DB) {
 	}
 
 	if !db.DryRun && db.Error == nil {
-		if db.Statement.Schema != nil && len(db.Statement.Schema.FieldsWithDefaultDBValue) > 0 {
+		if db.Statement.Schema != nil && (len(db.Statement.Schema.FieldsWithDefaultDBValue) > 0 || withUpsertStatus) {
 			rows, err := db.Statement.ConnPool.QueryContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
 			if db.AddError(err) == nil {
 				defer rows.Close()
@@ -157,16 +166,30 @@ func MergeCreate(db *gorm.DB, onConflict clause.OnConflict, values clause.Values
 	db.Statement.WriteString(") ON ")
 
 	var where clause.Where
-	for _, field := range db.Statement.Schema.PrimaryFields {
-		where.Exprs = append(where.Exprs, clause.Eq{
-			Column: clause.Column{Table: db.Statement.Table, Name: field.DBName},
-			Value:  clause.Column{Table: "excluded", Name: field.DBName},
-		})
+	if len(onConflict.Columns) > 0 {
+		for _, column := range onConflict.Columns {
+			where.Exprs = append(where.Exprs, clause.Eq{
+				Column: clause.Column{Table: db.Statement.Table, Name: column.Name},
+				Value:  clause.Column{Table: "excluded", Name: column.Name},
+			})
+		}
+	} else {
+		for _, field := range db.Statement.Schema.PrimaryFields {
+			where.Exprs = append(where.Exprs, clause.Eq{
+				Column: clause.Column{Table: db.Statement.Table, Name: field.DBName},
+				Value:  clause.Column{Table: "excluded", Name: field.DBName},
+			})
+		}
 	}
 	where.Build(db.Statement)
 
-	if len(onConflict.DoUpdates) > 0 {
-		db.Statement.WriteString(" WHEN MATCHED THEN UPDATE SET ")
+	if len(onConflict.DoUpdates) > 0 && !onConflict.DoNothing {
+		db.Statement.WriteString(" WHEN MATCHED")
+		if len(onConflict.Where.Exprs) > 0 {
+			db.Statement.WriteString(" AND ")
+			onConflict.Where.Build(db.Statement)
+		}
+		db.Statement.WriteString(" THEN UPDATE SET ")
 		onConflict.DoUpdates.Build(db.Statement)
 	}
 
@@ -201,6 +224,17 @@ func MergeCreate(db *gorm.DB, onConflict clause.OnConflict, values clause.Values
 
 	db.Statement.WriteString(")")
 	outputInserted(db)
+
+	// report upserted rows are inserted or updated
+	if _, ok := db.InstanceGet("gorm:upsert_statuses"); ok {
+		if db.Statement.Schema != nil && len(db.Statement.Schema.FieldsWithDefaultDBValue) > 0 {
+			db.Statement.WriteString(",")
+		} else {
+			db.Statement.WriteString(" OUTPUT")
+		}
+		db.Statement.WriteString(" $action AS ")
+		db.Statement.WriteQuoted(gorm.UpsertStatusColumn)
+	}
 	db.Statement.WriteString(";")
 }
 
@@ -1,6 +1,8 @@
 package sqlserver
 
 import (
+	"errors"
+
 	"github.com/microsoft/go-mssqldb"
 
 	"github.com/fangxing98/jx-gorm/gorm"
@@ -10,6 +12,12 @@ import (
 var errCodes = map[int32]error{
 	2627: gorm.ErrDuplicatedKey,
 	547:  gorm.ErrForeignKeyViolated,
+	1222: gorm.ErrLockNotAvailable,
+}
+
+// The error codes that transactions could be retried, 1205 transaction was deadlocked and chosen as the victim.
+var retryableErrCodes = map[int32]bool{
+	1205: true,
 }
 
 type ErrMessage struct {
@@ -28,3 +36,9 @@ func (dialector Dialector) Translate(err error) error {
 
 	return err
 }
+
+// Retryable returns whether the transaction could be retried, e.g. deadlocks.
+func (dialector Dialector) Retryable(err error) bool {
+	var mssqlErr mssql.Error
+	return errors.As(err, &mssqlErr) && retryableErrCodes[mssqlErr.Number]
+}
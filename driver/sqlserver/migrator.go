@@ -2,6 +2,7 @@ package sqlserver
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -48,6 +49,9 @@ func (m Migrator) CreateTable(values ...interface{}) (err error) {
 			}
 			for _, fieldName := range stmt.Schema.DBNames {
 				field := stmt.Schema.FieldsByDBName[fieldName]
+				if err = m.migrateEnumCheck(stmt, field); err != nil {
+					return
+				}
 				if field.Comment == "" {
 					continue
 				}
@@ -202,6 +206,9 @@ func (m Migrator) AddColumn(value interface{}, name string) error {
 	return m.RunWithValue(value, func(stmt *gorm.Statement) (err error) {
 		if stmt.Schema != nil {
 			if field := stmt.Schema.LookUpField(name); field != nil {
+				if err = m.migrateEnumCheck(stmt, field); err != nil {
+					return
+				}
 				if field.Comment == "" {
 					return
 				}
@@ -293,6 +300,10 @@ func (m Migrator) MigrateColumn(value interface{}, field *schema.Field, columnTy
 	}
 
 	return m.RunWithValue(value, func(stmt *gorm.Statement) (err error) {
+		if err = m.migrateEnumCheck(stmt, field); err != nil {
+			return
+		}
+
 		description := m.GetColumnComment(stmt, field.DBName)
 		if field.Comment != description {
 			if description == "" {
@@ -305,6 +316,44 @@ func (m Migrator) MigrateColumn(value interface{}, field *schema.Field, columnTy
 	})
 }
 
+// migrateEnumCheck create the check constraint of enum field, recreate it if there are new enum values
+func (m Migrator) migrateEnumCheck(stmt *gorm.Statement, field *schema.Field) error {
+	values, ok := m.EnumValuesOf(field)
+	if !ok || field.IgnoreMigration {
+		return nil
+	}
+
+	var (
+		definition sql.NullString
+		name       = m.DB.NamingStrategy.CheckerName(stmt.Table, field.DBName)
+		table      = clause.Table{Name: getFullQualifiedTableName(stmt)}
+	)
+	if err := m.DB.Raw(
+		"SELECT definition FROM sys.check_constraints WHERE name = ? AND parent_object_id = OBJECT_ID(?)",
+		name, getFullQualifiedTableName(stmt),
+	).Row().Scan(&definition); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if definition.Valid {
+		if len(migrator.MissingEnumValues(migrator.ParseEnumValues(definition.String), values)) == 0 {
+			return nil
+		}
+		if err := m.DB.Exec("ALTER TABLE ? DROP CONSTRAINT ?", table, clause.Column{Name: name}).Error; err != nil {
+			return err
+		}
+	}
+
+	literals := make([]string, len(values))
+	for idx, value := range values {
+		literals[idx] = "N'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+	return m.DB.Exec(
+		"ALTER TABLE ? ADD CONSTRAINT ? CHECK (? IN (?))",
+		table, clause.Column{Name: name}, clause.Column{Name: field.DBName}, clause.Expr{SQL: strings.Join(literals, ",")},
+	).Error
+}
+
 var defaultValueTrimRegexp = regexp.MustCompile("^\\('?([^']*)'?\\)$")
 
 // ColumnTypes return columnTypes []gorm.ColumnType and execErr error
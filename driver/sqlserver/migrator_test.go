@@ -49,7 +49,7 @@ type Testtable5 struct {
 func (*Testtable5) TableName() string { return "testschema2.Testtables" }
 
 func TestAutomigrateTablesWithoutDefaultSchema(t *testing.T) {
-	db, err := gorm.Open(sqlserver.Open(sqlserverDSN))
+	db, err := gorm.Open(sqlserver.Open(sqlserverDSN), gorm.DBTypeSQLServer)
 	if err != nil {
 		t.Error(err)
 	}
@@ -106,7 +106,7 @@ type Testtable6 struct {
 func (*Testtable6) TableName() string { return "testtable" }
 
 func TestCreateIndex(t *testing.T) {
-	db, err := gorm.Open(sqlserver.Open(sqlserverDSN))
+	db, err := gorm.Open(sqlserver.Open(sqlserverDSN), gorm.DBTypeSQLServer)
 	if err != nil {
 		t.Error(err)
 	}
@@ -131,7 +131,7 @@ type TestTableDefaultValue struct {
 func (*TestTableDefaultValue) TableName() string { return "test_table_default_value" }
 
 func TestReMigrateTableFieldsWithoutDefaultValue(t *testing.T) {
-	db, err := gorm.Open(sqlserver.Open(sqlserverDSN))
+	db, err := gorm.Open(sqlserver.Open(sqlserverDSN), gorm.DBTypeSQLServer)
 	if err != nil {
 		t.Error(err)
 	}
@@ -207,7 +207,7 @@ type TestTableFieldCommentUpdate struct {
 func (*TestTableFieldCommentUpdate) TableName() string { return "test_table_field_comment" }
 
 func TestMigrator_MigrateColumnComment(t *testing.T) {
-	db, err := gorm.Open(sqlserver.Open(sqlserverDSN))
+	db, err := gorm.Open(sqlserver.Open(sqlserverDSN), gorm.DBTypeSQLServer)
 	if err != nil {
 		t.Error(err)
 	}
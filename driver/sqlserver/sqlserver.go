@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fangxing98/jx-gorm/gorm"
 	"github.com/fangxing98/jx-gorm/gorm/callbacks"
@@ -21,6 +22,12 @@ type Config struct {
 	DSN               string
 	DefaultStringSize int
 	Conn              gorm.ConnPool
+	// ConnInitStatements statements executed on every new connection, e.g. SET ANSI_NULLS ON
+	ConnInitStatements []string
+	// OnConnect called on every new connection after ConnInitStatements
+	OnConnect gorm.OnConnectFunc
+	// Pool settings of the connection pool opened with DSN
+	Pool gorm.PoolConfig
 }
 
 type Dialector struct {
@@ -57,10 +64,12 @@ func (dialector Dialector) Initialize(db *gorm.DB) (err error) {
 	if dialector.Conn != nil {
 		db.ConnPool = dialector.Conn
 	} else {
-		db.ConnPool, err = sql.Open(dialector.DriverName, dialector.DSN)
-		if err != nil {
+		var sqlDB *sql.DB
+		if sqlDB, err = gorm.OpenDB(dialector.DriverName, dialector.DSN, dialector.ConnInitStatements, dialector.OnConnect); err != nil {
 			return err
 		}
+		dialector.Pool.Apply(sqlDB)
+		db.ConnPool = sqlDB
 	}
 
 	for k, v := range dialector.ClauseBuilders() {
@@ -71,6 +80,45 @@ func (dialector Dialector) Initialize(db *gorm.DB) (err error) {
 
 func (dialector Dialector) ClauseBuilders() map[string]clause.ClauseBuilder {
 	return map[string]clause.ClauseBuilder{
+		"FROM": func(c clause.Clause, builder clause.Builder) {
+			if from, ok := c.Expression.(clause.From); ok {
+				if stmt, ok := builder.(*gorm.Statement); ok {
+					// SQL Server locks rows with table hints instead of FOR UPDATE
+					if locking, ok := stmt.Clauses["FOR"].Expression.(clause.Locking); ok {
+						tables := from.Tables
+						if len(tables) == 0 {
+							tables = []clause.Table{{Name: clause.CurrentTable}}
+						}
+
+						builder.WriteString("FROM ")
+						for idx, table := range tables {
+							if idx > 0 {
+								builder.WriteByte(',')
+							}
+							builder.WriteQuoted(table)
+
+							if name := locking.Table.Name; name == "" || name == table.Name || name == table.Alias || (table.Name == clause.CurrentTable && name == stmt.Table) {
+								builder.WriteString(" WITH (" + lockingHints(locking) + ")")
+							}
+						}
+
+						for _, join := range from.Joins {
+							builder.WriteByte(' ')
+							join.Build(builder)
+						}
+						return
+					}
+				}
+			}
+			c.Build(builder)
+		},
+		"FOR": func(c clause.Clause, builder clause.Builder) {
+			if _, ok := c.Expression.(clause.Locking); ok {
+				// built as table hints of the FROM clause
+				return
+			}
+			c.Build(builder)
+		},
 		"LIMIT": func(c clause.Clause, builder clause.Builder) {
 			if limit, ok := c.Expression.(clause.Limit); ok {
 				if stmt, ok := builder.(*gorm.Statement); ok {
@@ -132,6 +180,22 @@ func (dialector Dialector) ClauseBuilders() map[string]clause.ClauseBuilder {
 	}
 }
 
+// lockingHints returns table hints of the locking clause, e.g. UPDLOCK, ROWLOCK, READPAST
+func lockingHints(locking clause.Locking) string {
+	hints := "UPDLOCK, ROWLOCK"
+	if strings.EqualFold(locking.Strength, clause.LockingStrengthShare) {
+		hints = "HOLDLOCK, ROWLOCK"
+	}
+
+	switch strings.ToUpper(locking.Options) {
+	case clause.LockingOptionsSkipLocked:
+		hints += ", READPAST"
+	case clause.LockingOptionsNoWait:
+		hints += ", NOWAIT"
+	}
+	return hints
+}
+
 func (dialector Dialector) DefaultValueOf(field *schema.Field) clause.Expression {
 	return clause.Expr{SQL: "NULL"}
 }
@@ -243,3 +307,13 @@ func (dialectopr Dialector) RollbackTo(tx *gorm.DB, name string) error {
 	tx.Exec("ROLLBACK TRANSACTION " + name)
 	return nil
 }
+
+// ReleaseSavePoint SQL Server doesn't support releasing savepoints, they are released when the transaction finished
+func (dialectopr Dialector) ReleaseSavePoint(tx *gorm.DB, name string) error {
+	return nil
+}
+
+// LockTimeout implements gorm.LockTimeoutDialectorInterface
+func (dialectopr Dialector) LockTimeout(timeout time.Duration) (string, string) {
+	return fmt.Sprintf("SET LOCK_TIMEOUT %d", max(timeout.Milliseconds(), 1)), "SET LOCK_TIMEOUT -1"
+}
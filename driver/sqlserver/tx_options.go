@@ -0,0 +1,44 @@
+package sqlserver
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+var isolationLevels = map[sql.IsolationLevel]string{
+	sql.LevelReadUncommitted: "READ UNCOMMITTED",
+	sql.LevelReadCommitted:   "READ COMMITTED",
+	sql.LevelRepeatableRead:  "REPEATABLE READ",
+	sql.LevelSnapshot:        "SNAPSHOT",
+	sql.LevelSerializable:    "SERIALIZABLE",
+}
+
+// TranslateTxOptions implements gorm.TxOptionsDialectorInterface, isolation level is set on the connection before
+// the transaction began, snapshot isolation can't be changed once the transaction started
+func (dialector Dialector) TranslateTxOptions(opts gorm.TxOptions) (gorm.TxBeginPlan, error) {
+	if opts.ReadOnly {
+		return gorm.TxBeginPlan{}, fmt.Errorf("%w: read-only transaction is not supported by sqlserver", gorm.ErrUnsupportedTxOptions)
+	}
+
+	if opts.Deferrable {
+		return gorm.TxBeginPlan{}, fmt.Errorf("%w: deferrable transaction is not supported by sqlserver", gorm.ErrUnsupportedTxOptions)
+	}
+
+	if opts.LockMode != "" {
+		return gorm.TxBeginPlan{}, fmt.Errorf("%w: lock mode is not supported by sqlserver", gorm.ErrUnsupportedTxOptions)
+	}
+
+	plan := gorm.TxBeginPlan{Options: &sql.TxOptions{}}
+	if opts.Isolation != sql.LevelDefault {
+		level, ok := isolationLevels[opts.Isolation]
+		if !ok {
+			return gorm.TxBeginPlan{}, fmt.Errorf("%w: isolation level %s is not supported by sqlserver", gorm.ErrUnsupportedTxOptions, opts.Isolation)
+		}
+		plan.Setup = []string{"SET TRANSACTION ISOLATION LEVEL " + level}
+		// the isolation level outlives the transaction on the connection, restore the default of sqlserver
+		plan.Teardown = []string{"SET TRANSACTION ISOLATION LEVEL READ COMMITTED"}
+	}
+	return plan, nil
+}
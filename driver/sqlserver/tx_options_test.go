@@ -0,0 +1,37 @@
+package sqlserver
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+func TestDialector_TranslateTxOptions(t *testing.T) {
+	dialector := Dialector{}
+
+	plan, err := dialector.TranslateTxOptions(gorm.TxOptions{Isolation: sql.LevelSnapshot})
+	if err != nil {
+		t.Fatalf("failed to translate options, got error %v", err)
+	}
+	if !reflect.DeepEqual(plan.Setup, []string{"SET TRANSACTION ISOLATION LEVEL SNAPSHOT"}) || !reflect.DeepEqual(plan.Teardown, []string{"SET TRANSACTION ISOLATION LEVEL READ COMMITTED"}) {
+		t.Errorf("isolation level should be set before the transaction began and restored after, got %+v", plan)
+	}
+
+	if plan, err := dialector.TranslateTxOptions(gorm.TxOptions{}); err != nil || len(plan.Setup) != 0 || len(plan.Teardown) != 0 {
+		t.Errorf("default options should begin the transaction with BeginTx, got %+v, %v", plan, err)
+	}
+
+	for _, opts := range []gorm.TxOptions{
+		{ReadOnly: true},
+		{Deferrable: true},
+		{Isolation: sql.LevelLinearizable},
+		{LockMode: gorm.TxLockModeImmediate},
+	} {
+		if _, err := dialector.TranslateTxOptions(opts); !errors.Is(err, gorm.ErrUnsupportedTxOptions) {
+			t.Errorf("options %+v should be unsupported, got %v", opts, err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package gorm
+
+import (
+	"context"
+	"sync"
+)
+
+// Unlock releases the advisory lock, it is safe to be called more than once
+type Unlock func() error
+
+// AdvisoryLockerDialectorInterface acquires and releases advisory locks on the connection of tx,
+// AdvisoryLock waits until the lock is acquired if wait is true, otherwise returns false if the lock is held by others
+type AdvisoryLockerDialectorInterface interface {
+	AdvisoryLock(tx *DB, key string, wait bool) (acquired bool, err error)
+	AdvisoryUnlock(tx *DB, key string) error
+}
+
+// AdvisoryLock acquires the advisory lock of key, waits until the lock is released by others or ctx is done.
+// The lock is held by a dedicated connection, and released by Unlock or when ctx is done.
+//
+//	unlock, err := db.AdvisoryLock(ctx, "jobs:daily-report")
+//	if err != nil {
+//		return err
+//	}
+//	defer unlock()
+func (db *DB) AdvisoryLock(ctx context.Context, key string) (Unlock, error) {
+	return db.advisoryLock(ctx, key, true)
+}
+
+// TryAdvisoryLock acquires the advisory lock of key like AdvisoryLock, returns ErrLockNotAvailable
+// immediately if the lock is held by others
+func (db *DB) TryAdvisoryLock(ctx context.Context, key string) (Unlock, error) {
+	return db.advisoryLock(ctx, key, false)
+}
+
+func (db *DB) advisoryLock(ctx context.Context, key string, wait bool) (Unlock, error) {
+	locker, ok := db.Dialector.(AdvisoryLockerDialectorInterface)
+	if !ok {
+		return nil, ErrUnsupportedDriver
+	}
+
+	var (
+		locked  = make(chan error, 1)
+		release = make(chan struct{})
+		done    = make(chan error, 1)
+	)
+
+	// pin the lock to a connection, the connection is returned to the pool after the lock released
+	go func() {
+		done <- db.WithContext(ctx).Connection(func(tx *DB) error {
+			acquired, err := locker.AdvisoryLock(tx.Session(&Session{NewDB: true}), key, wait)
+			if err == nil && !acquired {
+				err = ErrLockNotAvailable
+			}
+
+			locked <- err
+			if err != nil {
+				return err
+			}
+
+			select {
+			case <-release:
+			case <-ctx.Done():
+			}
+			// release with a new context as ctx might be canceled
+			return locker.AdvisoryUnlock(tx.Session(&Session{NewDB: true, Context: context.Background()}), key)
+		})
+	}()
+
+	select {
+	case err := <-locked:
+		if err != nil {
+			<-done
+			return nil, err
+		}
+	case err := <-done:
+		// failed to get a connection
+		return nil, err
+	}
+
+	var (
+		once      sync.Once
+		unlockErr error
+	)
+	return func() error {
+		once.Do(func() {
+			close(release)
+			unlockErr = <-done
+		})
+		return unlockErr
+	}, nil
+}
@@ -0,0 +1,89 @@
+package gorm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/internal/testdb"
+)
+
+func TestAdvisoryLock(t *testing.T) {
+	db := testdb.Open(t, nil)
+	ctx := context.Background()
+
+	unlock, err := db.AdvisoryLock(ctx, "daily-report")
+	if err != nil {
+		t.Fatalf("failed to acquire lock, got error %v", err)
+	}
+
+	if _, err := db.TryAdvisoryLock(ctx, "daily-report"); !errors.Is(err, gorm.ErrLockNotAvailable) {
+		t.Errorf("lock should be held, got error %v", err)
+	}
+
+	unlock2, err := db.TryAdvisoryLock(ctx, "weekly-report")
+	if err != nil {
+		t.Fatalf("locks of other keys should be acquired, got error %v", err)
+	}
+	unlock2()
+
+	acquired := make(chan gorm.Unlock)
+	go func() {
+		unlock, err := db.AdvisoryLock(ctx, "daily-report")
+		if err != nil {
+			t.Errorf("failed to wait for lock, got error %v", err)
+		}
+		acquired <- unlock
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatalf("lock should not be acquired before released")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if err := unlock(); err != nil {
+		t.Errorf("failed to release lock, got error %v", err)
+	}
+	unlock()
+
+	select {
+	case unlock := <-acquired:
+		unlock()
+	case <-time.After(5 * time.Second):
+		t.Fatalf("lock should be acquired after released")
+	}
+}
+
+func TestAdvisoryLockReleasedOnContextDone(t *testing.T) {
+	db := testdb.Open(t, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	unlock, err := db.AdvisoryLock(ctx, "daily-report")
+	if err != nil {
+		t.Fatalf("failed to acquire lock, got error %v", err)
+	}
+	cancel()
+
+	if err := unlock(); err != nil {
+		t.Errorf("lock should be released after context canceled, got error %v", err)
+	}
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer waitCancel()
+	unlock, err = db.AdvisoryLock(waitCtx, "daily-report")
+	if err != nil {
+		t.Fatalf("lock should be acquired after released, got error %v", err)
+	}
+	unlock()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	hold, _ := db.AdvisoryLock(context.Background(), "daily-report")
+	defer hold()
+	if _, err := db.AdvisoryLock(ctx, "daily-report"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("waiting for lock should stop after context done, got error %v", err)
+	}
+}
@@ -58,6 +58,14 @@ func (association *Association) Find(out interface{}, conds ...interface{}) erro
 }
 
 func (association *Association) Append(values ...interface{}) error {
+	owner := association.hookOwner()
+	association.callHooks(owner, func(value interface{}, tx *DB) error {
+		if i, ok := value.(BeforeAssociationAppendInterface); ok {
+			return NewHookError("BeforeAssociationAppend", i.BeforeAssociationAppend(tx, association.Relationship.Name, values))
+		}
+		return nil
+	})
+
 	if association.Error == nil {
 		switch association.Relationship.Type {
 		case schema.HasOne, schema.BelongsTo:
@@ -69,6 +77,13 @@ func (association *Association) Append(values ...interface{}) error {
 		}
 	}
 
+	association.callHooks(owner, func(value interface{}, tx *DB) error {
+		if i, ok := value.(AfterAssociationAppendInterface); ok {
+			return NewHookError("AfterAssociationAppend", i.AfterAssociationAppend(tx, association.Relationship.Name, values))
+		}
+		return nil
+	})
+
 	return association.Error
 }
 
@@ -195,6 +210,14 @@ func (association *Association) Replace(values ...interface{}) error {
 }
 
 func (association *Association) Delete(values ...interface{}) error {
+	owner := association.hookOwner()
+	association.callHooks(owner, func(value interface{}, tx *DB) error {
+		if i, ok := value.(BeforeAssociationDeleteInterface); ok {
+			return NewHookError("BeforeAssociationDelete", i.BeforeAssociationDelete(tx, association.Relationship.Name, values))
+		}
+		return nil
+	})
+
 	if association.Error == nil {
 		var (
 			reflectValue  = association.DB.Statement.ReflectValue
@@ -357,6 +380,13 @@ func (association *Association) Delete(values ...interface{}) error {
 		}
 	}
 
+	association.callHooks(owner, func(value interface{}, tx *DB) error {
+		if i, ok := value.(AfterAssociationDeleteInterface); ok {
+			return NewHookError("AfterAssociationDelete", i.AfterAssociationDelete(tx, association.Relationship.Name, values))
+		}
+		return nil
+	})
+
 	return association.Error
 }
 
@@ -562,6 +592,39 @@ func (association *Association) saveAssociation(clear bool, values ...interface{
 	}
 }
 
+// hookOwner returns the owner value to call association hooks, as the statement might be changed when saving associations
+func (association *Association) hookOwner() reflect.Value {
+	if association.DB.Statement.SkipHooks {
+		return reflect.Value{}
+	}
+	return association.DB.Statement.ReflectValue
+}
+
+// callHooks calls association hooks on the owner model, or on each owner if the model is a slice
+func (association *Association) callHooks(owner reflect.Value, fc func(value interface{}, tx *DB) error) {
+	if association.Error != nil || !owner.IsValid() {
+		return
+	}
+
+	tx := association.DB.Session(&Session{NewDB: true})
+	switch owner.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < owner.Len() && association.Error == nil; i++ {
+			if value := reflect.Indirect(owner.Index(i)); value.CanAddr() {
+				association.Error = fc(value.Addr().Interface(), tx)
+			} else {
+				association.Error = ErrInvalidValue
+			}
+		}
+	case reflect.Struct:
+		if owner.CanAddr() {
+			association.Error = fc(owner.Addr().Interface(), tx)
+		} else {
+			association.Error = fc(association.DB.Statement.Model, tx)
+		}
+	}
+}
+
 func (association *Association) buildCondition() *DB {
 	var (
 		queryConds = association.Relationship.ToQueryConditions(association.DB.Statement.Context, association.DB.Statement.ReflectValue)
@@ -0,0 +1,301 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/gorm/clause"
+	"github.com/fangxing98/jx-gorm/gorm/schema"
+)
+
+// DefaultBulkLoadBatchSize default rows of an insert statement when bulk loading with prepared inserts
+const DefaultBulkLoadBatchSize = 500
+
+// defaultMaxBindVars max bind vars of a statement if the dialector doesn't implement BindVarsLimiter, 999 of SQLite
+const defaultMaxBindVars = 999
+
+// BulkLoadOptions options of BulkLoad
+type BulkLoadOptions struct {
+	// Table loading table, the table of the schema if empty
+	Table string
+	// Columns loading columns, fields or columns, all creatable columns if empty
+	Columns []string
+	// BatchSize rows of an insert statement when loading with prepared inserts
+	BatchSize int
+}
+
+// BulkLoadSource rows source of BulkLoader, compatible with pgx.CopyFromSource
+type BulkLoadSource interface {
+	// Next advances to the next row, returns false if no more rows or error happened
+	Next() bool
+	// Values returns the values of current row, in the order of the columns
+	Values() ([]interface{}, error)
+	// Err returns the error happened
+	Err() error
+}
+
+// BulkLoader dialector interface to load rows with the native bulk copy protocol, returns ErrNotImplemented to
+// load with prepared inserts
+type BulkLoader interface {
+	BulkLoad(ctx context.Context, db *DB, table string, columns []string, source BulkLoadSource) (int64, error)
+}
+
+// BindVarsLimiter dialector interface to report the max bind vars of a statement, e.g. 65535 of PostgreSQL
+type BindVarsLimiter interface {
+	MaxBindVars() int
+}
+
+// BulkLoad loads rows (slice of structs) into the database with the native bulk copy protocol of the dialector,
+// falls back to chunked prepared inserts in a single transaction, hooks and associations are skipped.
+//
+// The bulk copy protocol might be unavailable on some connections, e.g. Postgres can't COPY FROM within transactions
+// as the pgx connection of a database/sql transaction is unreachable, a warning is logged when falling back
+func (db *DB) BulkLoad(ctx context.Context, rows interface{}, opts BulkLoadOptions) (tx *DB) {
+	tx = db.getInstance().WithContext(ctx)
+	stmt := tx.Statement
+
+	source, err := newBulkLoadSource(stmt, rows, opts)
+	if err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	table := opts.Table
+	if table == "" {
+		table = stmt.Table
+	}
+
+	var (
+		begin    = time.Now()
+		affected int64
+		loaded   bool
+	)
+
+	if loader, ok := tx.Dialector.(BulkLoader); ok {
+		affected, err = loader.BulkLoad(ctx, tx, table, source.columns, source)
+		if loaded = !errors.Is(err, ErrNotImplemented); !loaded {
+			tx.Logger.Warn(ctx, "bulk copy of %s is unavailable on the connection, loading %s with prepared inserts", tx.Dialector.Name(), table)
+		}
+	}
+
+	if !loaded {
+		affected, err = tx.bulkInsert(ctx, table, source, opts.BatchSize)
+	}
+
+	tx.RowsAffected = affected
+	tx.Logger.Trace(ctx, begin, func() (string, int64) {
+		return fmt.Sprintf("BULK LOAD %s (%s)", table, strings.Join(source.columns, ",")), affected
+	}, err)
+	tx.AddError(err)
+	return
+}
+
+// bulkInsert inserts rows with prepared multi-rows inserts in a single transaction
+func (db *DB) bulkInsert(ctx context.Context, table string, source *bulkLoadSource, batchSize int) (affected int64, err error) {
+	if batchSize <= 0 {
+		batchSize = DefaultBulkLoadBatchSize
+	}
+
+	// keep the bind vars of a statement under the limit of the database
+	maxBindVars := defaultMaxBindVars
+	if limiter, ok := db.Dialector.(BindVarsLimiter); ok && limiter.MaxBindVars() > 0 {
+		maxBindVars = limiter.MaxBindVars()
+	}
+	if maxRows := maxBindVars / len(source.columns); batchSize > maxRows && maxRows > 0 {
+		batchSize = maxRows
+	}
+
+	err = db.Session(&Session{NewDB: true, SkipDefaultTransaction: true}).Transaction(func(tx *DB) error {
+		var (
+			prepared     *sql.Stmt
+			preparedRows int
+			batch        = make([]interface{}, 0, batchSize*len(source.columns))
+		)
+
+		defer func() {
+			if prepared != nil {
+				prepared.Close()
+			}
+		}()
+
+		flush := func() error {
+			rows := len(batch) / len(source.columns)
+			if rows == 0 {
+				return nil
+			}
+
+			if rows != preparedRows {
+				if prepared != nil {
+					prepared.Close()
+				}
+
+				var err error
+				if prepared, err = tx.Statement.ConnPool.PrepareContext(ctx, tx.bulkInsertSQL(table, source.columns, rows)); err != nil {
+					prepared = nil
+					return err
+				}
+				preparedRows = rows
+			}
+
+			result, err := prepared.ExecContext(ctx, batch...)
+			if err != nil {
+				return err
+			}
+
+			count, _ := result.RowsAffected()
+			affected += count
+			batch = batch[:0]
+			return nil
+		}
+
+		for source.Next() {
+			values, _ := source.Values()
+			if batch = append(batch, values...); len(batch) >= cap(batch) {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := source.Err(); err != nil {
+			return err
+		}
+		return flush()
+	})
+	return
+}
+
+func (db *DB) bulkInsertSQL(table string, columns []string, rows int) string {
+	stmt := &Statement{DB: db, Table: table, Context: db.Statement.Context}
+	stmt.WriteString("INSERT INTO ")
+	stmt.WriteQuoted(clause.Table{Name: table})
+	stmt.WriteString(" (")
+	for idx, column := range columns {
+		if idx > 0 {
+			stmt.WriteByte(',')
+		}
+		stmt.WriteQuoted(column)
+	}
+	stmt.WriteString(") VALUES ")
+
+	placeholders := make([]interface{}, len(columns))
+	for i := 0; i < rows; i++ {
+		if i > 0 {
+			stmt.WriteByte(',')
+		}
+		stmt.WriteByte('(')
+		stmt.AddVar(stmt, placeholders...)
+		stmt.WriteByte(')')
+	}
+	return stmt.SQL.String()
+}
+
+// bulkLoadSource converts rows to values of columns with the schema, serializers and default values are applied
+type bulkLoadSource struct {
+	stmt         *Statement
+	reflectValue reflect.Value
+	fields       []*schema.Field
+	columns      []string
+	now          time.Time
+	index        int
+	values       []interface{}
+	err          error
+}
+
+func newBulkLoadSource(stmt *Statement, rows interface{}, opts BulkLoadOptions) (*bulkLoadSource, error) {
+	if err := stmt.Parse(rows); err != nil {
+		return nil, err
+	}
+
+	reflectValue := reflect.Indirect(reflect.ValueOf(rows))
+	if kind := reflectValue.Kind(); kind != reflect.Slice && kind != reflect.Array {
+		return nil, fmt.Errorf("%w: bulk load requires a slice, got %T", ErrInvalidData, rows)
+	}
+
+	source := &bulkLoadSource{stmt: stmt, reflectValue: reflectValue, now: stmt.DB.NowFunc(), index: -1}
+	selected := map[string]bool{}
+	for _, column := range opts.Columns {
+		if field := stmt.Schema.LookUpField(column); field != nil {
+			column = field.DBName
+		}
+		selected[column] = true
+	}
+
+	for _, dbName := range stmt.Schema.DBNames {
+		field := stmt.Schema.FieldsByDBName[dbName]
+		if !field.Creatable || (len(selected) > 0 && !selected[dbName]) {
+			continue
+		}
+
+		// skip the fields with database default values, e.g. auto increment primary keys, if no value is provided
+		if len(selected) == 0 && field.HasDefaultValue && field.DefaultValueInterface == nil && field.AutoCreateTime == 0 && field.AutoUpdateTime == 0 {
+			provided := false
+			for i := 0; i < reflectValue.Len() && !provided; i++ {
+				_, zero := field.ValueOf(stmt.Context, reflectValue.Index(i))
+				provided = !zero
+			}
+
+			if !provided {
+				continue
+			}
+		}
+
+		source.fields = append(source.fields, field)
+		source.columns = append(source.columns, dbName)
+	}
+
+	if len(source.columns) == 0 {
+		return nil, ErrModelAccessibleFieldsRequired
+	}
+
+	source.values = make([]interface{}, len(source.fields))
+	return source, nil
+}
+
+// Next implements BulkLoadSource interface
+func (source *bulkLoadSource) Next() bool {
+	if source.err != nil || source.index+1 >= source.reflectValue.Len() {
+		return false
+	}
+
+	source.index++
+	rv := reflect.Indirect(source.reflectValue.Index(source.index))
+	for idx, field := range source.fields {
+		value, zero := field.ValueOf(source.stmt.Context, rv)
+		if zero {
+			switch {
+			case field.DefaultValueInterface != nil:
+				value = field.DefaultValueInterface
+			case field.AutoCreateTime > 0 || field.AutoUpdateTime > 0:
+				if source.err = field.Set(source.stmt.Context, rv, source.now); source.err != nil {
+					return false
+				}
+				value, _ = field.ValueOf(source.stmt.Context, rv)
+			}
+		}
+
+		if valuer, ok := value.(driver.Valuer); ok {
+			if value, source.err = valuer.Value(); source.err != nil {
+				return false
+			}
+		}
+		source.values[idx] = value
+	}
+	return true
+}
+
+// Values implements BulkLoadSource interface
+func (source *bulkLoadSource) Values() ([]interface{}, error) {
+	return source.values, source.err
+}
+
+// Err implements BulkLoadSource interface
+func (source *bulkLoadSource) Err() error {
+	return source.err
+}
@@ -0,0 +1,104 @@
+package gorm_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/driver/sqlite"
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/internal/testdb"
+	"github.com/fangxing98/jx-gorm/gorm/logger"
+)
+
+type BulkLoadUser struct {
+	ID        uint
+	Name      string
+	Age       int               `gorm:"default:18"`
+	Tags      []string          `gorm:"serializer:json"`
+	Extra     map[string]string `gorm:"serializer:json"`
+	Score     *float64
+	CreatedAt time.Time
+}
+
+func TestBulkLoad(t *testing.T) {
+	db := testdb.Open(t, nil, &BulkLoadUser{})
+
+	score := 9.5
+	users := make([]BulkLoadUser, 0, 1234)
+	for i := 0; i < cap(users); i++ {
+		users = append(users, BulkLoadUser{Name: fmt.Sprintf("user-%d", i), Tags: []string{"a", "b"}})
+	}
+	users[0].Age, users[0].Score = 30, &score
+
+	tx := db.BulkLoad(context.Background(), &users, gorm.BulkLoadOptions{BatchSize: 100})
+	if tx.Error != nil {
+		t.Fatalf("failed to bulk load, got error %v", tx.Error)
+	}
+
+	if tx.RowsAffected != int64(len(users)) {
+		t.Errorf("rows affected should be %v, got %v", len(users), tx.RowsAffected)
+	}
+
+	var count int64
+	db.Model(&BulkLoadUser{}).Count(&count)
+	if count != int64(len(users)) {
+		t.Errorf("should load %v rows, got %v", len(users), count)
+	}
+
+	var first, last BulkLoadUser
+	db.First(&first, "name = ?", "user-0")
+	db.First(&last, "name = ?", "user-1233")
+
+	if first.Age != 30 || first.Score == nil || *first.Score != score || len(first.Tags) != 2 || first.CreatedAt.IsZero() {
+		t.Errorf("loaded values are not correct, got %+v", first)
+	}
+
+	if last.Age != 18 || last.Score != nil || len(last.Tags) != 2 || last.ID == 0 {
+		t.Errorf("default values should be applied, got %+v", last)
+	}
+
+	if err := db.BulkLoad(context.Background(), &BulkLoadUser{}, gorm.BulkLoadOptions{}).Error; err == nil {
+		t.Errorf("should return error when loading non-slice values")
+	}
+}
+
+// bulkCopyDialector bulk copy unavailable on the connection, e.g. postgres within transactions
+type bulkCopyDialector struct {
+	gorm.Dialector
+}
+
+func (bulkCopyDialector) BulkLoad(context.Context, *gorm.DB, string, []string, gorm.BulkLoadSource) (int64, error) {
+	return 0, gorm.ErrNotImplemented
+}
+
+type bulkLoadLogWriter struct {
+	logs []string
+}
+
+func (w *bulkLoadLogWriter) Printf(format string, args ...interface{}) {
+	w.logs = append(w.logs, fmt.Sprintf(format, args...))
+}
+
+func TestBulkLoadFallbackLogged(t *testing.T) {
+	writer := &bulkLoadLogWriter{}
+	db, err := gorm.Open(bulkCopyDialector{sqlite.Open(filepath.Join(t.TempDir(), "gorm.db"))}, gorm.DBTypeSqlite, &gorm.Config{
+		Logger: logger.New(writer, logger.Config{LogLevel: logger.Warn}),
+	})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+	db.AutoMigrate(&BulkLoadUser{})
+
+	users := []BulkLoadUser{{Name: "a"}, {Name: "b"}}
+	if tx := db.BulkLoad(context.Background(), &users, gorm.BulkLoadOptions{}); tx.Error != nil || tx.RowsAffected != 2 {
+		t.Fatalf("rows should be loaded with prepared inserts, got %v rows, error %v", tx.RowsAffected, tx.Error)
+	}
+
+	if len(writer.logs) != 1 || !strings.Contains(writer.logs[0], "loading bulk_load_users with prepared inserts") {
+		t.Errorf("falling back to prepared inserts should be logged, got %v", writer.logs)
+	}
+}
@@ -0,0 +1,230 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/callbacks"
+	"github.com/fangxing98/jx-gorm/gorm/clause"
+)
+
+const (
+	// PluginName the name registered into gorm.Config.Plugins
+	PluginName = "gorm:cache"
+
+	ttlSettingKey = "gorm:cache_ttl"
+	keyPrefix     = "gorm:cache:"
+)
+
+// Config cache plugin config
+type Config struct {
+	// Store the cache backend, defaults to a MemoryStore with DefaultMemoryStoreSize entries
+	Store Store
+	// TTL default ttl of the cached queries, when it is zero only queries with a cache.TTL clause are cached
+	TTL time.Duration
+}
+
+// Cache query result cache plugin
+//
+//	db.Use(cache.New(cache.Config{}))
+//	db.Clauses(cache.TTL(time.Minute)).Where("type = ?", "gender").Find(&dicts)
+type Cache struct {
+	Config
+	query func(*gorm.DB)
+	// generation increases after every invalidation, results queried before an invalidation are not stored
+	generation atomic.Uint64
+}
+
+// New returns a cache plugin with config
+func New(config Config) *Cache {
+	if config.Store == nil {
+		config.Store = NewMemoryStore(DefaultMemoryStoreSize)
+	}
+	return &Cache{Config: config}
+}
+
+// Name implements gorm.Plugin
+func (c *Cache) Name() string {
+	return PluginName
+}
+
+// Initialize implements gorm.Plugin
+func (c *Cache) Initialize(db *gorm.DB) error {
+	if c.Store == nil {
+		c.Store = NewMemoryStore(DefaultMemoryStoreSize)
+	}
+
+	if c.query = db.Callback().Query().Get("gorm:query"); c.query == nil {
+		c.query = callbacks.Query
+	}
+
+	// wrap the conn pool so invalidations within a transaction are applied on commit
+	if _, ok := db.ConnPool.(*connPool); !ok && db.ConnPool != nil {
+		db.ConnPool = &connPool{ConnPool: db.ConnPool, cache: c}
+		if db.Statement != nil {
+			db.Statement.ConnPool = db.ConnPool
+		}
+	}
+
+	if err := db.Callback().Query().Replace("gorm:query", c.queryWithCache); err != nil {
+		return err
+	}
+
+	if err := db.Callback().Create().After("gorm:after_create").Register("gorm:cache_invalidate", c.afterWrite); err != nil {
+		return err
+	}
+
+	if err := db.Callback().Update().After("gorm:after_update").Register("gorm:cache_invalidate", c.afterWrite); err != nil {
+		return err
+	}
+
+	return db.Callback().Delete().After("gorm:after_delete").Register("gorm:cache_invalidate", c.afterWrite)
+}
+
+// Invalidate removes cached queries of tables, used after changing data with raw sql
+func (c *Cache) Invalidate(ctx context.Context, tables ...string) error {
+	if len(tables) == 0 {
+		return nil
+	}
+	c.generation.Add(1)
+	return c.Store.Invalidate(ctx, tables...)
+}
+
+func (c *Cache) queryWithCache(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+
+	callbacks.BuildQuerySQL(db)
+
+	ttl, cacheable := c.ttlOf(db)
+	if !cacheable || db.DryRun || db.Error != nil || inTransaction(db.Statement.ConnPool) {
+		c.query(db)
+		return
+	}
+
+	key := c.keyOf(db)
+	if res, ok := c.load(db, key); ok {
+		res.scan(db)
+		return
+	}
+
+	generation := c.generation.Load()
+	res, err := fetch(db)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	// the result might be stale if tables were changed while querying
+	if c.generation.Load() == generation {
+		c.store(db, key, ttl, res, generation)
+	}
+	res.scan(db)
+}
+
+func (c *Cache) ttlOf(db *gorm.DB) (time.Duration, bool) {
+	if v, ok := db.Statement.Settings.Load(ttlSettingKey); ok {
+		ttl, _ := v.(time.Duration)
+		return ttl, ttl > 0
+	}
+	return c.TTL, c.TTL > 0
+}
+
+func (c *Cache) keyOf(db *gorm.DB) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%v\x00", reflect.TypeOf(db.Statement.Dest))
+	hash.Write([]byte(db.Statement.SQL.String()))
+	for _, v := range db.Statement.Vars {
+		fmt.Fprintf(hash, "\x00%T:%v", v, v)
+	}
+	return keyPrefix + hex.EncodeToString(hash.Sum(nil))
+}
+
+func (c *Cache) load(db *gorm.DB, key string) (*result, bool) {
+	value, ok, err := c.Store.Get(db.Statement.Context, key)
+	if err != nil || !ok {
+		return nil, false
+	}
+
+	res, err := decodeResult(value)
+	return res, err == nil
+}
+
+func (c *Cache) store(db *gorm.DB, key string, ttl time.Duration, res *result, generation uint64) {
+	value, err := res.encode()
+	if err != nil {
+		db.Logger.Warn(db.Statement.Context, "failed to encode query cache, got error %v", err)
+		return
+	}
+
+	tables := tablesOf(db)
+	if err := c.Store.Set(db.Statement.Context, key, value, ttl, tables...); err != nil {
+		db.Logger.Warn(db.Statement.Context, "failed to store query cache, got error %v", err)
+		return
+	}
+
+	// an invalidation between the generation check and tagging the entry misses it, remove the stale entry again
+	if c.generation.Load() != generation {
+		if err := c.Store.Invalidate(db.Statement.Context, tables...); err != nil {
+			db.Logger.Warn(db.Statement.Context, "failed to invalidate query cache, got error %v", err)
+		}
+	}
+}
+
+func (c *Cache) afterWrite(db *gorm.DB) {
+	if db.Error != nil || db.DryRun || db.Statement.Table == "" {
+		return
+	}
+
+	if tx := pendingTx(db.Statement.ConnPool); tx != nil {
+		tx.addTables(db.Statement.Table)
+		return
+	}
+
+	if err := c.Invalidate(db.Statement.Context, db.Statement.Table); err != nil {
+		db.Logger.Warn(db.Statement.Context, "failed to invalidate query cache, got error %v", err)
+	}
+}
+
+// tablesOf returns the tables a query reads, including joined tables
+func tablesOf(db *gorm.DB) []string {
+	tables := make([]string, 0, 1)
+	if db.Statement.Table != "" {
+		tables = append(tables, db.Statement.Table)
+	}
+
+	if from, ok := db.Statement.Clauses["FROM"].Expression.(clause.From); ok {
+		for _, table := range from.Tables {
+			tables = append(tables, table.Name)
+		}
+		for _, join := range from.Joins {
+			if join.Table.Name != "" {
+				tables = append(tables, join.Table.Name)
+			}
+		}
+	}
+	return tables
+}
+
+type ttlClause time.Duration
+
+// TTL caches the query result for ttl
+//
+//	db.Clauses(cache.TTL(time.Minute)).Find(&configs)
+func TTL(ttl time.Duration) clause.Expression {
+	return ttlClause(ttl)
+}
+
+func (ttlClause) Build(clause.Builder) {
+}
+
+func (t ttlClause) ModifyStatement(stmt *gorm.Statement) {
+	stmt.Settings.Store(ttlSettingKey, time.Duration(t))
+}
@@ -0,0 +1,279 @@
+package cache_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/cache"
+	"github.com/fangxing98/jx-gorm/gorm/internal/testdb"
+)
+
+type Dict struct {
+	ID     uint
+	Type   string
+	Value  string
+	Hidden string `json:"-"`
+}
+
+func openDB(t *testing.T) (*gorm.DB, *cache.Cache) {
+	db := testdb.Open(t, nil)
+
+	plugin := cache.New(cache.Config{})
+	if err := db.Use(plugin); err != nil {
+		t.Fatalf("failed to use cache plugin, got error %v", err)
+	}
+
+	if err := db.AutoMigrate(&Dict{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+	return db, plugin
+}
+
+func TestQueryCache(t *testing.T) {
+	db, _ := openDB(t)
+	db.Create(&Dict{Type: "gender", Value: "male"})
+
+	var dicts []Dict
+	if err := db.Clauses(cache.TTL(time.Minute)).Where("type = ?", "gender").Find(&dicts).Error; err != nil || len(dicts) != 1 {
+		t.Fatalf("failed to query dicts, got %v, error %v", dicts, err)
+	}
+
+	// raw sql doesn't invalidate the cache
+	db.Exec("UPDATE dicts SET value = ?", "female")
+
+	dicts = nil
+	if err := db.Clauses(cache.TTL(time.Minute)).Where("type = ?", "gender").Find(&dicts).Error; err != nil || len(dicts) != 1 || dicts[0].Value != "male" {
+		t.Fatalf("expects cached dicts, got %v, error %v", dicts, err)
+	}
+
+	// queries without ttl are not cached
+	dicts = nil
+	if err := db.Where("type = ?", "gender").Find(&dicts).Error; err != nil || len(dicts) != 1 || dicts[0].Value != "female" {
+		t.Fatalf("expects fresh dicts, got %v, error %v", dicts, err)
+	}
+
+	var dict Dict
+	if err := db.Clauses(cache.TTL(time.Minute)).First(&dict, "type = ?", "unknown").Error; err != gorm.ErrRecordNotFound {
+		t.Fatalf("expects record not found, got %v", err)
+	}
+
+	if err := db.Clauses(cache.TTL(time.Minute)).First(&dict, "type = ?", "unknown").Error; err != gorm.ErrRecordNotFound {
+		t.Fatalf("expects cached record not found, got %v", err)
+	}
+}
+
+func TestQueryCacheInvalidate(t *testing.T) {
+	db, _ := openDB(t)
+	db.Create(&Dict{Type: "gender", Value: "male"})
+
+	var count int64
+	if err := db.Clauses(cache.TTL(time.Minute)).Model(&Dict{}).Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("failed to count dicts, got %v, error %v", count, err)
+	}
+
+	db.Create(&Dict{Type: "gender", Value: "female"})
+
+	if err := db.Clauses(cache.TTL(time.Minute)).Model(&Dict{}).Count(&count).Error; err != nil || count != 2 {
+		t.Fatalf("expects cache invalidated after create, got %v, error %v", count, err)
+	}
+
+	db.Where("value = ?", "female").Delete(&Dict{})
+
+	if err := db.Clauses(cache.TTL(time.Minute)).Model(&Dict{}).Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("expects cache invalidated after delete, got %v, error %v", count, err)
+	}
+}
+
+func TestQueryCacheInvalidateOnCommit(t *testing.T) {
+	db, _ := openDB(t)
+	db.Create(&Dict{Type: "gender", Value: "male"})
+
+	query := func() (dict Dict) {
+		db.Clauses(cache.TTL(time.Minute)).First(&dict)
+		return
+	}
+
+	if dict := query(); dict.Value != "male" {
+		t.Fatalf("failed to query dict, got %v", dict)
+	}
+
+	tx := db.Begin()
+	if err := tx.Model(&Dict{}).Where("type = ?", "gender").Update("value", "female").Error; err != nil {
+		t.Fatalf("failed to update dict, got error %v", err)
+	}
+
+	if dict := query(); dict.Value != "male" {
+		t.Fatalf("expects cache kept before commit, got %v", dict)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		t.Fatalf("failed to commit, got error %v", err)
+	}
+
+	if dict := query(); dict.Value != "female" {
+		t.Fatalf("expects cache invalidated after commit, got %v", dict)
+	}
+
+	db.Transaction(func(tx *gorm.DB) error {
+		tx.Model(&Dict{}).Where("type = ?", "gender").Update("value", "unknown")
+		return gorm.ErrInvalidData
+	})
+
+	if dict := query(); dict.Value != "female" {
+		t.Fatalf("expects cache kept after rollback, got %v", dict)
+	}
+}
+
+func TestQueryCacheScanValues(t *testing.T) {
+	db, _ := openDB(t)
+	db.Create(&Dict{Type: "gender", Value: "male", Hidden: "pw"})
+
+	for i := 0; i < 2; i++ {
+		var dict Dict
+		if err := db.Clauses(cache.TTL(time.Minute)).First(&dict).Error; err != nil || dict.Hidden != "pw" || dict.Value != "male" {
+			t.Errorf("fields should be scanned from cached values, got %+v, error %v", dict, err)
+		}
+	}
+
+	var dicts []map[string]interface{}
+	if err := db.Clauses(cache.TTL(time.Minute)).Model(&Dict{}).Order("id").Limit(1).Find(&dicts).Error; err != nil || len(dicts) != 1 {
+		t.Fatalf("failed to query maps, got %v, error %v", dicts, err)
+	}
+	if dicts[0]["type"] != "gender" || dicts[0]["hidden"] != "pw" {
+		t.Errorf("map should be scanned with columns, got %v", dicts[0])
+	}
+}
+
+func TestQueryCacheWithPreparedStmt(t *testing.T) {
+	db, _ := openDB(t)
+
+	err := db.Session(&gorm.Session{PrepareStmt: true}).Transaction(func(tx *gorm.DB) error {
+		tx.Create(&Dict{Type: "gender", Value: "male"})
+		tx.WithSavePoint(func(tx *gorm.DB) error {
+			if depth := tx.SavePointDepth(); depth != 1 {
+				t.Errorf("savepoint should be tracked, got depth %v", depth)
+			}
+			tx.Create(&Dict{Type: "gender", Value: "female"})
+			return gorm.ErrInvalidData
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("transaction should succeed, got %v", err)
+	}
+
+	var values []string
+	db.Model(&Dict{}).Pluck("value", &values)
+	if len(values) != 1 || values[0] != "male" {
+		t.Errorf("changes in the savepoint should be rolled back, got %v", values)
+	}
+}
+
+func TestQueryCachePadsInListsOfPreparedStmt(t *testing.T) {
+	db := testdb.Open(t, &gorm.Config{PrepareStmt: true}, &Dict{})
+	if err := db.Use(cache.New(cache.Config{})); err != nil {
+		t.Fatalf("failed to use cache plugin, got error %v", err)
+	}
+
+	var dicts []Dict
+	sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("id IN ?", []uint{1, 2, 3}).Find(&dicts)
+	})
+	if sql != "SELECT * FROM `dicts` WHERE id IN (1,2,3,3)" {
+		t.Errorf("IN list should be padded with prepared statements wrapped by the cache, got %v", sql)
+	}
+}
+
+// invalidatingStore invalidates before storing, as if the invalidation landed between the generation check and Set
+type invalidatingStore struct {
+	cache.Store
+	invalidate func()
+}
+
+func (s *invalidatingStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	if s.invalidate != nil {
+		s.invalidate()
+		s.invalidate = nil
+	}
+	return s.Store.Set(ctx, key, value, ttl, tags...)
+}
+
+func TestQueryCacheInvalidateBeforeSet(t *testing.T) {
+	db := testdb.Open(t, nil, &Dict{})
+	store := &invalidatingStore{Store: cache.NewMemoryStore(0)}
+	plugin := cache.New(cache.Config{Store: store})
+	if err := db.Use(plugin); err != nil {
+		t.Fatalf("failed to use cache plugin, got error %v", err)
+	}
+	db.Create(&Dict{Type: "gender", Value: "male"})
+
+	store.invalidate = func() {
+		db.Exec("UPDATE dicts SET value = ?", "female")
+		plugin.Invalidate(context.Background(), "dicts")
+	}
+
+	var stale, dict Dict
+	db.Clauses(cache.TTL(time.Minute)).First(&stale)
+	if err := db.Clauses(cache.TTL(time.Minute)).First(&dict).Error; err != nil || dict.Value != "female" {
+		t.Errorf("stale result should not be cached, got %+v, error %v", dict, err)
+	}
+}
+
+func TestQueryCacheConcurrentInvalidate(t *testing.T) {
+	db, plugin := openDB(t)
+	db.Create(&Dict{Type: "counter", Value: "0"})
+
+	for i := 1; i <= 50; i++ {
+		value := strconv.Itoa(i)
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			var dict Dict
+			db.Clauses(cache.TTL(time.Minute)).First(&dict, "type = ?", "counter")
+		}()
+		go func() {
+			defer wg.Done()
+			db.Exec("UPDATE dicts SET value = ? WHERE type = ?", value, "counter")
+			plugin.Invalidate(context.Background(), "dicts")
+		}()
+		wg.Wait()
+
+		var dict Dict
+		if err := db.Clauses(cache.TTL(time.Minute)).First(&dict, "type = ?", "counter").Error; err != nil || dict.Value != value {
+			t.Fatalf("cache should be invalidated, expects %v, got %+v, error %v", value, dict, err)
+		}
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := cache.NewMemoryStore(2)
+	ctx := context.Background()
+
+	store.Set(ctx, "a", []byte("a"), time.Minute, "users")
+	store.Set(ctx, "b", []byte("b"), time.Millisecond, "orders")
+	store.Set(ctx, "c", []byte("c"), 0, "users", "orders")
+
+	if _, ok, _ := store.Get(ctx, "a"); ok {
+		t.Errorf("expects a evicted")
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if _, ok, _ := store.Get(ctx, "b"); ok {
+		t.Errorf("expects b expired")
+	}
+
+	if v, ok, _ := store.Get(ctx, "c"); !ok || string(v) != "c" {
+		t.Errorf("expects c cached, got %s", v)
+	}
+
+	store.Invalidate(ctx, "orders")
+	if _, ok, _ := store.Get(ctx, "c"); ok {
+		t.Errorf("expects c invalidated")
+	}
+}
@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+// connPool wraps the db conn pool, transactions begun from it collect the tables changed
+// inside the transaction and invalidate them after commit
+type connPool struct {
+	gorm.ConnPool
+	cache *Cache
+}
+
+func (p *connPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	var tx gorm.Tx
+
+	switch beginner := p.ConnPool.(type) {
+	case gorm.TxBeginner:
+		sqlTx, err := beginner.BeginTx(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		tx = sqlTx
+	case gorm.ConnPoolBeginner:
+		conn, err := beginner.BeginTx(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		var ok bool
+		if tx, ok = conn.(gorm.Tx); !ok {
+			return nil, gorm.ErrInvalidTransaction
+		}
+	default:
+		return nil, gorm.ErrInvalidTransaction
+	}
+
+	return &txConnPool{Tx: tx, parent: p}, nil
+}
+
+// Unwrap implements gorm.ConnPoolUnwrapper
+func (p *connPool) Unwrap() gorm.ConnPool {
+	return p.ConnPool
+}
+
+func (p *connPool) GetDBConn() (*sql.DB, error) {
+	if sqldb, ok := p.ConnPool.(*sql.DB); ok {
+		return sqldb, nil
+	}
+
+	if dbConnector, ok := p.ConnPool.(gorm.GetDBConnector); ok && dbConnector != nil {
+		return dbConnector.GetDBConn()
+	}
+
+	return nil, gorm.ErrInvalidDB
+}
+
+func (p *connPool) Ping() error {
+	sqldb, err := p.GetDBConn()
+	if err != nil {
+		return err
+	}
+	return sqldb.Ping()
+}
+
+type txConnPool struct {
+	gorm.Tx
+	parent *connPool
+
+	mu     sync.Mutex
+	tables []string
+}
+
+// Unwrap implements gorm.ConnPoolUnwrapper
+func (tx *txConnPool) Unwrap() gorm.ConnPool {
+	return tx.Tx
+}
+
+func (tx *txConnPool) GetDBConn() (*sql.DB, error) {
+	return tx.parent.GetDBConn()
+}
+
+func (tx *txConnPool) Commit() error {
+	if err := tx.Tx.Commit(); err != nil {
+		return err
+	}
+
+	tx.mu.Lock()
+	tables := tx.tables
+	tx.tables = nil
+	tx.mu.Unlock()
+
+	return tx.parent.cache.Invalidate(context.Background(), tables...)
+}
+
+func (tx *txConnPool) Rollback() error {
+	tx.mu.Lock()
+	tx.tables = nil
+	tx.mu.Unlock()
+
+	return tx.Tx.Rollback()
+}
+
+func (tx *txConnPool) addTables(tables ...string) {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	for _, table := range tables {
+		exists := false
+		for _, t := range tx.tables {
+			if t == table {
+				exists = true
+				break
+			}
+		}
+
+		if !exists {
+			tx.tables = append(tx.tables, table)
+		}
+	}
+}
+
+// pendingTx returns the cache transaction of pool, if pool is not a transaction began by the cache conn pool, returns nil
+func pendingTx(pool gorm.ConnPool) *txConnPool {
+	switch p := pool.(type) {
+	case *txConnPool:
+		return p
+	case *gorm.PreparedStmtTX:
+		if tx, ok := p.Tx.(*txConnPool); ok {
+			return tx
+		}
+	}
+	return nil
+}
+
+func inTransaction(pool gorm.ConnPool) bool {
+	committer, ok := pool.(gorm.TxCommitter)
+	return ok && committer != nil
+}
@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/gob"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+func init() {
+	gob.Register(time.Time{})
+}
+
+// result the columns and values scanned from the database, values are driver values, e.g. int64, []byte or time.Time,
+// so the result is scanned into the destination by gorm.Scan like the rows of the database
+type result struct {
+	Columns   []string
+	TypeNames []string
+	Values    [][]interface{}
+}
+
+// fetch executes the query and reads the driver values of all rows
+func fetch(db *gorm.DB) (*result, error) {
+	rows, err := db.Statement.ConnPool.QueryContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := &result{}
+	if res.Columns, err = rows.Columns(); err != nil {
+		return nil, err
+	}
+	if columnTypes, err := rows.ColumnTypes(); err == nil {
+		for _, columnType := range columnTypes {
+			res.TypeNames = append(res.TypeNames, columnType.DatabaseTypeName())
+		}
+	}
+
+	for rows.Next() {
+		values := make([]interface{}, len(res.Columns))
+		dest := make([]interface{}, len(values))
+		for idx := range values {
+			dest[idx] = &values[idx]
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		res.Values = append(res.Values, values)
+	}
+	return res, rows.Err()
+}
+
+func (res *result) encode() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(res)
+	return buf.Bytes(), err
+}
+
+func decodeResult(value []byte) (*result, error) {
+	res := &result{}
+	return res, gob.NewDecoder(bytes.NewReader(value)).Decode(res)
+}
+
+var (
+	replayDB     *sql.DB
+	replayDBOnce sync.Once
+)
+
+// scan scans the result into the destination of db with gorm.Scan
+func (res *result) scan(db *gorm.DB) {
+	replayDBOnce.Do(func() {
+		replayDB = sql.OpenDB(replayConnector{})
+	})
+
+	rows, err := replayDB.QueryContext(context.Background(), "", res)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	defer func() {
+		db.AddError(rows.Close())
+	}()
+	gorm.Scan(rows, db, 0)
+}
+
+// replayConnector connector of the driver replaying results as rows
+type replayConnector struct{}
+
+func (replayConnector) Connect(context.Context) (driver.Conn, error) {
+	return replayConn{}, nil
+}
+
+func (replayConnector) Driver() driver.Driver {
+	return replayDriver{}
+}
+
+type replayDriver struct{}
+
+func (replayDriver) Open(string) (driver.Conn, error) {
+	return replayConn{}, nil
+}
+
+var errReplayOnly = errors.New("cache: replay connections only query results")
+
+type replayConn struct{}
+
+func (replayConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errReplayOnly
+}
+
+func (replayConn) Close() error {
+	return nil
+}
+
+func (replayConn) Begin() (driver.Tx, error) {
+	return nil, errReplayOnly
+}
+
+// CheckNamedValue implements driver.NamedValueChecker, the result is passed as the argument
+func (replayConn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (replayConn) QueryContext(_ context.Context, _ string, args []driver.NamedValue) (driver.Rows, error) {
+	if len(args) == 1 {
+		if res, ok := args[0].Value.(*result); ok {
+			return &replayRows{result: res}, nil
+		}
+	}
+	return nil, errReplayOnly
+}
+
+type replayRows struct {
+	*result
+	idx int
+}
+
+func (rows *replayRows) Columns() []string {
+	return rows.result.Columns
+}
+
+func (rows *replayRows) ColumnTypeDatabaseTypeName(idx int) string {
+	if idx < len(rows.TypeNames) {
+		return rows.TypeNames[idx]
+	}
+	return ""
+}
+
+func (rows *replayRows) Close() error {
+	return nil
+}
+
+func (rows *replayRows) Next(dest []driver.Value) error {
+	if rows.idx >= len(rows.Values) {
+		return io.EOF
+	}
+
+	for idx, value := range rows.Values[rows.idx] {
+		dest[idx] = value
+	}
+	rows.idx++
+	return nil
+}
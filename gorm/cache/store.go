@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/gorm/internal/lru"
+)
+
+// Store is the backend used to keep cached query results, values are the encoded results of a query
+type Store interface {
+	// Get returns the value stored with key, ok is false if the key doesn't exist or has expired
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	// Set stores value with key for ttl, tags are used to invalidate the entry later
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error
+	// Invalidate removes all entries tagged with any of tags
+	Invalidate(ctx context.Context, tags ...string) error
+}
+
+// DefaultMemoryStoreSize default max entries of the memory store
+const DefaultMemoryStoreSize = 1024
+
+type memoryEntry struct {
+	value     []byte
+	tags      []string
+	expiresAt time.Time
+}
+
+// MemoryStore in-memory LRU store, it is the default Store of the cache plugin
+type MemoryStore struct {
+	lru  *lru.LRU[string, *memoryEntry]
+	mu   sync.Mutex
+	tags map[string]map[string]struct{}
+}
+
+// NewMemoryStore returns a memory store holding at most size entries, size <= 0 means DefaultMemoryStoreSize
+func NewMemoryStore(size int) *MemoryStore {
+	if size <= 0 {
+		size = DefaultMemoryStoreSize
+	}
+
+	store := &MemoryStore{tags: map[string]map[string]struct{}{}}
+	store.lru = lru.NewLRU[string, *memoryEntry](size, store.onEvict, 0)
+	return store
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	entry, ok := s.lru.Get(key)
+	if !ok || entry == nil {
+		return nil, false, nil
+	}
+
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		s.lru.Remove(key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	entry := &memoryEntry{value: value, tags: tags}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	// the LRU calls onEvict with its own lock held, so don't hold s.mu when adding
+	s.lru.Add(key, entry)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tag := range tags {
+		keys, ok := s.tags[tag]
+		if !ok {
+			keys = map[string]struct{}{}
+			s.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Invalidate(_ context.Context, tags ...string) error {
+	var keys []string
+
+	s.mu.Lock()
+	for _, tag := range tags {
+		for key := range s.tags[tag] {
+			keys = append(keys, key)
+		}
+		delete(s.tags, tag)
+	}
+	s.mu.Unlock()
+
+	for _, key := range keys {
+		s.lru.Remove(key)
+	}
+	return nil
+}
+
+// Len returns the number of cached entries
+func (s *MemoryStore) Len() int {
+	return s.lru.Len()
+}
+
+func (s *MemoryStore) onEvict(key string, entry *memoryEntry) {
+	if entry == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tag := range entry.tags {
+		if keys, ok := s.tags[tag]; ok {
+			delete(keys, key)
+			if len(keys) == 0 {
+				delete(s.tags, tag)
+			}
+		}
+	}
+}
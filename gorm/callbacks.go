@@ -93,6 +93,12 @@ func (p *processor) Execute(db *DB) *DB {
 		optimizer.ModifyStatement(stmt)
 	}
 
+	if _, ok := stmt.Settings.Load(noPrepareSettingKey); ok {
+		if _, skipped := noPrepareFrom(stmt.Context); !skipped {
+			stmt.Context = withoutPrepare(stmt.Context, noPrepareOption{})
+		}
+	}
+
 	// assign model values
 	if stmt.Model == nil {
 		stmt.Model = stmt.Dest
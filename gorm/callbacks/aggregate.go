@@ -0,0 +1,324 @@
+package callbacks
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/clause"
+	"github.com/fangxing98/jx-gorm/gorm/schema"
+	"github.com/fangxing98/jx-gorm/gorm/utils"
+)
+
+// Aggregate loads the aggregates of relation records of WithCount and WithAggregate with grouped queries
+func Aggregate(db *gorm.DB) {
+	if db.Error == nil && len(db.Statement.Aggregates) > 0 && !db.DryRun {
+		if db.Statement.Schema == nil {
+			db.AddError(fmt.Errorf("%w when using aggregate", gorm.ErrModelValueRequired))
+			return
+		}
+
+		for _, aggregate := range db.Statement.Aggregates {
+			if err := loadAggregate(db, aggregate); err != nil {
+				db.AddError(err)
+				return
+			}
+		}
+	}
+}
+
+func loadAggregate(db *gorm.DB, aggregate gorm.Aggregate) error {
+	var (
+		s            = db.Statement.Schema
+		rel          = s.Relationships.Relations[aggregate.Relation]
+		fn           = strings.ToUpper(aggregate.Func)
+		column       = aggregate.Column
+		reflectValue = db.Statement.ReflectValue
+	)
+
+	if rel == nil {
+		return fmt.Errorf("%s: %w for schema %s", aggregate.Relation, gorm.ErrUnsupportedRelation, s.Name)
+	}
+
+	if fn == "" || strings.IndexFunc(fn, func(r rune) bool { return (r < 'A' || r > 'Z') && r != '_' }) >= 0 {
+		return fmt.Errorf("%w: aggregate function %q", gorm.ErrInvalidField, aggregate.Func)
+	}
+
+	if column == "" {
+		column = "*"
+	} else if field := rel.FieldSchema.LookUpField(column); field != nil {
+		column = field.DBName
+	}
+
+	dest, err := newAggregateDest(db, aggregate.Dest)
+	if err != nil {
+		return err
+	}
+
+	fields := aggregateFields(s, rel, fn, column)
+	if len(fields) == 0 && dest == nil {
+		return fmt.Errorf("%w: no field tagged with aggregate:%s.%s.%s for schema %s", gorm.ErrInvalidField, rel.Name, strings.ToLower(fn), column, s.Name)
+	}
+
+	// clean up old values before aggregating
+	switch reflectValue.Kind() {
+	case reflect.Struct:
+		for _, field := range fields {
+			db.AddError(field.Set(db.Statement.Context, reflectValue, reflect.Zero(field.FieldType).Interface()))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < reflectValue.Len(); i++ {
+			for _, field := range fields {
+				db.AddError(field.Set(db.Statement.Context, reflectValue.Index(i), reflect.Zero(field.FieldType).Interface()))
+			}
+		}
+	}
+	if dest != nil {
+		if err := dest.reset(db.Statement.Context, reflectValue); err != nil {
+			return err
+		}
+	}
+
+	var (
+		tx = db.Session(&gorm.Session{NewDB: true, Context: db.Statement.Context, SkipHooks: db.Statement.SkipHooks}).
+			Model(reflect.New(rel.FieldSchema.ModelType).Interface())
+		groupTable    = clause.CurrentTable
+		groupKeys     []string
+		groupFields   []*schema.Field
+		foreignFields []*schema.Field
+	)
+	tx.Statement.Unscoped = db.Statement.Unscoped
+
+	if rel.JoinTable != nil {
+		groupTable = rel.JoinTable.Table
+		joinConds := make([]clause.Expression, 0, len(rel.References))
+		for _, ref := range rel.References {
+			if ref.OwnPrimaryKey {
+				groupKeys = append(groupKeys, ref.ForeignKey.DBName)
+				groupFields = append(groupFields, ref.ForeignKey)
+				foreignFields = append(foreignFields, ref.PrimaryKey)
+			} else if ref.PrimaryValue != "" {
+				joinConds = append(joinConds, clause.Eq{Column: clause.Column{Table: groupTable, Name: ref.ForeignKey.DBName}, Value: ref.PrimaryValue})
+			} else {
+				joinConds = append(joinConds, clause.Eq{
+					Column: clause.Column{Table: groupTable, Name: ref.ForeignKey.DBName},
+					Value:  clause.Column{Table: clause.CurrentTable, Name: ref.PrimaryKey.DBName},
+				})
+			}
+		}
+		tx = tx.Joins("INNER JOIN ? ON ?", clause.Table{Name: groupTable}, clause.And(joinConds...))
+	} else {
+		for _, ref := range rel.References {
+			if ref.OwnPrimaryKey {
+				groupKeys = append(groupKeys, ref.ForeignKey.DBName)
+				groupFields = append(groupFields, ref.ForeignKey)
+				foreignFields = append(foreignFields, ref.PrimaryKey)
+			} else if ref.PrimaryValue != "" {
+				tx = tx.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: ref.ForeignKey.DBName}, Value: ref.PrimaryValue})
+			} else {
+				groupKeys = append(groupKeys, ref.PrimaryKey.DBName)
+				groupFields = append(groupFields, ref.PrimaryKey)
+				foreignFields = append(foreignFields, ref.ForeignKey)
+			}
+		}
+	}
+
+	identityMap, foreignValues := schema.GetIdentityFieldValuesMap(db.Statement.Context, reflectValue, foreignFields)
+	if len(foreignValues) == 0 {
+		return nil
+	}
+
+	var inlineConds []interface{}
+	for _, cond := range aggregate.Conds {
+		if fc, ok := cond.(func(*gorm.DB) *gorm.DB); ok {
+			tx = fc(tx)
+		} else {
+			inlineConds = append(inlineConds, cond)
+		}
+	}
+
+	if len(inlineConds) > 0 {
+		tx = tx.Where(inlineConds[0], inlineConds[1:]...)
+	}
+
+	var (
+		selectSQL    strings.Builder
+		groupColumns = make([]clause.Column, 0, len(groupKeys))
+		vars         = make([]interface{}, 0, len(groupKeys)+2)
+	)
+
+	for _, key := range groupKeys {
+		groupColumn := clause.Column{Table: groupTable, Name: key}
+		groupColumns = append(groupColumns, groupColumn)
+		vars = append(vars, groupColumn)
+		selectSQL.WriteString("?,")
+	}
+
+	if column == "*" {
+		selectSQL.WriteString(fn + "(*) AS ?")
+	} else {
+		selectSQL.WriteString(fn + "(?) AS ?")
+		vars = append(vars, clause.Column{Table: clause.CurrentTable, Name: column})
+	}
+	vars = append(vars, clause.Column{Name: "gorm_aggregate"})
+
+	queryColumn, queryValues := schema.ToQueryValues(groupTable, groupKeys, foreignValues)
+	rows, err := tx.Select(selectSQL.String(), vars...).Where(clause.IN{Column: queryColumn, Values: queryValues}).
+		Clauses(clause.GroupBy{Columns: groupColumns}).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var (
+		groupValues = make([]interface{}, len(groupFields))
+		dests       = make([]interface{}, len(groupFields)+1)
+		value       interface{}
+	)
+
+	for rows.Next() {
+		for idx, field := range groupFields {
+			dests[idx] = reflect.New(field.IndirectFieldType).Interface()
+		}
+		dests[len(groupFields)] = &value
+		if dest != nil {
+			// scan into the type of the destination, e.g. *float64 for SUM of integers
+			dests[len(groupFields)] = reflect.New(reflect.PointerTo(dest.valueType())).Interface()
+		}
+
+		if err := rows.Scan(dests...); err != nil {
+			return err
+		}
+
+		if dest != nil {
+			if v := reflect.ValueOf(dests[len(groupFields)]).Elem(); v.IsNil() {
+				value = nil
+			} else {
+				value = v.Elem().Interface()
+			}
+		}
+
+		for idx := range groupFields {
+			groupValues[idx] = reflect.ValueOf(dests[idx]).Elem().Interface()
+		}
+
+		if value == nil {
+			continue
+		}
+
+		for _, data := range identityMap[utils.ToStringKey(groupValues...)] {
+			for _, field := range fields {
+				db.AddError(field.Set(db.Statement.Context, data, value))
+			}
+
+			if dest != nil {
+				if err := dest.set(db.Statement.Context, data, reflect.ValueOf(value)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return rows.Err()
+}
+
+// aggregateDest destination of WithAggregate, a value of a single record or a map keyed by primary keys of records
+type aggregateDest struct {
+	value        reflect.Value
+	primaryField *schema.Field
+}
+
+func newAggregateDest(db *gorm.DB, dest interface{}) (*aggregateDest, error) {
+	if dest == nil {
+		return nil, nil
+	}
+
+	d := &aggregateDest{value: reflect.ValueOf(dest).Elem()}
+	if d.value.Kind() == reflect.Map {
+		if d.primaryField = db.Statement.Schema.PrioritizedPrimaryField; d.primaryField == nil {
+			return nil, fmt.Errorf("%w: aggregate destination %T is keyed by primary keys of schema %s", gorm.ErrPrimaryKeyRequired, dest, db.Statement.Schema.Name)
+		}
+	} else if db.Statement.ReflectValue.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: aggregate destination %T of multiple records should be a map", gorm.ErrInvalidData, dest)
+	}
+	return d, nil
+}
+
+func (d *aggregateDest) valueType() reflect.Type {
+	if d.primaryField != nil {
+		return d.value.Type().Elem()
+	}
+	return d.value.Type()
+}
+
+// reset cleans up old values, the aggregates of records without relation records are zero
+func (d *aggregateDest) reset(ctx context.Context, reflectValue reflect.Value) error {
+	zero := reflect.Zero(d.valueType())
+	if d.primaryField == nil {
+		d.value.Set(zero)
+		return nil
+	}
+
+	if d.value.IsNil() {
+		d.value.Set(reflect.MakeMap(d.value.Type()))
+	} else {
+		d.value.Clear()
+	}
+
+	switch reflectValue.Kind() {
+	case reflect.Struct:
+		return d.set(ctx, reflectValue, zero)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < reflectValue.Len(); i++ {
+			if err := d.set(ctx, reflect.Indirect(reflectValue.Index(i)), zero); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *aggregateDest) set(ctx context.Context, data reflect.Value, value reflect.Value) error {
+	if d.primaryField == nil {
+		d.value.Set(value)
+		return nil
+	}
+
+	pk, _ := d.primaryField.ValueOf(ctx, data)
+	key := reflect.ValueOf(pk)
+	if !key.Type().ConvertibleTo(d.value.Type().Key()) {
+		return fmt.Errorf("%w: primary key %v can't be the key of aggregate destination %s", gorm.ErrInvalidData, pk, d.value.Type())
+	}
+	d.value.SetMapIndex(key.Convert(d.value.Type().Key()), value)
+	return nil
+}
+
+// aggregateFields returns the fields tagged with the aggregate, e.g. `aggregate:Orders.count`, `aggregate:Orders.sum.amount`
+func aggregateFields(s *schema.Schema, rel *schema.Relationship, fn, column string) (fields []*schema.Field) {
+	for _, field := range s.Fields {
+		if field.Aggregate == "" {
+			continue
+		}
+
+		settings := strings.SplitN(field.Aggregate, ".", 3)
+		if len(settings) < 2 || settings[0] != rel.Name || !strings.EqualFold(settings[1], fn) {
+			continue
+		}
+
+		aggregateColumn := "*"
+		if len(settings) == 3 {
+			if aggregateColumn = settings[2]; aggregateColumn != "*" {
+				if f := rel.FieldSchema.LookUpField(aggregateColumn); f != nil {
+					aggregateColumn = f.DBName
+				}
+			}
+		}
+
+		if aggregateColumn == column {
+			fields = append(fields, field)
+		}
+	}
+	return
+}
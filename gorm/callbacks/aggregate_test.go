@@ -0,0 +1,116 @@
+package callbacks_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/internal/testdb"
+)
+
+type AggregateOrder struct {
+	ID              uint
+	AggregateUserID uint
+	Amount          float64
+	State           string
+	DeletedAt       gorm.DeletedAt
+}
+
+type AggregateTag struct {
+	ID   uint
+	Name string
+}
+
+type AggregateComment struct {
+	ID        uint
+	OwnerID   uint
+	OwnerType string
+}
+
+type AggregateUser struct {
+	ID            uint
+	Name          string
+	Orders        []AggregateOrder
+	Tags          []AggregateTag     `gorm:"many2many:aggregate_user_tags"`
+	Comments      []AggregateComment `gorm:"polymorphic:Owner"`
+	OrdersCount   int                `gorm:"->;aggregate:Orders.count"`
+	OrdersTotal   float64            `gorm:"->;aggregate:Orders.sum.amount"`
+	TagsCount     int64              `gorm:"->;aggregate:Tags.count"`
+	CommentsCount uint               `gorm:"->;aggregate:Comments.count"`
+}
+
+func TestAggregate(t *testing.T) {
+	db := testdb.Open(t, nil, &AggregateUser{}, &AggregateOrder{}, &AggregateTag{}, &AggregateComment{})
+
+	if db.Migrator().HasColumn(&AggregateUser{}, "orders_count") {
+		t.Errorf("aggregate fields should not be migrated")
+	}
+
+	tags := []AggregateTag{{Name: "a"}, {Name: "b"}}
+	users := []AggregateUser{
+		{
+			Name:     "a",
+			Orders:   []AggregateOrder{{Amount: 10, State: "paid"}, {Amount: 20, State: "paid"}, {Amount: 5, State: "cancelled"}},
+			Tags:     tags,
+			Comments: []AggregateComment{{}, {}},
+		},
+		{Name: "b", Orders: []AggregateOrder{{Amount: 1, State: "paid"}}, Tags: tags[:1]},
+		{Name: "c"},
+	}
+	if err := db.Create(&users).Error; err != nil {
+		t.Fatalf("failed to create users, got error %v", err)
+	}
+	db.Delete(&users[1].Orders[0])
+	db.Create(&AggregateComment{OwnerID: users[1].ID, OwnerType: "others"})
+
+	var result []AggregateUser
+	if err := db.WithCount("Orders").WithAggregate("Orders", "sum", "Amount").WithCount("Tags").WithCount("Comments").
+		Order("id").Find(&result).Error; err != nil {
+		t.Fatalf("failed to find with aggregates, got error %v", err)
+	}
+
+	expects := []AggregateUser{
+		{OrdersCount: 3, OrdersTotal: 35, TagsCount: 2, CommentsCount: 2},
+		{OrdersCount: 0, OrdersTotal: 0, TagsCount: 1, CommentsCount: 0},
+		{},
+	}
+	for idx, expect := range expects {
+		if r := result[idx]; r.OrdersCount != expect.OrdersCount || r.OrdersTotal != expect.OrdersTotal || r.TagsCount != expect.TagsCount || r.CommentsCount != expect.CommentsCount || len(r.Orders) != 0 {
+			t.Errorf("aggregates of user %v should be %+v, got %+v", idx, expect, r)
+		}
+	}
+
+	var user AggregateUser
+	if err := db.WithCount("Orders", "state = ?", "paid").WithAggregate("Orders", "SUM", "amount", func(db *gorm.DB) *gorm.DB {
+		return db.Where("state = ?", "paid")
+	}).First(&user, users[0].ID).Error; err != nil {
+		t.Fatalf("failed to find with aggregates, got error %v", err)
+	}
+
+	if user.OrdersCount != 2 || user.OrdersTotal != 30 {
+		t.Errorf("aggregates should be filtered by conditions, got %+v", user)
+	}
+
+	if err := db.WithAggregate("Orders", "MAX", "amount").Find(&result).Error; !errors.Is(err, gorm.ErrInvalidField) {
+		t.Errorf("aggregate without tagged field should be invalid, got %v", err)
+	}
+
+	var maxAmount float64
+	if err := db.WithAggregate("Orders", "MAX", "amount", &maxAmount, "state = ?", "paid").First(&user, users[0].ID).Error; err != nil || maxAmount != 20 {
+		t.Errorf("aggregate should be loaded into the destination, got %v, error %v", maxAmount, err)
+	}
+
+	totals := map[uint]float64{0: 1}
+	if err := db.WithAggregate("Orders", "SUM", "amount", &totals).Order("id").Find(&result).Error; err != nil {
+		t.Fatalf("failed to find with aggregate destination, got error %v", err)
+	}
+
+	if expects := map[uint]float64{users[0].ID: 35, users[1].ID: 0, users[2].ID: 0}; !reflect.DeepEqual(totals, expects) {
+		t.Errorf("aggregates should be keyed by primary keys, expects %v, got %v", expects, totals)
+	}
+
+	if err := db.WithAggregate("Orders", "SUM", "amount", &maxAmount).Find(&result).Error; !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("aggregate destination of multiple records should be a map, got %v", err)
+	}
+}
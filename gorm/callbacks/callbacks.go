@@ -44,12 +44,17 @@ func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
 	createCallback.Register("gorm:create", Create(config))
 	createCallback.Register("gorm:save_after_associations", SaveAfterAssociations(true))
 	createCallback.Register("gorm:after_create", AfterCreate)
+	createCallback.Register("gorm:transaction_hooks", TransactionHooks)
 	createCallback.Match(enableTransaction).Register("gorm:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 	createCallback.Clauses = config.CreateClauses
 
 	queryCallback := db.Callback().Query()
+	queryCallback.Register("gorm:before_query", BeforeQuery)
+	queryCallback.Register("gorm:set_lock_timeout", SetLockTimeout)
 	queryCallback.Register("gorm:query", Query)
+	queryCallback.Register("gorm:reset_lock_timeout", ResetLockTimeout)
 	queryCallback.Register("gorm:preload", Preload)
+	queryCallback.Register("gorm:aggregate", Aggregate)
 	queryCallback.Register("gorm:after_query", AfterQuery)
 	queryCallback.Clauses = config.QueryClauses
 
@@ -59,6 +64,7 @@ func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
 	deleteCallback.Register("gorm:delete_before_associations", DeleteBeforeAssociations)
 	deleteCallback.Register("gorm:delete", Delete(config))
 	deleteCallback.Register("gorm:after_delete", AfterDelete)
+	deleteCallback.Register("gorm:transaction_hooks", TransactionHooks)
 	deleteCallback.Match(enableTransaction).Register("gorm:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 	deleteCallback.Clauses = config.DeleteClauses
 
@@ -70,6 +76,7 @@ func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
 	updateCallback.Register("gorm:update", Update(config))
 	updateCallback.Register("gorm:save_after_associations", SaveAfterAssociations(false))
 	updateCallback.Register("gorm:after_update", AfterUpdate)
+	updateCallback.Register("gorm:transaction_hooks", TransactionHooks)
 	updateCallback.Match(enableTransaction).Register("gorm:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 	updateCallback.Clauses = config.UpdateClauses
 
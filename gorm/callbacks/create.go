@@ -13,19 +13,42 @@ import (
 
 // BeforeCreate before create hooks
 func BeforeCreate(db *gorm.DB) {
-	if db.Error == nil && db.Statement.Schema != nil && !db.Statement.SkipHooks && (db.Statement.Schema.BeforeSave || db.Statement.Schema.BeforeCreate) {
+	if db.Error == nil && db.Statement.Schema != nil && !db.Statement.SkipHooks && (db.Statement.Schema.BeforeSave || db.Statement.Schema.BeforeCreate || db.Statement.Schema.BeforeUpsert) {
+		upsert := db.Statement.Schema.BeforeUpsert && isUpsert(db.Statement)
 		callMethod(db, func(value interface{}, tx *gorm.DB) (called bool) {
 			if db.Statement.Schema.BeforeSave {
 				if i, ok := value.(BeforeSaveInterface); ok {
 					called = true
-					db.AddError(i.BeforeSave(tx))
+					db.AddError(gorm.NewHookError("BeforeSave", i.BeforeSave(tx)))
+				}
+
+				if i, ok := value.(BeforeSaveContextInterface); ok {
+					called = true
+					db.AddError(gorm.NewHookError("BeforeSaveContext", i.BeforeSaveContext(tx.Statement.Context, tx)))
+				}
+			}
+
+			if upsert {
+				if i, ok := value.(BeforeUpsertInterface); ok {
+					called = true
+					db.AddError(gorm.NewHookError("BeforeUpsert", i.BeforeUpsert(tx)))
+				}
+
+				if i, ok := value.(BeforeUpsertContextInterface); ok {
+					called = true
+					db.AddError(gorm.NewHookError("BeforeUpsertContext", i.BeforeUpsertContext(tx.Statement.Context, tx)))
 				}
 			}
 
 			if db.Statement.Schema.BeforeCreate {
 				if i, ok := value.(BeforeCreateInterface); ok {
 					called = true
-					db.AddError(i.BeforeCreate(tx))
+					db.AddError(gorm.NewHookError("BeforeCreate", i.BeforeCreate(tx)))
+				}
+
+				if i, ok := value.(BeforeCreateContextInterface); ok {
+					called = true
+					db.AddError(gorm.NewHookError("BeforeCreateContext", i.BeforeCreateContext(tx.Statement.Context, tx)))
 				}
 			}
 			return called
@@ -33,6 +56,16 @@ func BeforeCreate(db *gorm.DB) {
 	}
 }
 
+// isUpsert reports whether the conflicting rows are updated when creating, e.g. by Upsert or an OnConflict clause
+func isUpsert(stmt *gorm.Statement) bool {
+	if c, ok := stmt.Clauses["ON CONFLICT"]; ok {
+		if onConflict, ok := c.Expression.(clause.OnConflict); ok {
+			return !onConflict.DoNothing
+		}
+	}
+	return false
+}
+
 // Create create hook
 func Create(config *Config) func(db *gorm.DB) {
 	supportReturning := utils.Contains(config.CreateClauses, "RETURNING")
@@ -207,14 +240,24 @@ func AfterCreate(db *gorm.DB) {
 			if db.Statement.Schema.AfterCreate {
 				if i, ok := value.(AfterCreateInterface); ok {
 					called = true
-					db.AddError(i.AfterCreate(tx))
+					db.AddError(gorm.NewHookError("AfterCreate", i.AfterCreate(tx)))
+				}
+
+				if i, ok := value.(AfterCreateContextInterface); ok {
+					called = true
+					db.AddError(gorm.NewHookError("AfterCreateContext", i.AfterCreateContext(tx.Statement.Context, tx)))
 				}
 			}
 
 			if db.Statement.Schema.AfterSave {
 				if i, ok := value.(AfterSaveInterface); ok {
 					called = true
-					db.AddError(i.AfterSave(tx))
+					db.AddError(gorm.NewHookError("AfterSave", i.AfterSave(tx)))
+				}
+
+				if i, ok := value.(AfterSaveContextInterface); ok {
+					called = true
+					db.AddError(gorm.NewHookError("AfterSaveContext", i.AfterSaveContext(tx.Statement.Context, tx)))
 				}
 			}
 			return called
@@ -12,13 +12,17 @@ import (
 
 func BeforeDelete(db *gorm.DB) {
 	if db.Error == nil && db.Statement.Schema != nil && !db.Statement.SkipHooks && db.Statement.Schema.BeforeDelete {
-		callMethod(db, func(value interface{}, tx *gorm.DB) bool {
+		callMethod(db, func(value interface{}, tx *gorm.DB) (called bool) {
 			if i, ok := value.(BeforeDeleteInterface); ok {
-				db.AddError(i.BeforeDelete(tx))
-				return true
+				called = true
+				db.AddError(gorm.NewHookError("BeforeDelete", i.BeforeDelete(tx)))
 			}
 
-			return false
+			if i, ok := value.(BeforeDeleteContextInterface); ok {
+				called = true
+				db.AddError(gorm.NewHookError("BeforeDeleteContext", i.BeforeDeleteContext(tx.Statement.Context, tx)))
+			}
+			return called
 		})
 	}
 }
@@ -174,12 +178,17 @@ func Delete(config *Config) func(db *gorm.DB) {
 
 func AfterDelete(db *gorm.DB) {
 	if db.Error == nil && db.Statement.Schema != nil && !db.Statement.SkipHooks && db.Statement.Schema.AfterDelete {
-		callMethod(db, func(value interface{}, tx *gorm.DB) bool {
+		callMethod(db, func(value interface{}, tx *gorm.DB) (called bool) {
 			if i, ok := value.(AfterDeleteInterface); ok {
-				db.AddError(i.AfterDelete(tx))
-				return true
+				called = true
+				db.AddError(gorm.NewHookError("AfterDelete", i.AfterDelete(tx)))
+			}
+
+			if i, ok := value.(AfterDeleteContextInterface); ok {
+				called = true
+				db.AddError(gorm.NewHookError("AfterDeleteContext", i.AfterDeleteContext(tx.Statement.Context, tx)))
 			}
-			return false
+			return called
 		})
 	}
 }
@@ -0,0 +1,186 @@
+package callbacks_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/internal/testdb"
+)
+
+type hookContextKey struct{}
+
+type HookPet struct {
+	ID         uint
+	HookUserID uint
+	Name       string
+}
+
+type HookUser struct {
+	ID       uint
+	Name     string
+	Scanned  bool `gorm:"-"`
+	Pets     []HookPet
+	Creator  string
+	Appended []string `gorm:"-"`
+	Deleted  []string `gorm:"-"`
+}
+
+func (u *HookUser) BeforeCreateContext(ctx context.Context, tx *gorm.DB) error {
+	if creator, ok := ctx.Value(hookContextKey{}).(string); ok {
+		u.Creator = creator
+	}
+	return nil
+}
+
+func (u *HookUser) BeforeFind(tx *gorm.DB) error {
+	tx.Statement.Where("name <> ?", "hidden")
+	if u.Name != "" {
+		tx.Statement.Where("name = ?", u.Name)
+	}
+	return nil
+}
+
+func (u *HookUser) AfterScanRow(tx *gorm.DB) error {
+	u.Scanned = true
+	return nil
+}
+
+func (u *HookUser) BeforeAssociationAppend(tx *gorm.DB, association string, values []interface{}) error {
+	if len(values) == 0 {
+		return errors.New("nothing to append")
+	}
+	return nil
+}
+
+func (u *HookUser) AfterAssociationAppend(tx *gorm.DB, association string, values []interface{}) error {
+	u.Appended = append(u.Appended, association)
+	return nil
+}
+
+func (u *HookUser) AfterAssociationDelete(tx *gorm.DB, association string, values []interface{}) error {
+	u.Deleted = append(u.Deleted, association)
+	return nil
+}
+
+func TestHooks(t *testing.T) {
+	db := testdb.Open(t, nil, &HookUser{}, &HookPet{})
+
+	ctx := context.WithValue(context.Background(), hookContextKey{}, "jinzhu")
+	users := []HookUser{{Name: "visible"}, {Name: "hidden"}}
+	if err := db.WithContext(ctx).Create(&users).Error; err != nil {
+		t.Fatalf("failed to create users, got error %v", err)
+	}
+
+	if users[0].Creator != "jinzhu" {
+		t.Errorf("BeforeCreateContext should receive the statement context, got creator %q", users[0].Creator)
+	}
+
+	var result []HookUser
+	if err := db.Find(&result).Error; err != nil {
+		t.Fatalf("failed to find users, got error %v", err)
+	}
+
+	if len(result) != 1 || result[0].Name != "visible" {
+		t.Fatalf("BeforeFind should filter hidden users, got %+v", result)
+	}
+
+	if !result[0].Scanned || result[0].Creator != "jinzhu" {
+		t.Errorf("AfterScanRow should be called for each row, got %+v", result[0])
+	}
+
+	missing := HookUser{Name: "missing"}
+	if err := db.First(&missing).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("BeforeFind should be called on the dest struct, got error %v", err)
+	}
+
+	result = []HookUser{{Name: "missing"}}
+	if err := db.Find(&result).Error; err != nil || len(result) != 1 {
+		t.Errorf("BeforeFind should be called on a zero model for slice dest, got %+v, error %v", result, err)
+	}
+
+	var skipped []HookUser
+	db.Session(&gorm.Session{SkipHooks: true}).Find(&skipped)
+	if len(skipped) != 2 || skipped[0].Scanned {
+		t.Errorf("hooks should be skipped, got %+v", skipped)
+	}
+
+	user := users[0]
+	var hookErr *gorm.HookError
+	if err := db.Model(&user).Association("Pets").Append(); !errors.As(err, &hookErr) || hookErr.Hook != "BeforeAssociationAppend" {
+		t.Errorf("BeforeAssociationAppend error should be returned as HookError, got %v", err)
+	}
+
+	pet := HookPet{Name: "pet"}
+	if err := db.Model(&user).Association("Pets").Append(&pet); err != nil {
+		t.Fatalf("failed to append pets, got error %v", err)
+	}
+
+	if err := db.Model(&user).Association("Pets").Delete(&pet); err != nil {
+		t.Fatalf("failed to delete pets, got error %v", err)
+	}
+
+	if len(user.Appended) != 1 || user.Appended[0] != "Pets" || len(user.Deleted) != 1 || user.Deleted[0] != "Pets" {
+		t.Errorf("association hooks should be called, got appended %v, deleted %v", user.Appended, user.Deleted)
+	}
+
+	owners := []HookUser{users[0], users[1]}
+	if err := db.Model(&owners).Association("Pets").Append(&HookPet{Name: "a"}, &HookPet{Name: "b"}); err != nil {
+		t.Fatalf("failed to append pets of slice owners, got error %v", err)
+	}
+
+	for _, owner := range owners {
+		if len(owner.Appended) != 1 || owner.Appended[0] != "Pets" {
+			t.Errorf("association hooks should be called on each owner, got %+v", owner)
+		}
+	}
+}
+
+var errInvalidAccount = errors.New("invalid account")
+
+type HookAccount struct {
+	ID       uint
+	Email    string `gorm:"uniqueIndex"`
+	Name     string
+	Upserted int
+}
+
+func (a *HookAccount) BeforeUpsert(tx *gorm.DB) error {
+	if a.Name == "" {
+		return errInvalidAccount
+	}
+	a.Upserted++
+	return nil
+}
+
+func TestBeforeUpsertHook(t *testing.T) {
+	db := testdb.Open(t, nil, &HookAccount{})
+
+	account := HookAccount{Email: "a@example.org", Name: "a"}
+	if err := db.Create(&account).Error; err != nil || account.Upserted != 0 {
+		t.Fatalf("BeforeUpsert should not be called when creating, got %+v, error %v", account, err)
+	}
+
+	accounts := []HookAccount{{Email: "a@example.org", Name: "b"}, {Email: "b@example.org", Name: "b"}}
+	if err := db.Upsert(&accounts, gorm.UpsertOptions{Except: []string{"upserted"}}).Error; err != nil {
+		t.Fatalf("failed to upsert, got error %v", err)
+	}
+
+	for _, account := range accounts {
+		if account.Upserted != 1 {
+			t.Errorf("BeforeUpsert should be called on each value, got %+v", account)
+		}
+	}
+
+	var hookErr *gorm.HookError
+	err := db.Upsert(&HookAccount{Email: "c@example.org"}, gorm.UpsertOptions{}).Error
+	if !errors.As(err, &hookErr) || hookErr.Hook != "BeforeUpsert" || !errors.Is(err, errInvalidAccount) {
+		t.Errorf("BeforeUpsert error should be returned as HookError, got %v", err)
+	}
+
+	var count int64
+	if db.Model(&HookAccount{}).Count(&count); count != 2 {
+		t.Errorf("values should not be upserted if BeforeUpsert failed, got %v rows", count)
+	}
+}
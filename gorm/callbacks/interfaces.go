@@ -1,6 +1,10 @@
 package callbacks
 
-import "github.com/fangxing98/jx-gorm/gorm"
+import (
+	"context"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+)
 
 type BeforeCreateInterface interface {
 	BeforeCreate(*gorm.DB) error
@@ -37,3 +41,70 @@ type AfterDeleteInterface interface {
 type AfterFindInterface interface {
 	AfterFind(*gorm.DB) error
 }
+
+// BeforeFindInterface called once before querying, on the dest if it is a struct, otherwise on a zero model
+// as no rows are scanned yet when the dest is a slice
+type BeforeFindInterface interface {
+	BeforeFind(*gorm.DB) error
+}
+
+// BeforeUpsertInterface called before BeforeCreate when the conflicting rows are updated, e.g. by Upsert or an OnConflict clause
+type BeforeUpsertInterface interface {
+	BeforeUpsert(*gorm.DB) error
+}
+
+// transaction hooks, called after the transaction committed or rolled back
+
+type AfterCommitInterface interface {
+	AfterCommit(*gorm.DB)
+}
+
+type AfterRollbackInterface interface {
+	AfterRollback(*gorm.DB)
+}
+
+// context-first hooks, called with the context of the statement
+
+type BeforeCreateContextInterface interface {
+	BeforeCreateContext(context.Context, *gorm.DB) error
+}
+
+type AfterCreateContextInterface interface {
+	AfterCreateContext(context.Context, *gorm.DB) error
+}
+
+type BeforeUpdateContextInterface interface {
+	BeforeUpdateContext(context.Context, *gorm.DB) error
+}
+
+type AfterUpdateContextInterface interface {
+	AfterUpdateContext(context.Context, *gorm.DB) error
+}
+
+type BeforeSaveContextInterface interface {
+	BeforeSaveContext(context.Context, *gorm.DB) error
+}
+
+type AfterSaveContextInterface interface {
+	AfterSaveContext(context.Context, *gorm.DB) error
+}
+
+type BeforeDeleteContextInterface interface {
+	BeforeDeleteContext(context.Context, *gorm.DB) error
+}
+
+type AfterDeleteContextInterface interface {
+	AfterDeleteContext(context.Context, *gorm.DB) error
+}
+
+type BeforeUpsertContextInterface interface {
+	BeforeUpsertContext(context.Context, *gorm.DB) error
+}
+
+type BeforeFindContextInterface interface {
+	BeforeFindContext(context.Context, *gorm.DB) error
+}
+
+type AfterFindContextInterface interface {
+	AfterFindContext(context.Context, *gorm.DB) error
+}
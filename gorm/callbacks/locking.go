@@ -0,0 +1,67 @@
+package callbacks
+
+import (
+	"time"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+const (
+	lockTimeoutResetKey       = "gorm:lock_timeout_reset"
+	lockTimeoutTransactionKey = "gorm:lock_timeout_transaction"
+)
+
+// SetLockTimeout sets the lock wait timeout of LockForUpdate before querying, a transaction is began if not
+// in a transaction, so the timeout is set on the connection of the query
+func SetLockTimeout(db *gorm.DB) {
+	if db.Error != nil || db.DryRun {
+		return
+	}
+
+	value, ok := db.Get(gorm.LockTimeoutSettingKey)
+	timeout, isDuration := value.(time.Duration)
+	dialector, isLockTimeoutDialector := db.Dialector.(gorm.LockTimeoutDialectorInterface)
+	if !ok || !isDuration || !isLockTimeoutDialector {
+		return
+	}
+
+	set, reset := dialector.LockTimeout(timeout)
+	if set == "" {
+		return
+	}
+
+	if _, ok := db.Statement.ConnPool.(gorm.TxCommitter); !ok {
+		tx := db.Begin()
+		if tx.Error != nil {
+			db.AddError(tx.Error)
+			return
+		}
+		// save the conn pool of the statement, e.g. prepared statement pool, to restore it after the transaction
+		db.InstanceSet(lockTimeoutTransactionKey, db.Statement.ConnPool)
+		db.Statement.UseTransaction(tx)
+	}
+
+	if _, err := db.Statement.ConnPool.ExecContext(db.Statement.Context, set); err != nil {
+		db.AddError(err)
+	}
+	db.InstanceSet(lockTimeoutResetKey, reset)
+}
+
+// ResetLockTimeout resets the lock wait timeout after querying, commits the transaction began by SetLockTimeout
+func ResetLockTimeout(db *gorm.DB) {
+	if reset, ok := db.InstanceGet(lockTimeoutResetKey); ok && reset != "" {
+		// the session setting should be reset even if the query failed, e.g. lock wait timeout
+		if _, err := db.Statement.ConnPool.ExecContext(db.Statement.Context, reset.(string)); err != nil && db.Error == nil {
+			db.AddError(err)
+		}
+	}
+
+	if connPool, ok := db.InstanceGet(lockTimeoutTransactionKey); ok {
+		if db.Error != nil {
+			db.Rollback()
+		} else {
+			db.Commit()
+		}
+		db.Statement.ConnPool = connPool.(gorm.ConnPool)
+	}
+}
@@ -266,24 +266,52 @@ func preload(tx *gorm.DB, rel *schema.Relationship, conds []interface{}, preload
 		}
 	}
 
-	// nested preload
-	for p, pvs := range preloads {
-		tx = tx.Preload(p, pvs...)
-	}
-
 	reflectResults := rel.FieldSchema.MakeSlice().Elem()
 	column, values := schema.ToQueryValues(clause.CurrentTable, relForeignKeys, foreignValues)
 
 	if len(values) != 0 {
+		var preloadLimit *gorm.PreloadLimit
 		for _, cond := range conds {
-			if fc, ok := cond.(func(*gorm.DB) *gorm.DB); ok {
-				tx = fc(tx)
-			} else {
+			switch v := cond.(type) {
+			case func(*gorm.DB) *gorm.DB:
+				tx = v(tx)
+			case gorm.PreloadLimit:
+				preloadLimit = &v
+			case *gorm.PreloadLimit:
+				preloadLimit = v
+			default:
 				inlineConds = append(inlineConds, cond)
 			}
 		}
 
-		if err := tx.Where(clause.IN{Column: column, Values: values}).Find(reflectResults.Addr().Interface(), inlineConds...).Error; err != nil {
+		if preloadLimit != nil && preloadLimit.Limit <= 0 {
+			// no limit, only orders the records
+			tx = tx.Order(preloadLimit.Order)
+			preloadLimit = nil
+		}
+
+		if preloadLimit != nil {
+			if len(inlineConds) > 0 {
+				if exprs := tx.Statement.BuildCondition(inlineConds[0], inlineConds[1:]...); len(exprs) > 0 {
+					tx.Statement.AddClause(clause.Where{Exprs: exprs})
+				}
+				inlineConds = nil
+			}
+
+			var err error
+			if tx, err = preloadLimitDB(tx, rel, reflectResults, foreignValues, *preloadLimit); err != nil {
+				return err
+			}
+		} else {
+			tx = tx.Where(clause.IN{Column: column, Values: values})
+		}
+
+		// nested preload
+		for p, pvs := range preloads {
+			tx = tx.Preload(p, pvs...)
+		}
+
+		if err := tx.Find(reflectResults.Addr().Interface(), inlineConds...).Error; err != nil {
 			return err
 		}
 	}
@@ -343,3 +371,105 @@ func preload(tx *gorm.DB, rel *schema.Relationship, conds []interface{}, preload
 
 	return tx.Error
 }
+
+// preloadLimitColumn the row number column of the records of each parent
+const preloadLimitColumn = "gorm_preload_rn"
+
+// preloadLimitDB limits the preloaded records of each parent, returns the db queries the limited records
+//
+//	// ROW_NUMBER() window function
+//	SELECT * FROM (SELECT "orders".*, ROW_NUMBER() OVER (PARTITION BY "orders"."user_id" ORDER BY created_at DESC) AS "gorm_preload_rn"
+//	FROM "orders" WHERE "orders"."user_id" IN (1,2)) AS gorm_preload WHERE "gorm_preload_rn" <= 5 ORDER BY "gorm_preload"."user_id","gorm_preload"."gorm_preload_rn"
+//	// LATERAL join
+//	SELECT "gorm_preload".* FROM "users" AS gorm_parent CROSS JOIN LATERAL (SELECT * FROM "orders" WHERE "orders"."user_id" = "gorm_parent"."id"
+//	ORDER BY created_at DESC LIMIT 5) AS gorm_preload WHERE "gorm_parent"."id" IN (1,2)
+func preloadLimitDB(tx *gorm.DB, rel *schema.Relationship, reflectResults reflect.Value, foreignValues [][]interface{}, opts gorm.PreloadLimit) (*gorm.DB, error) {
+	if rel.JoinTable != nil || (rel.Type != schema.HasOne && rel.Type != schema.HasMany) {
+		return tx, fmt.Errorf("%s: %w, per parent limit requires has one or has many relations", rel.Name, gorm.ErrUnsupportedRelation)
+	}
+
+	var (
+		relForeignKeys    = make([]string, 0, len(rel.References))
+		parentForeignKeys = make([]string, 0, len(rel.References))
+	)
+
+	// same order as the foreign values
+	for _, ref := range rel.References {
+		if ref.OwnPrimaryKey {
+			relForeignKeys = append(relForeignKeys, ref.ForeignKey.DBName)
+			parentForeignKeys = append(parentForeignKeys, ref.PrimaryKey.DBName)
+		}
+	}
+
+	// the order of the records of each parent
+	orderBy, ok := tx.Statement.Clauses["ORDER BY"].Expression.(clause.OrderBy)
+	delete(tx.Statement.Clauses, "ORDER BY")
+
+	if opts.Order != "" {
+		orderBy = clause.OrderBy{Columns: []clause.OrderByColumn{{Column: clause.Column{Name: opts.Order, Raw: true}}}}
+	} else if !ok {
+		orderColumns := make([]clause.OrderByColumn, 0, len(rel.FieldSchema.PrimaryFieldDBNames))
+		for _, dbName := range rel.FieldSchema.PrimaryFieldDBNames {
+			orderColumns = append(orderColumns, clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: dbName}})
+		}
+
+		if len(orderColumns) == 0 {
+			for _, dbName := range relForeignKeys {
+				orderColumns = append(orderColumns, clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: dbName}})
+			}
+		}
+		orderBy = clause.OrderBy{Columns: orderColumns}
+	}
+
+	tx = tx.Model(reflectResults.Addr().Interface())
+	outerDB := tx.Session(&gorm.Session{NewDB: true, Context: tx.Statement.Context})
+
+	if opts.Lateral && tx.IsPgDriver() {
+		for idx, dbName := range relForeignKeys {
+			tx = tx.Where(clause.Eq{
+				Column: clause.Column{Table: clause.CurrentTable, Name: dbName},
+				Value:  clause.Column{Table: "gorm_parent", Name: parentForeignKeys[idx]},
+			})
+		}
+		tx.Statement.AddClause(orderBy)
+
+		column, values := schema.ToQueryValues("gorm_parent", parentForeignKeys, foreignValues)
+		outerDB = outerDB.Table("? AS gorm_parent CROSS JOIN LATERAL (?) AS gorm_preload", clause.Table{Name: rel.Schema.Table}, tx.Limit(opts.Limit)).
+			Select("?.*", clause.Table{Name: "gorm_preload"}).Where(clause.IN{Column: column, Values: values})
+	} else {
+		var (
+			partitionSQL  strings.Builder
+			vars          = []interface{}{clause.Table{Name: clause.CurrentTable}}
+			outerOrderBys = make([]clause.OrderByColumn, 0, len(relForeignKeys)+1)
+		)
+
+		for idx, dbName := range relForeignKeys {
+			if idx > 0 {
+				partitionSQL.WriteByte(',')
+			}
+			partitionSQL.WriteByte('?')
+			vars = append(vars, clause.Column{Table: clause.CurrentTable, Name: dbName})
+			outerOrderBys = append(outerOrderBys, clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: dbName}})
+		}
+		vars = append(vars, windowOrderBy(orderBy), clause.Column{Name: preloadLimitColumn})
+		outerOrderBys = append(outerOrderBys, clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: preloadLimitColumn}})
+
+		column, values := schema.ToQueryValues(clause.CurrentTable, relForeignKeys, foreignValues)
+		tx = tx.Where(clause.IN{Column: column, Values: values}).
+			Select("?.*, ROW_NUMBER() OVER (PARTITION BY "+partitionSQL.String()+" ORDER BY ?) AS ?", vars...)
+
+		outerDB = outerDB.Table("(?) AS gorm_preload", tx).
+			Where(clause.Lte{Column: clause.Column{Name: preloadLimitColumn}, Value: opts.Limit}).
+			Order(clause.OrderBy{Columns: outerOrderBys})
+	}
+
+	outerDB.Statement.Unscoped = tx.Statement.Unscoped
+	return outerDB, nil
+}
+
+// windowOrderBy builds the columns of ORDER BY clause without the keyword, e.g. ROW_NUMBER() OVER (ORDER BY ?)
+type windowOrderBy clause.OrderBy
+
+func (orderBy windowOrderBy) Build(builder clause.Builder) {
+	clause.OrderBy(orderBy).Build(builder)
+}
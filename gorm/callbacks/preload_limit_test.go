@@ -0,0 +1,104 @@
+package callbacks_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/internal/testdb"
+)
+
+type PreloadOrder struct {
+	ID            uint
+	PreloadUserID uint
+	State         string
+	Amount        int
+}
+
+type PreloadUser struct {
+	ID          uint
+	Name        string
+	Orders      []PreloadOrder
+	LatestOrder *PreloadOrder
+	Owner       *PreloadOwner
+	OwnerID     uint
+}
+
+type PreloadOwner struct {
+	ID uint
+}
+
+func TestPreloadLimit(t *testing.T) {
+	db := testdb.Open(t, nil, &PreloadOwner{}, &PreloadUser{}, &PreloadOrder{})
+
+	users := []PreloadUser{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	for i := range users {
+		for j := 1; j <= 4; j++ {
+			state := "paid"
+			if j%2 == 0 {
+				state = "cancelled"
+			}
+			users[i].Orders = append(users[i].Orders, PreloadOrder{State: state, Amount: i*10 + j})
+		}
+	}
+	users[2].Orders = nil
+
+	if err := db.Create(&users).Error; err != nil {
+		t.Fatalf("failed to create users, got error %v", err)
+	}
+
+	var result []PreloadUser
+	if err := db.Preload("Orders", gorm.PreloadLimit{Limit: 3, Order: "amount DESC"}).Order("id").Find(&result).Error; err != nil {
+		t.Fatalf("failed to preload, got error %v", err)
+	}
+
+	if len(result) != 3 || len(result[0].Orders) != 3 || len(result[1].Orders) != 3 || len(result[2].Orders) != 0 {
+		t.Fatalf("should preload 3 orders of each user, got %+v", result)
+	}
+
+	for i, user := range result[:2] {
+		for j, order := range user.Orders {
+			if expected := i*10 + 4 - j; order.Amount != expected || order.PreloadUserID != user.ID {
+				t.Errorf("order %d of user %d should be amount %d, got %+v", j, i, expected, order)
+			}
+		}
+	}
+
+	result = nil
+	if err := db.Preload("Orders", gorm.PreloadLimit{Limit: 1}, func(db *gorm.DB) *gorm.DB {
+		return db.Where("state = ?", "paid").Order("amount DESC")
+	}).Order("id").Find(&result).Error; err != nil {
+		t.Fatalf("failed to preload, got error %v", err)
+	}
+
+	if len(result[0].Orders) != 1 || result[0].Orders[0].Amount != 3 || len(result[1].Orders) != 1 || result[1].Orders[0].Amount != 13 {
+		t.Errorf("should preload the max paid order of each user, got %+v", result)
+	}
+
+	result = nil
+	if err := db.Preload("LatestOrder", gorm.PreloadLimit{Limit: 1, Order: "amount DESC"}).Order("id").Find(&result).Error; err != nil {
+		t.Fatalf("failed to preload, got error %v", err)
+	}
+
+	if result[0].LatestOrder == nil || result[0].LatestOrder.Amount != 4 || result[1].LatestOrder == nil || result[1].LatestOrder.Amount != 14 || result[2].LatestOrder != nil {
+		t.Errorf("should preload the latest order of each user, got %+v", result)
+	}
+
+	var user PreloadUser
+	if err := db.Preload("Orders", gorm.PreloadLimit{Limit: 2}, "state = ?", "cancelled").First(&user, users[1].ID).Error; err != nil {
+		t.Fatalf("failed to preload, got error %v", err)
+	}
+
+	if len(user.Orders) != 2 || user.Orders[0].Amount != 12 || user.Orders[1].Amount != 14 {
+		t.Errorf("should preload the cancelled orders ordered by primary key, got %+v", user.Orders)
+	}
+
+	owner := PreloadOwner{}
+	db.Create(&owner)
+	db.Model(&PreloadUser{}).Where("id = ?", users[0].ID).Update("owner_id", owner.ID)
+
+	err := db.Preload("Owner", gorm.PreloadLimit{Limit: 1}).Find(&result).Error
+	if !errors.Is(err, gorm.ErrUnsupportedRelation) {
+		t.Errorf("per parent limit of belongs to relation should be unsupported, got %v", err)
+	}
+}
@@ -11,6 +11,27 @@ import (
 	"github.com/fangxing98/jx-gorm/gorm/utils"
 )
 
+// BeforeQuery before query hooks, called once on the dest struct, or on a zero model when the dest is a slice
+func BeforeQuery(db *gorm.DB) {
+	if db.Error == nil && db.Statement.Schema != nil && !db.Statement.SkipHooks && db.Statement.Schema.BeforeFind {
+		var value interface{}
+		if db.Statement.ReflectValue.Kind() == reflect.Struct && db.Statement.ReflectValue.CanAddr() {
+			value = db.Statement.ReflectValue.Addr().Interface()
+		} else {
+			value = reflect.New(db.Statement.Schema.ModelType).Interface()
+		}
+
+		tx := db.Session(&gorm.Session{NewDB: true})
+		if i, ok := value.(BeforeFindInterface); ok {
+			db.AddError(gorm.NewHookError("BeforeFind", i.BeforeFind(tx)))
+		}
+
+		if i, ok := value.(BeforeFindContextInterface); ok {
+			db.AddError(gorm.NewHookError("BeforeFindContext", i.BeforeFindContext(tx.Statement.Context, tx)))
+		}
+	}
+}
+
 func Query(db *gorm.DB) {
 	if db.Error == nil {
 		BuildQuerySQL(db)
@@ -145,15 +166,16 @@ func BuildQuerySQL(db *gorm.DB) {
 					}
 
 					if isRelations {
-						genJoinClause := func(joinType clause.JoinType, parentTableName string, relation *schema.Relationship) clause.Join {
+						// the selects, omits and conditions of the join only apply to the last relation of nested joins
+						genJoinClause := func(joinType clause.JoinType, parentTableName string, relation *schema.Relationship, last bool) clause.Join {
 							tableAliasName := relation.Name
 							if parentTableName != clause.CurrentTable {
 								tableAliasName = utils.NestedRelationName(parentTableName, tableAliasName)
 							}
 
-							columnStmt := gorm.Statement{
-								Table: tableAliasName, DB: db, Schema: relation.FieldSchema,
-								Selects: join.Selects, Omits: join.Omits,
+							columnStmt := gorm.Statement{Table: tableAliasName, DB: db, Schema: relation.FieldSchema}
+							if last {
+								columnStmt.Selects, columnStmt.Omits = join.Selects, join.Omits
 							}
 
 							selectColumns, restricted := columnStmt.SelectAndOmitColumns(false, false)
@@ -195,7 +217,7 @@ func BuildQuerySQL(db *gorm.DB) {
 									onStmt.AddClause(c)
 								}
 
-								if join.On != nil {
+								if join.On != nil && last {
 									onStmt.AddClause(join.On)
 								}
 
@@ -226,11 +248,11 @@ func BuildQuerySQL(db *gorm.DB) {
 						}
 
 						parentTableName := clause.CurrentTable
-						for _, rel := range relations {
+						for idx, rel := range relations {
 							// joins table alias like "Manager, Company, Manager__Company"
 							nestedAlias := utils.NestedRelationName(parentTableName, rel.Name)
 							if _, ok := specifiedRelationsName[nestedAlias]; !ok {
-								fromClause.Joins = append(fromClause.Joins, genJoinClause(join.JoinType, parentTableName, rel))
+								fromClause.Joins = append(fromClause.Joins, genJoinClause(join.JoinType, parentTableName, rel, idx == len(relations)-1))
 								specifiedRelationsName[nestedAlias] = nil
 							}
 
@@ -292,12 +314,17 @@ func AfterQuery(db *gorm.DB) {
 		db.Statement.Clauses["FROM"] = fromClause
 	}
 	if db.Error == nil && db.Statement.Schema != nil && !db.Statement.SkipHooks && db.Statement.Schema.AfterFind && db.RowsAffected > 0 {
-		callMethod(db, func(value interface{}, tx *gorm.DB) bool {
+		callMethod(db, func(value interface{}, tx *gorm.DB) (called bool) {
 			if i, ok := value.(AfterFindInterface); ok {
-				db.AddError(i.AfterFind(tx))
-				return true
+				called = true
+				db.AddError(gorm.NewHookError("AfterFind", i.AfterFind(tx)))
+			}
+
+			if i, ok := value.(AfterFindContextInterface); ok {
+				called = true
+				db.AddError(gorm.NewHookError("AfterFindContext", i.AfterFindContext(tx.Statement.Context, tx)))
 			}
-			return false
+			return called
 		})
 	}
 }
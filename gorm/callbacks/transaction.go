@@ -7,7 +7,7 @@ import (
 func BeginTransaction(db *gorm.DB) {
 	if !db.Config.SkipDefaultTransaction && db.Error == nil {
 		if tx := db.Begin(); tx.Error == nil {
-			db.Statement.ConnPool = tx.Statement.ConnPool
+			db.Statement.UseTransaction(tx)
 			db.InstanceSet("gorm:started_transaction", true)
 		} else if tx.Error == gorm.ErrInvalidTransaction {
 			tx.Error = nil
@@ -30,3 +30,29 @@ func CommitOrRollbackTransaction(db *gorm.DB) {
 		}
 	}
 }
+
+// TransactionHooks registers AfterCommit and AfterRollback hooks of models to the current transaction,
+// hooks are called with a new session outside of the transaction
+func TransactionHooks(db *gorm.DB) {
+	if db.Statement.Schema != nil && !db.Statement.SkipHooks && (db.Statement.Schema.AfterCommit || db.Statement.Schema.AfterRollback) {
+		tx := db.Session(&gorm.Session{NewDB: true, Context: db.Statement.Context})
+		tx.Statement.ConnPool = db.ConnPool
+
+		callMethod(db, func(value interface{}, _ *gorm.DB) (called bool) {
+			if db.Statement.Schema.AfterCommit && db.Error == nil {
+				if i, ok := value.(AfterCommitInterface); ok {
+					called = true
+					db.AfterCommit(func() { i.AfterCommit(tx) })
+				}
+			}
+
+			if db.Statement.Schema.AfterRollback {
+				if i, ok := value.(AfterRollbackInterface); ok {
+					called = true
+					db.AfterRollback(func() { i.AfterRollback(tx) })
+				}
+			}
+			return called
+		})
+	}
+}
@@ -36,14 +36,24 @@ func BeforeUpdate(db *gorm.DB) {
 			if db.Statement.Schema.BeforeSave {
 				if i, ok := value.(BeforeSaveInterface); ok {
 					called = true
-					db.AddError(i.BeforeSave(tx))
+					db.AddError(gorm.NewHookError("BeforeSave", i.BeforeSave(tx)))
+				}
+
+				if i, ok := value.(BeforeSaveContextInterface); ok {
+					called = true
+					db.AddError(gorm.NewHookError("BeforeSaveContext", i.BeforeSaveContext(tx.Statement.Context, tx)))
 				}
 			}
 
 			if db.Statement.Schema.BeforeUpdate {
 				if i, ok := value.(BeforeUpdateInterface); ok {
 					called = true
-					db.AddError(i.BeforeUpdate(tx))
+					db.AddError(gorm.NewHookError("BeforeUpdate", i.BeforeUpdate(tx)))
+				}
+
+				if i, ok := value.(BeforeUpdateContextInterface); ok {
+					called = true
+					db.AddError(gorm.NewHookError("BeforeUpdateContext", i.BeforeUpdateContext(tx.Statement.Context, tx)))
 				}
 			}
 
@@ -111,14 +121,24 @@ func AfterUpdate(db *gorm.DB) {
 			if db.Statement.Schema.AfterUpdate {
 				if i, ok := value.(AfterUpdateInterface); ok {
 					called = true
-					db.AddError(i.AfterUpdate(tx))
+					db.AddError(gorm.NewHookError("AfterUpdate", i.AfterUpdate(tx)))
+				}
+
+				if i, ok := value.(AfterUpdateContextInterface); ok {
+					called = true
+					db.AddError(gorm.NewHookError("AfterUpdateContext", i.AfterUpdateContext(tx.Statement.Context, tx)))
 				}
 			}
 
 			if db.Statement.Schema.AfterSave {
 				if i, ok := value.(AfterSaveInterface); ok {
 					called = true
-					db.AddError(i.AfterSave(tx))
+					db.AddError(gorm.NewHookError("AfterSave", i.AfterSave(tx)))
+				}
+
+				if i, ok := value.(AfterSaveContextInterface); ok {
+					called = true
+					db.AddError(gorm.NewHookError("AfterSaveContext", i.AfterSaveContext(tx.Statement.Context, tx)))
 				}
 			}
 
@@ -208,6 +228,17 @@ func ConvertToAssignments(stmt *gorm.Statement) (set clause.Set) {
 				if field := stmt.Schema.LookUpField(k); field != nil {
 					if field.DBName != "" {
 						if v, ok := selectColumns[field.DBName]; (ok && v) || (!ok && !restricted) {
+							if _, isExpr := kv.(clause.Expression); !isExpr {
+								// encrypt values of encrypted fields, never write plaintext
+								if _, ok := field.Serializer.(schema.BlindIndexer); ok {
+									kv = field.SerializedValueOf(stmt.Context, value[k])
+
+									if blindIndex := field.BlindIndex; blindIndex != nil && value[blindIndex.Name] == nil && value[blindIndex.DBName] == nil {
+										set = append(set, clause.Assignment{Column: clause.Column{Name: blindIndex.DBName}, Value: field.BlindIndexValueOf(stmt.Context, value[k])})
+									}
+								}
+							}
+
 							set = append(set, clause.Assignment{Column: clause.Column{Name: field.DBName}, Value: kv})
 							assignValue(field, value[k])
 						}
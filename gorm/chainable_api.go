@@ -1,6 +1,7 @@
 package gorm
 
 import (
+	"reflect"
 	"regexp"
 	"strings"
 
@@ -400,6 +401,68 @@ func (db *DB) Preload(query string, args ...interface{}) (tx *DB) {
 	return
 }
 
+// PreloadLimit limits the preloaded records of each parent, it is an argument of Preload, only supports has one and
+// has many relations, the records are numbered with ROW_NUMBER() window function (MySQL 8+, SQLite 3.25+, Postgres,
+// SQL Server), or limited with LATERAL join on postgres
+//
+//	// preload the latest 5 orders of each user
+//	db.Preload("Orders", gorm.PreloadLimit{Limit: 5, Order: "created_at DESC"}).Find(&users)
+//	// order with the conditions
+//	db.Preload("Orders", gorm.PreloadLimit{Limit: 5}, func(db *gorm.DB) *gorm.DB {
+//		return db.Where("state = ?", "paid").Order("created_at DESC")
+//	}).Find(&users)
+type PreloadLimit struct {
+	// Limit max records of each parent
+	Limit int
+	// Order orders the records of each parent, the ORDER BY of conditions or primary keys are used if empty
+	Order string
+	// Lateral limits with LATERAL join instead of ROW_NUMBER() window function, ignored if not postgres
+	Lateral bool
+}
+
+// WithCount loads the count of relation records with given conditions into the fields tagged with
+// `aggregate:<relation>.count`, the records are counted with a grouped query after the query
+//
+//	type User struct {
+//		ID          uint
+//		Orders      []Order
+//		OrdersCount int `gorm:"->;aggregate:Orders.count"`
+//	}
+//	// SELECT user_id, COUNT(*) FROM orders WHERE user_id IN (1,2) AND state = 'paid' GROUP BY user_id
+//	db.WithCount("Orders", "state = ?", "paid").Find(&users)
+func (db *DB) WithCount(name string, args ...interface{}) (tx *DB) {
+	return db.WithAggregate(name, "COUNT", "*", args...)
+}
+
+// WithAggregate loads the aggregate of relation records with given conditions into the fields tagged with
+// `aggregate:<relation>.<func>.<column>`, e.g. SUM, AVG, MIN, MAX, or into the destination if the first argument
+// is a pointer to a value (querying a single record) or to a map keyed by primary keys of the records
+//
+//	type User struct {
+//		ID          uint
+//		Orders      []Order
+//		OrdersTotal float64 `gorm:"->;aggregate:Orders.sum.amount"`
+//	}
+//	// SELECT user_id, SUM(amount) FROM orders WHERE user_id IN (1,2) GROUP BY user_id
+//	db.WithAggregate("Orders", "SUM", "amount").Find(&users)
+//
+//	var total float64
+//	db.WithAggregate("Orders", "SUM", "amount", &total).First(&user)
+//	var totals map[uint]float64
+//	db.WithAggregate("Orders", "SUM", "amount", &totals, "state = ?", "paid").Find(&users)
+func (db *DB) WithAggregate(name, fn, column string, args ...interface{}) (tx *DB) {
+	tx = db.getInstance()
+	aggregate := Aggregate{Relation: name, Func: fn, Column: column, Conds: args}
+	if len(args) > 0 {
+		// pointers to structs are conditions
+		if rv := reflect.ValueOf(args[0]); rv.Kind() == reflect.Ptr && rv.Type().Elem().Kind() != reflect.Struct {
+			aggregate.Dest, aggregate.Conds = args[0], args[1:]
+		}
+	}
+	tx.Statement.Aggregates = append(tx.Statement.Aggregates, aggregate)
+	return
+}
+
 // Attrs provide attributes used in [FirstOrCreate] or [FirstOrInit]
 //
 // Attrs only adds attributes if the record is not found.
@@ -12,7 +12,7 @@ import (
 	"github.com/fangxing98/jx-gorm/gorm/utils/tests"
 )
 
-var db, _ = gorm.Open(tests.DummyDialector{}, nil)
+var db, _ = gorm.Open(tests.DummyDialector{}, gorm.DBType("dummy"), &gorm.Config{})
 
 func checkBuildClauses(t *testing.T, clauses []clause.Interface, result string, vars []interface{}) {
 	var (
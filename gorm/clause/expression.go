@@ -5,6 +5,7 @@ import (
 	"database/sql/driver"
 	"go/ast"
 	"reflect"
+	"strings"
 )
 
 // Expression expression interface
@@ -31,9 +32,20 @@ func (expr Expr) Build(builder Builder) {
 		idx              int
 	)
 
-	for _, v := range []byte(expr.SQL) {
+	for pos, v := range []byte(expr.SQL) {
 		if v == '?' && len(expr.Vars) > idx {
-			if afterParenthesis || expr.WithoutParentheses {
+			if values, ok := inListValues(builder, expr.SQL[:pos], expr.Vars[idx]); ok {
+				if afterParenthesis || expr.WithoutParentheses {
+					for i, value := range values {
+						if i > 0 {
+							builder.WriteByte(',')
+						}
+						builder.AddVar(builder, value)
+					}
+				} else {
+					builder.AddVar(builder, values)
+				}
+			} else if afterParenthesis || expr.WithoutParentheses {
 				if _, ok := expr.Vars[idx].(driver.Valuer); ok {
 					builder.AddVar(builder, expr.Vars[idx])
 				} else {
@@ -188,6 +200,48 @@ func (expr NamedExpr) Build(builder Builder) {
 	}
 }
 
+// InValuesPadder pads values of IN lists, implemented by builders bounding the variety of SQL, e.g. with prepared statements
+type InValuesPadder interface {
+	PadInValues(values []interface{}) []interface{}
+}
+
+func padInValues(builder Builder, values []interface{}) []interface{} {
+	if padder, ok := builder.(InValuesPadder); ok {
+		return padder.PadInValues(values)
+	}
+	return values
+}
+
+// inListValues returns values of the list v padded by the builder if the placeholder follows IN, e.g. `id IN ?` or `id IN (?)`
+func inListValues(builder Builder, sql string, v interface{}) ([]interface{}, bool) {
+	padder, ok := builder.(InValuesPadder)
+	if !ok {
+		return nil, false
+	} else if _, ok := v.(driver.Valuer); ok {
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(v)
+	if (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) || rv.Len() < 2 {
+		return nil, false
+	}
+
+	sql = strings.TrimRight(strings.TrimSuffix(strings.TrimRight(sql, " "), "("), " ")
+	if len(sql) < 2 || !strings.EqualFold(sql[len(sql)-2:], "IN") || (len(sql) > 2 && isIdentifierChar(sql[len(sql)-3])) {
+		return nil, false
+	}
+
+	values := make([]interface{}, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+	return padder.PadInValues(values), true
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c == '.' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
 // IN Whether a value is within a set of values
 type IN struct {
 	Column interface{}
@@ -210,7 +264,7 @@ func (in IN) Build(builder Builder) {
 		fallthrough
 	default:
 		builder.WriteString(" IN (")
-		builder.AddVar(builder, in.Values...)
+		builder.AddVar(builder, padInValues(builder, in.Values)...)
 		builder.WriteByte(')')
 	}
 }
@@ -230,7 +284,7 @@ func (in IN) NegationBuild(builder Builder) {
 		fallthrough
 	default:
 		builder.WriteString(" NOT IN (")
-		builder.AddVar(builder, in.Values...)
+		builder.AddVar(builder, padInValues(builder, in.Values)...)
 		builder.WriteByte(')')
 	}
 }
@@ -22,6 +22,10 @@ func TestExpr(t *testing.T) {
 		SQL:    "create table ? (? ?, ? ?)",
 		Vars:   []interface{}{clause.Table{Name: "users"}, clause.Column{Name: "id"}, clause.Expr{SQL: "int"}, clause.Column{Name: "name"}, clause.Expr{SQL: "text"}},
 		Result: "create table `users` (`id` int, `name` text)",
+	}, {
+		SQL:    "id IN (?)",
+		Vars:   []interface{}{[]uint8{1, 2}},
+		Result: "id IN (?,?)",
 	}}
 
 	for idx, result := range results {
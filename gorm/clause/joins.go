@@ -1,5 +1,11 @@
 package clause
 
+import (
+	"strings"
+
+	"github.com/fangxing98/jx-gorm/gorm/utils"
+)
+
 type JoinType string
 
 const (
@@ -45,3 +51,24 @@ func (join Join) Build(builder Builder) {
 		}
 	}
 }
+
+// JoinedTable returns the table alias of the joined relation, nested relations are separated by dots, e.g.
+//
+//	// "Manager__Company"
+//	clause.JoinedTable("Manager.Company")
+func JoinedTable(relation string) string {
+	names := strings.Split(relation, ".")
+	table := names[0]
+	for _, name := range names[1:] {
+		table = utils.NestedRelationName(table, name)
+	}
+	return table
+}
+
+// JoinedColumn returns the column of the joined relation, e.g.
+//
+//	// SELECT ... LEFT JOIN `companies` `Manager__Company` ON ... WHERE `Manager__Company`.`name` = "jinzhu"
+//	db.Joins("Manager.Company").Where(clause.Eq{Column: clause.JoinedColumn("Manager.Company", "name"), Value: "jinzhu"})
+func JoinedColumn(relation, name string) Column {
+	return Column{Table: JoinedTable(relation), Name: name}
+}
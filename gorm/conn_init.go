@@ -0,0 +1,141 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"time"
+)
+
+// OnConnectFunc initializes a new physical connection before it is added to the pool
+type OnConnectFunc func(ctx context.Context, conn driver.Conn) error
+
+// PoolConfig settings of the connection pool opened by the dialector, zero values keep the defaults of database/sql
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// Apply applies the settings to the connection pool
+func (config PoolConfig) Apply(sqlDB *sql.DB) {
+	if config.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+	}
+	if config.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+	}
+	if config.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime)
+	}
+	if config.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+	}
+}
+
+// OpenDB opens the connection pool like sql.Open, statements and onConnect are applied to every new physical connection
+//
+//	sqlDB, err := gorm.OpenDB("pgx", dsn, []string{"SET search_path TO app", "SET application_name = 'worker'"}, nil)
+func OpenDB(driverName, dsn string, statements []string, onConnect OnConnectFunc) (*sql.DB, error) {
+	sqlDB, err := sql.Open(driverName, dsn)
+	if err != nil || (len(statements) == 0 && onConnect == nil) {
+		return sqlDB, err
+	}
+
+	// database/sql doesn't have hooks of new connections, reopen the pool with the connector of the driver
+	drv := sqlDB.Driver()
+	sqlDB.Close()
+
+	var connector driver.Connector
+	if driverContext, ok := drv.(driver.DriverContext); ok {
+		if connector, err = driverContext.OpenConnector(dsn); err != nil {
+			return nil, err
+		}
+	} else {
+		connector = dsnConnector{dsn: dsn, driver: drv}
+	}
+	return sql.OpenDB(WithConnInit(connector, statements, onConnect)), nil
+}
+
+// WithConnInit returns a connector applying statements and onConnect to every new connection of connector
+func WithConnInit(connector driver.Connector, statements []string, onConnect OnConnectFunc) driver.Connector {
+	if len(statements) == 0 && onConnect == nil {
+		return connector
+	}
+	return &connInitConnector{Connector: connector, statements: statements, onConnect: onConnect}
+}
+
+type connInitConnector struct {
+	driver.Connector
+	statements []string
+	onConnect  OnConnectFunc
+}
+
+// Connect implements driver.Connector, the connection is closed if failed to initialize
+func (c *connInitConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stmt := range c.statements {
+		if err = ExecDriverConn(ctx, conn, stmt); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	if c.onConnect != nil {
+		if err = c.onConnect(ctx, conn); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// dsnConnector connector of drivers not implementing driver.DriverContext
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// ExecDriverConn executes the query without arguments on the driver connection, e.g. in OnConnectFunc
+func ExecDriverConn(ctx context.Context, conn driver.Conn, query string) error {
+	if execer, ok := conn.(driver.ExecerContext); ok {
+		if _, err := execer.ExecContext(ctx, query, nil); !errors.Is(err, driver.ErrSkip) {
+			return err
+		}
+	}
+
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+	if preparer, ok := conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = conn.Prepare(query)
+	}
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	if execer, ok := stmt.(driver.StmtExecContext); ok {
+		_, err = execer.ExecContext(ctx, nil)
+	} else {
+		_, err = stmt.Exec(nil) //nolint:staticcheck
+	}
+	return err
+}
@@ -0,0 +1,78 @@
+package gorm_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/driver/sqlite"
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+func TestConnInitStatements(t *testing.T) {
+	var connected atomic.Int32
+	db, err := gorm.Open(sqlite.New(sqlite.Config{
+		DSN:                filepath.Join(t.TempDir(), "conn_init.db"),
+		ConnInitStatements: []string{"PRAGMA foreign_keys = ON", "PRAGMA busy_timeout = 3000"},
+		OnConnect: func(ctx context.Context, conn driver.Conn) error {
+			connected.Add(1)
+			return gorm.ExecDriverConn(ctx, conn, "PRAGMA cache_size = 1000")
+		},
+		Pool: gorm.PoolConfig{MaxOpenConns: 2, MaxIdleConns: 2, ConnMaxLifetime: time.Hour},
+	}), gorm.DBTypeSqlite, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB, got error %v", err)
+	}
+	if stats := sqlDB.Stats(); stats.MaxOpenConnections != 2 {
+		t.Errorf("max open conns should be 2, got %v", stats.MaxOpenConnections)
+	}
+
+	// hold the connection so a new connection is opened
+	err = db.Connection(func(tx *gorm.DB) error {
+		var foreignKeys, busyTimeout, cacheSize int
+		db.Raw("PRAGMA foreign_keys").Scan(&foreignKeys)
+		db.Raw("PRAGMA busy_timeout").Scan(&busyTimeout)
+		db.Raw("PRAGMA cache_size").Scan(&cacheSize)
+		if foreignKeys != 1 || busyTimeout != 3000 || cacheSize != 1000 {
+			t.Errorf("connection should be initialized, got foreign_keys %v, busy_timeout %v, cache_size %v", foreignKeys, busyTimeout, cacheSize)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to run with connection, got error %v", err)
+	}
+
+	if count := connected.Load(); count != 2 {
+		t.Errorf("every new connection should be initialized, got %v", count)
+	}
+}
+
+func TestConnInitFailed(t *testing.T) {
+	errConnect := errors.New("connect")
+	_, err := gorm.Open(sqlite.New(sqlite.Config{
+		DSN: filepath.Join(t.TempDir(), "conn_init.db"),
+		OnConnect: func(ctx context.Context, conn driver.Conn) error {
+			return errConnect
+		},
+	}), gorm.DBTypeSqlite, &gorm.Config{})
+	if !errors.Is(err, errConnect) {
+		t.Errorf("open should fail if failed to initialize connection, got %v", err)
+	}
+
+	_, err = gorm.Open(sqlite.New(sqlite.Config{
+		DSN:                filepath.Join(t.TempDir(), "conn_init.db"),
+		ConnInitStatements: []string{"PRAGMA invalid syntax here"},
+	}), gorm.DBTypeSqlite, &gorm.Config{})
+	if err == nil {
+		t.Errorf("open should fail with invalid init statements")
+	}
+}
@@ -51,4 +51,35 @@ var (
 	ErrForeignKeyViolated = errors.New("violates foreign key constraint")
 	// ErrCheckConstraintViolated occurs when there is a check constraint violation
 	ErrCheckConstraintViolated = errors.New("violates check constraint")
+	// ErrLockNotAvailable occurs when failed to lock rows with NOWAIT or lock wait timeout
+	ErrLockNotAvailable = errors.New("lock not available")
+	// ErrUnsupportedTxOptions transaction options not supported by the dialector
+	ErrUnsupportedTxOptions = errors.New("unsupported transaction options")
 )
+
+// HookError error returned by the hook of a model, e.g. BeforeCreate, the message of Err is kept
+//
+//	var hookErr *gorm.HookError
+//	if errors.As(db.Create(&user).Error, &hookErr) && hookErr.Hook == "BeforeCreate" {
+//		// ...
+//	}
+type HookError struct {
+	Hook string
+	Err  error
+}
+
+// NewHookError wraps err returned by hook, returns nil if err is nil
+func NewHookError(hook string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &HookError{Hook: hook, Err: err}
+}
+
+func (e *HookError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
@@ -4,9 +4,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"hash/maphash"
+	"math/rand/v2"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/fangxing98/jx-gorm/gorm/clause"
 	"github.com/fangxing98/jx-gorm/gorm/logger"
@@ -617,24 +618,46 @@ func (db *DB) Connection(fc func(tx *DB) error) (err error) {
 
 // Transaction start a transaction as a block, return error will rollback, otherwise to commit. Transaction executes an
 // arbitrary number of commands in fc within a transaction. On success the changes are committed; if an error occurs
-// they are rolled back.
+// they are rolled back. Transactions are retried with Config.TransactionRetry if it is set.
 func (db *DB) Transaction(fc func(tx *DB) error, opts ...*sql.TxOptions) (err error) {
+	if db.TransactionRetry != nil {
+		return db.TransactionWithRetry(fc, *db.TransactionRetry, opts...)
+	}
+	return db.transaction(fc, opts...)
+}
+
+// TransactionWithRetry executes fc within a transaction like Transaction, the transaction is rolled back and fc runs
+// again from scratch if it fails with retryable errors, e.g. deadlocks and serialization failures. Nested transactions
+// run in savepoints without retry, only the outermost transaction retries.
+func (db *DB) TransactionWithRetry(fc func(tx *DB) error, policy RetryPolicy, opts ...*sql.TxOptions) (err error) {
+	if committer, ok := db.Statement.ConnPool.(TxCommitter); ok && committer != nil {
+		return db.transaction(fc, opts...)
+	}
+
+	ctx := db.Statement.Context
+	for attempt := 0; ; attempt++ {
+		if err = db.transaction(fc, opts...); err == nil || attempt >= policy.maxRetries() || !policy.retryable(db, err) {
+			return err
+		}
+
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+func (db *DB) transaction(fc func(tx *DB) error, opts ...*sql.TxOptions) (err error) {
 	panicked := true
 
 	if committer, ok := db.Statement.ConnPool.(TxCommitter); ok && committer != nil {
 		// nested transaction
 		if !db.DisableNestedTransaction {
-			spID := new(maphash.Hash).Sum64()
-			err = db.SavePoint(fmt.Sprintf("sp%d", spID)).Error
-			if err != nil {
-				return
-			}
-			defer func() {
-				// Make sure to rollback when panic, Block error or Commit error
-				if panicked || err != nil {
-					db.RollbackTo(fmt.Sprintf("sp%d", spID))
-				}
-			}()
+			panicked = false
+			return db.WithSavePoint(fc)
 		}
 		err = fc(db.Session(&Session{NewDB: db.clone == 1}))
 	} else {
@@ -660,7 +683,54 @@ func (db *DB) Transaction(fc func(tx *DB) error, opts ...*sql.TxOptions) (err er
 	return
 }
 
-// Begin begins a transaction with any transaction options opts
+// RetryPolicy retry policy of transactions, see TransactionWithRetry
+type RetryPolicy struct {
+	// MaxRetries max retry times after the first attempt, default 3
+	MaxRetries int
+	// BaseDelay delay before the first retry, doubled for each retry with jitter, default 10ms
+	BaseDelay time.Duration
+	// MaxDelay max delay between retries, default 1s
+	MaxDelay time.Duration
+	// Retryable classifies retryable errors, default uses the dialector's RetryableErrorTranslator
+	Retryable func(err error) bool
+}
+
+func (policy RetryPolicy) maxRetries() int {
+	if policy.MaxRetries > 0 {
+		return policy.MaxRetries
+	}
+	return 3
+}
+
+func (policy RetryPolicy) retryable(db *DB, err error) bool {
+	if policy.Retryable != nil {
+		return policy.Retryable(err)
+	}
+	if translator, ok := db.Dialector.(RetryableErrorTranslator); ok {
+		return translator.Retryable(err)
+	}
+	return false
+}
+
+// backoff returns exponential backoff delay of attempt with jitter between [delay/2, delay]
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	baseDelay, maxDelay := policy.BaseDelay, policy.MaxDelay
+	if baseDelay <= 0 {
+		baseDelay = 10 * time.Millisecond
+	}
+	if maxDelay <= 0 {
+		maxDelay = time.Second
+	}
+
+	delay := maxDelay
+	if attempt < 32 && baseDelay<<attempt > 0 && baseDelay<<attempt < maxDelay {
+		delay = baseDelay << attempt
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// Begin begins a transaction with any transaction options opts, opts override isolation level and read-only mode
+// of TxOptions and are translated by the dialector the same way
 func (db *DB) Begin(opts ...*sql.TxOptions) *DB {
 	var (
 		// clone statement
@@ -673,17 +743,46 @@ func (db *DB) Begin(opts ...*sql.TxOptions) *DB {
 		opt = opts[0]
 	}
 
-	switch beginner := tx.Statement.ConnPool.(type) {
-	case TxBeginner:
-		tx.Statement.ConnPool, err = beginner.BeginTx(tx.Statement.Context, opt)
-	case ConnPoolBeginner:
-		tx.Statement.ConnPool, err = beginner.BeginTx(tx.Statement.Context, opt)
-	default:
-		err = ErrInvalidTransaction
+	var plan TxBeginPlan
+	if tx.TxOptions != nil || opt != nil {
+		var txOptions TxOptions
+		if tx.TxOptions != nil {
+			txOptions = *tx.TxOptions
+		}
+		if plan, err = tx.txBeginPlan(txOptions.with(opt)); err == nil {
+			opt = plan.Options
+		}
+	}
+
+	if err == nil && (len(plan.Setup) > 0 || plan.Begin != "") {
+		var connPool ConnPool
+		if connPool, err = beginOnConn(tx.Statement.Context, tx.Statement.ConnPool, plan); err == nil {
+			tx.Statement.ConnPool = connPool
+		}
+	} else if err == nil {
+		switch beginner := tx.Statement.ConnPool.(type) {
+		case TxBeginner:
+			tx.Statement.ConnPool, err = beginner.BeginTx(tx.Statement.Context, opt)
+		case ConnPoolBeginner:
+			tx.Statement.ConnPool, err = beginner.BeginTx(tx.Statement.Context, opt)
+		default:
+			err = ErrInvalidTransaction
+		}
+	}
+
+	if err == nil {
+		for _, stmt := range plan.Statements {
+			if _, err = tx.Statement.ConnPool.ExecContext(tx.Statement.Context, stmt); err != nil {
+				tx.Rollback()
+				break
+			}
+		}
 	}
 
 	if err != nil {
 		tx.AddError(err)
+	} else if inTransaction(tx.Statement.ConnPool) {
+		tx.Statement.transaction = &txState{}
 	}
 
 	return tx
@@ -692,7 +791,9 @@ func (db *DB) Begin(opts ...*sql.TxOptions) *DB {
 // Commit commits the changes in a transaction
 func (db *DB) Commit() *DB {
 	if committer, ok := db.Statement.ConnPool.(TxCommitter); ok && committer != nil && !reflect.ValueOf(committer).IsNil() {
-		db.AddError(committer.Commit())
+		err := committer.Commit()
+		db.AddError(err)
+		db.finishTransaction(err == nil)
 	} else {
 		db.AddError(ErrInvalidTransaction)
 	}
@@ -704,6 +805,7 @@ func (db *DB) Rollback() *DB {
 	if committer, ok := db.Statement.ConnPool.(TxCommitter); ok && committer != nil {
 		if !reflect.ValueOf(committer).IsNil() {
 			db.AddError(committer.Rollback())
+			db.finishTransaction(false)
 		}
 	} else {
 		db.AddError(ErrInvalidTransaction)
@@ -713,20 +815,27 @@ func (db *DB) Rollback() *DB {
 
 func (db *DB) SavePoint(name string) *DB {
 	if savePointer, ok := db.Dialector.(SavePointerDialectorInterface); ok {
+		state := db.txState()
+
 		// close prepared statement, because SavePoint not support prepared statement.
 		// e.g. mysql8.0 doc: https://dev.mysql.com/doc/refman/8.0/en/sql-prepared-statements.html
 		var (
+			connPool         = db.Statement.ConnPool
 			preparedStmtTx   *PreparedStmtTX
 			isPreparedStmtTx bool
 		)
 		// close prepared statement, because SavePoint not support prepared statement.
-		if preparedStmtTx, isPreparedStmtTx = db.Statement.ConnPool.(*PreparedStmtTX); isPreparedStmtTx {
+		if preparedStmtTx, isPreparedStmtTx = unwrapPreparedStmtTX(connPool); isPreparedStmtTx {
 			db.Statement.ConnPool = preparedStmtTx.Tx
 		}
-		db.AddError(savePointer.SavePoint(db, name))
+		if err := savePointer.SavePoint(db, name); err != nil {
+			db.AddError(err)
+		} else if state != nil {
+			state.savePoint(name)
+		}
 		// restore prepared statement
 		if isPreparedStmtTx {
-			db.Statement.ConnPool = preparedStmtTx
+			db.Statement.ConnPool = connPool
 		}
 	} else {
 		db.AddError(ErrUnsupportedDriver)
@@ -736,20 +845,27 @@ func (db *DB) SavePoint(name string) *DB {
 
 func (db *DB) RollbackTo(name string) *DB {
 	if savePointer, ok := db.Dialector.(SavePointerDialectorInterface); ok {
+		state := db.txState()
+
 		// close prepared statement, because RollbackTo not support prepared statement.
 		// e.g. mysql8.0 doc: https://dev.mysql.com/doc/refman/8.0/en/sql-prepared-statements.html
 		var (
+			connPool         = db.Statement.ConnPool
 			preparedStmtTx   *PreparedStmtTX
 			isPreparedStmtTx bool
 		)
 		// close prepared statement, because SavePoint not support prepared statement.
-		if preparedStmtTx, isPreparedStmtTx = db.Statement.ConnPool.(*PreparedStmtTX); isPreparedStmtTx {
+		if preparedStmtTx, isPreparedStmtTx = unwrapPreparedStmtTX(connPool); isPreparedStmtTx {
 			db.Statement.ConnPool = preparedStmtTx.Tx
 		}
-		db.AddError(savePointer.RollbackTo(db, name))
+		if err := savePointer.RollbackTo(db, name); err != nil {
+			db.AddError(err)
+		} else if state != nil {
+			state.rollbackTo(name)
+		}
 		// restore prepared statement
 		if isPreparedStmtTx {
-			db.Statement.ConnPool = preparedStmtTx
+			db.Statement.ConnPool = connPool
 		}
 	} else {
 		db.AddError(ErrUnsupportedDriver)
@@ -757,6 +873,81 @@ func (db *DB) RollbackTo(name string) *DB {
 	return db
 }
 
+// ReleaseSavePoint releases the savepoint name and savepoints created after it, the changes are kept in the transaction
+func (db *DB) ReleaseSavePoint(name string) *DB {
+	if savePointer, ok := db.Dialector.(SavePointerDialectorInterface); ok {
+		state := db.txState()
+
+		// close prepared statement, because ReleaseSavePoint not support prepared statement.
+		var (
+			connPool         = db.Statement.ConnPool
+			preparedStmtTx   *PreparedStmtTX
+			isPreparedStmtTx bool
+		)
+		if preparedStmtTx, isPreparedStmtTx = unwrapPreparedStmtTX(connPool); isPreparedStmtTx {
+			db.Statement.ConnPool = preparedStmtTx.Tx
+		}
+		if err := savePointer.ReleaseSavePoint(db, name); err != nil {
+			db.AddError(err)
+		} else if state != nil {
+			state.release(name)
+		}
+		// restore prepared statement
+		if isPreparedStmtTx {
+			db.Statement.ConnPool = connPool
+		}
+	} else {
+		db.AddError(ErrUnsupportedDriver)
+	}
+	return db
+}
+
+// WithSavePoint executes fc in a savepoint of the current transaction, the savepoint is released if fc succeeded,
+// otherwise the transaction is rolled back to the savepoint, the error of fc is returned
+//
+//	db.Transaction(func(tx *gorm.DB) error {
+//		tx.Create(&order)
+//		if err := tx.WithSavePoint(func(tx *gorm.DB) error { return tx.Create(&coupon).Error }); err != nil {
+//			// order is kept without coupon
+//		}
+//		return nil
+//	})
+func (db *DB) WithSavePoint(fc func(tx *DB) error) (err error) {
+	if committer, ok := db.Statement.ConnPool.(TxCommitter); !ok || committer == nil {
+		return ErrInvalidTransaction
+	}
+
+	panicked := true
+	// named after the depth, savepoints at the same depth are released or rolled back before the next one is created
+	name := fmt.Sprintf("sp_%d", db.SavePointDepth()+1)
+	if err = db.SavePoint(name).Error; err != nil {
+		return
+	}
+
+	defer func() {
+		// Make sure to rollback when panic, Block error or release error, the savepoint is released after rolled back
+		if panicked || err != nil {
+			if db.RollbackTo(name).Error == nil {
+				db.ReleaseSavePoint(name)
+			}
+		}
+	}()
+
+	if err = fc(db.Session(&Session{NewDB: db.clone == 1})); err == nil {
+		err = db.ReleaseSavePoint(name).Error
+	}
+	panicked = false
+	return
+}
+
+// SavePointDepth returns the count of savepoints in the current transaction, for diagnostics
+func (db *DB) SavePointDepth() int {
+	if state := db.txState(); state != nil {
+		return state.depth()
+	}
+	return 0
+}
+
 //// Exec executes raw sql
 //func (db *DB) Exec(sql string, values ...interface{}) (tx *DB) {
 //	tx = db.getInstance()
@@ -61,6 +61,10 @@ type Config struct {
 	IgnoreRelationshipsWhenMigrating bool
 	// DisableNestedTransaction disable nested transaction
 	DisableNestedTransaction bool
+	// TransactionRetry retry Transaction on deadlocks and serialization failures with the policy
+	TransactionRetry *RetryPolicy
+	// TxOptions default options of transactions, translated by the dialector
+	TxOptions *TxOptions
 	// AllowGlobalUpdate allow global update
 	AllowGlobalUpdate bool
 	// QueryFields executes the SQL query with all fields of the table
@@ -139,6 +143,7 @@ type Session struct {
 	Logger                   logger.Interface
 	NowFunc                  func() time.Time
 	CreateBatchSize          int
+	TxOptions                *TxOptions
 }
 
 // Open initialize db session based on dialector
@@ -230,6 +235,10 @@ func Open(dialector Dialector, dbType DBType, opts ...Option) (db *DB, err error
 		Clauses:  map[string]clause.Clause{},
 	}
 
+	if err == nil && config.TxOptions != nil {
+		_, err = db.txBeginPlan(*config.TxOptions)
+	}
+
 	if err == nil && !config.DisableAutomaticPing {
 		if pinger, ok := db.ConnPool.(interface{ Ping() error }); ok {
 			err = pinger.Ping()
@@ -263,6 +272,14 @@ func (db *DB) Session(config *Session) *DB {
 		tx.Config.SkipDefaultTransaction = true
 	}
 
+	if config.TxOptions != nil {
+		tx.Config.TxOptions = config.TxOptions
+		// validate the options up front instead of failing when beginning transactions
+		if _, err := tx.txBeginPlan(*config.TxOptions); err != nil {
+			tx.AddError(err)
+		}
+	}
+
 	if config.AllowGlobalUpdate {
 		txConfig.AllowGlobalUpdate = true
 	}
@@ -294,17 +311,20 @@ func (db *DB) Session(config *Session) *DB {
 			db.cacheStore.Store(preparedStmtDBKey, preparedStmt)
 		}
 
-		switch t := tx.Statement.ConnPool.(type) {
-		case Tx:
-			tx.Statement.ConnPool = &PreparedStmtTX{
-				Tx:             t,
-				PreparedStmtDB: preparedStmt,
+		if t, ok := tx.Statement.ConnPool.(Tx); ok {
+			// transactions wrapped by plugins might execute with prepared statements already
+			if _, prepared := unwrapPreparedStmtTX(t); !prepared {
+				tx.Statement.ConnPool = &PreparedStmtTX{
+					Tx:             t,
+					PreparedStmtDB: preparedStmt,
+				}
 			}
-		default:
+		} else {
 			tx.Statement.ConnPool = &PreparedStmtDB{
 				ConnPool: db.Config.ConnPool,
 				Mux:      preparedStmt.Mux,
 				Stmts:    preparedStmt.Stmts,
+				stats:    preparedStmt.stats,
 			}
 		}
 		txConfig.ConnPool = tx.Statement.ConnPool
@@ -443,6 +463,8 @@ func (db *DB) getInstance() *DB {
 				Vars:      make([]interface{}, 0, 8),
 				SkipHooks: db.Statement.SkipHooks,
 			}
+			// sessions of a transaction share its state
+			tx.Statement.transaction = db.Statement.transaction
 			if db.Config.PropagateUnscoped {
 				tx.Statement.Unscoped = db.Statement.Unscoped
 			}
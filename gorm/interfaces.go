@@ -42,6 +42,7 @@ type ConnPool interface {
 type SavePointerDialectorInterface interface {
 	SavePoint(tx *DB, name string) error
 	RollbackTo(tx *DB, name string) error
+	ReleaseSavePoint(tx *DB, name string) error
 }
 
 // TxBeginner tx beginner
@@ -54,6 +55,11 @@ type ConnPoolBeginner interface {
 	BeginTx(ctx context.Context, opts *sql.TxOptions) (ConnPool, error)
 }
 
+// ConnPoolUnwrapper conn pool wrapping another conn pool, e.g. by plugins, Unwrap returns the wrapped conn pool
+type ConnPoolUnwrapper interface {
+	Unwrap() ConnPool
+}
+
 // TxCommitter tx committer
 type TxCommitter interface {
 	Commit() error
@@ -90,3 +96,33 @@ type Rows interface {
 type ErrorTranslator interface {
 	Translate(err error) error
 }
+
+// RetryableErrorTranslator classifies errors that transactions could be retried from scratch, e.g. deadlocks
+type RetryableErrorTranslator interface {
+	Retryable(err error) bool
+}
+
+// AfterScanRowInterface called after each row scanned into the model
+type AfterScanRowInterface interface {
+	AfterScanRow(*DB) error
+}
+
+// BeforeAssociationAppendInterface called on the owner before appending associations
+type BeforeAssociationAppendInterface interface {
+	BeforeAssociationAppend(tx *DB, association string, values []interface{}) error
+}
+
+// AfterAssociationAppendInterface called on the owner after appending associations
+type AfterAssociationAppendInterface interface {
+	AfterAssociationAppend(tx *DB, association string, values []interface{}) error
+}
+
+// BeforeAssociationDeleteInterface called on the owner before deleting associations
+type BeforeAssociationDeleteInterface interface {
+	BeforeAssociationDelete(tx *DB, association string, values []interface{}) error
+}
+
+// AfterAssociationDeleteInterface called on the owner after deleting associations
+type AfterAssociationDeleteInterface interface {
+	AfterAssociationDelete(tx *DB, association string, values []interface{}) error
+}
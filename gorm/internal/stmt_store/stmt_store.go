@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/fangxing98/jx-gorm/gorm/internal/lru"
@@ -67,6 +68,13 @@ type Store interface {
 	// Parameters:
 	//   key: The key associated with the Stmt object to be deleted.
 	Delete(key string)
+
+	// Len returns the count of Stmt objects in the store.
+	Len() int
+
+	// Evictions returns the count of Stmt objects evicted because of the capacity or the TTL of the store,
+	// Stmt objects removed by Delete are not counted.
+	Evictions() uint64
 }
 
 // defaultMaxSize defines the default maximum capacity of the cache.
@@ -104,16 +112,22 @@ func New(size int, ttl time.Duration) Store {
 		ttl = defaultTTL
 	}
 
+	store := &lruStore{}
 	onEvicted := func(k string, v *Stmt) {
+		store.removed.Add(1)
 		if v != nil {
 			go v.Close()
 		}
 	}
-	return &lruStore{lru: lru.NewLRU[string, *Stmt](size, onEvicted, ttl)}
+	store.lru = lru.NewLRU[string, *Stmt](size, onEvicted, ttl)
+	return store
 }
 
 type lruStore struct {
 	lru *lru.LRU[string, *Stmt]
+	// removed counts all removed Stmt objects, deleted counts Stmt objects removed by Delete
+	removed atomic.Uint64
+	deleted atomic.Uint64
 }
 
 func (s *lruStore) Keys() []string {
@@ -133,7 +147,21 @@ func (s *lruStore) Set(key string, value *Stmt) {
 }
 
 func (s *lruStore) Delete(key string) {
-	s.lru.Remove(key)
+	if s.lru.Remove(key) {
+		s.deleted.Add(1)
+	}
+}
+
+func (s *lruStore) Len() int {
+	return s.lru.Len()
+}
+
+func (s *lruStore) Evictions() uint64 {
+	removed, deleted := s.removed.Load(), s.deleted.Load()
+	if removed < deleted {
+		return 0
+	}
+	return removed - deleted
 }
 
 type ConnPool interface {
@@ -0,0 +1,30 @@
+package testdb
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/driver/sqlite"
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+// Open opens a sqlite database in the temp dir of the test and migrates the models,
+// the default config is used if config is nil
+func Open(t testing.TB, config *gorm.Config, models ...interface{}) *gorm.DB {
+	t.Helper()
+	if config == nil {
+		config = &gorm.Config{}
+	}
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "gorm.db")), gorm.DBTypeSqlite, config)
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+
+	if len(models) > 0 {
+		if err := db.AutoMigrate(models...); err != nil {
+			t.Fatalf("failed to migrate, got error %v", err)
+		}
+	}
+	return db
+}
@@ -0,0 +1,92 @@
+package gorm_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/clause"
+	"github.com/fangxing98/jx-gorm/gorm/internal/testdb"
+)
+
+type JoinCompany struct {
+	ID   uint
+	Name string
+}
+
+type JoinUser struct {
+	ID        uint
+	Name      string
+	ManagerID *uint
+	Manager   *JoinUser
+	CompanyID *uint
+	Company   *JoinCompany
+	PartnerID *uint
+	Partner   *JoinCompany
+}
+
+func TestNestedJoins(t *testing.T) {
+	db := testdb.Open(t, nil, &JoinCompany{}, &JoinUser{})
+
+	boss := JoinUser{Name: "boss", Company: &JoinCompany{Name: "holding"}}
+	db.Create(&boss)
+	manager := JoinUser{Name: "manager", Manager: &boss, Company: &JoinCompany{Name: "acme"}}
+	db.Create(&manager)
+	user := JoinUser{Name: "user", Manager: &manager, Partner: &JoinCompany{Name: "partner"}}
+	db.Create(&user)
+
+	var result JoinUser
+	if err := db.Joins("Manager.Manager.Company").Joins("Company").Joins("Partner").First(&result, user.ID).Error; err != nil {
+		t.Fatalf("failed to find with nested joins, got error %v", err)
+	}
+
+	if result.Manager == nil || result.Manager.Name != "manager" || result.Manager.Manager == nil || result.Manager.Manager.Name != "boss" ||
+		result.Manager.Manager.Company == nil || result.Manager.Manager.Company.Name != "holding" {
+		t.Errorf("nested relations should be joined, got %+v", result)
+	}
+
+	if result.Company != nil || result.Partner == nil || result.Partner.Name != "partner" {
+		t.Errorf("missing relation should be nil and relations to the same table should be aliased, got %+v, %+v", result.Company, result.Partner)
+	}
+
+	result = JoinUser{}
+	if err := db.Joins("Manager.Manager").First(&result, boss.ID).Error; err != nil {
+		t.Fatalf("failed to find with nested joins, got error %v", err)
+	}
+
+	if result.Manager != nil {
+		t.Errorf("NULL rows of LEFT JOIN should be scanned into nil pointers, got %+v", result.Manager)
+	}
+
+	var users []JoinUser
+	if err := db.Joins("Manager.Company").Where(clause.Eq{Column: clause.JoinedColumn("Manager.Company", "name"), Value: "acme"}).Find(&users).Error; err != nil {
+		t.Fatalf("failed to find with joined column, got error %v", err)
+	}
+
+	if len(users) != 1 || users[0].ID != user.ID || users[0].Manager.Company.Name != "acme" {
+		t.Errorf("should find users by joined column, got %+v", users)
+	}
+
+	users = nil
+	if err := db.Joins("Manager.Company").Where(&JoinUser{Manager: &JoinUser{Company: &JoinCompany{Name: "holding"}}}).Find(&users).Error; err != nil {
+		t.Fatalf("failed to find with relations in struct conditions, got error %v", err)
+	}
+
+	if len(users) != 3 {
+		t.Errorf("relations in struct conditions should be ignored, got %+v", users)
+	}
+
+	users = nil
+	if err := db.Joins("Manager.Company", db.Where(&JoinCompany{Name: "acme"})).Order("join_users.id").Find(&users).Error; err != nil {
+		t.Fatalf("failed to find with join conditions, got error %v", err)
+	}
+
+	if len(users) != 3 || users[1].Manager == nil || users[1].Manager.Company != nil || users[2].Manager == nil || users[2].Manager.Company == nil {
+		t.Errorf("join conditions should only apply to the last nested relation, got %+v", users)
+	}
+
+	stmt := db.Session(&gorm.Session{DryRun: true}).Joins("Manager.Manager").Find(&users).Statement
+	if sql := stmt.SQL.String(); !strings.Contains(sql, "LEFT JOIN `join_users` `Manager__Manager` ON `Manager`.`manager_id` = `Manager__Manager`.`id`") {
+		t.Errorf("nested join aliases should be deterministic, got %v", sql)
+	}
+}
@@ -0,0 +1,58 @@
+package gorm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/gorm/clause"
+)
+
+// LockTimeoutSettingKey setting key of the lock wait timeout of the statement, set by LockForUpdate
+const LockTimeoutSettingKey = "gorm:lock_timeout"
+
+// LockOptions options of LockForUpdate
+type LockOptions struct {
+	// Of locks rows of the table only, e.g. queries with joins
+	Of string
+	// NoWait fails with ErrLockNotAvailable instead of waiting if rows are locked
+	NoWait bool
+	// SkipLocked skips locked rows
+	SkipLocked bool
+	// Timeout lock wait timeout of the statement, fails with ErrLockNotAvailable after the timeout
+	Timeout time.Duration
+}
+
+// LockTimeoutDialectorInterface returns the statements setting the lock wait timeout before
+// the statement and resetting it after the statement
+type LockTimeoutDialectorInterface interface {
+	LockTimeout(timeout time.Duration) (set string, reset string)
+}
+
+// LockForUpdate locks the selected rows for update with the syntax of the dialect, FOR UPDATE on MySQL and PostgreSQL,
+// the UPDLOCK table hint on SQL Server, SQLite doesn't support row locks and the clause is ignored
+//
+//	db.Transaction(func(tx *gorm.DB) error {
+//		return tx.LockForUpdate(gorm.LockOptions{SkipLocked: true}).Where("status = ?", "pending").Limit(10).Find(&jobs).Error
+//	})
+func (db *DB) LockForUpdate(opts ...LockOptions) (tx *DB) {
+	var opt LockOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	locking := clause.Locking{Strength: clause.LockingStrengthUpdate, Table: clause.Table{Name: opt.Of}}
+	if opt.NoWait {
+		locking.Options = clause.LockingOptionsNoWait
+	} else if opt.SkipLocked {
+		locking.Options = clause.LockingOptionsSkipLocked
+	}
+
+	tx = db.Clauses(locking)
+	if opt.NoWait && opt.SkipLocked {
+		tx.AddError(fmt.Errorf("%w: NoWait and SkipLocked can't be used together", ErrInvalidData))
+	}
+	if opt.Timeout > 0 {
+		tx.Statement.Settings.Store(LockTimeoutSettingKey, opt.Timeout)
+	}
+	return tx
+}
@@ -16,7 +16,8 @@ func (db *DB) Migrator() Migrator {
 		tx = tx.executeScopes()
 	}
 
-	return tx.Dialector.Migrator(tx.Session(&Session{}))
+	// migrations run without the prepared statement cache, cached statements are invalidated after migration statements executed as tables might be changed
+	return tx.Dialector.Migrator(tx.Session(&Session{Context: withoutPrepare(tx.Statement.Context, noPrepareOption{invalidate: true})}))
 }
 
 // AutoMigrate run auto migration for given models
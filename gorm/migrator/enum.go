@@ -0,0 +1,32 @@
+package migrator
+
+import (
+	"regexp"
+	"strings"
+)
+
+// regEnumValue matches quoted values of enum definitions, e.g. enum('a','b'), CHECK ([status]='a' OR [status]='b')
+var regEnumValue = regexp.MustCompile(`'((?:[^']|'')*)'`)
+
+// ParseEnumValues parse quoted values from enum type or check constraint definition
+func ParseEnumValues(definition string) (values []string) {
+	for _, matches := range regEnumValue.FindAllStringSubmatch(definition, -1) {
+		values = append(values, strings.ReplaceAll(matches[1], "''", "'"))
+	}
+	return
+}
+
+// MissingEnumValues returns values that don't exist in the current enum values
+func MissingEnumValues(current, values []string) (missing []string) {
+	exists := make(map[string]bool, len(current))
+	for _, value := range current {
+		exists[value] = true
+	}
+
+	for _, value := range values {
+		if !exists[value] {
+			missing = append(missing, value)
+		}
+	}
+	return
+}
@@ -0,0 +1,38 @@
+package migrator_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/gorm/migrator"
+)
+
+func TestParseEnumValues(t *testing.T) {
+	params := []struct {
+		definition string
+		expect     []string
+	}{
+		{"enum('pending','paid')", []string{"pending", "paid"}},
+		{"CHECK (`status` IN ('pending','it''s shipped'))", []string{"pending", "it's shipped"}},
+		{"([status]=N'paid' OR [status]=N'pending')", []string{"paid", "pending"}},
+		{"enum('')", []string{""}},
+		{"varchar(100)", nil},
+	}
+
+	for _, p := range params {
+		if values := migrator.ParseEnumValues(p.definition); !reflect.DeepEqual(values, p.expect) {
+			t.Errorf("parse enum values of %q, expects %#v, got %#v", p.definition, p.expect, values)
+		}
+	}
+}
+
+func TestMissingEnumValues(t *testing.T) {
+	missing := migrator.MissingEnumValues([]string{"pending", "paid"}, []string{"pending", "paid", "shipped"})
+	if !reflect.DeepEqual(missing, []string{"shipped"}) {
+		t.Errorf("should return new enum values, got %#v", missing)
+	}
+
+	if missing := migrator.MissingEnumValues([]string{"pending", "paid"}, []string{"paid"}); len(missing) != 0 {
+		t.Errorf("removed enum values should be ignored, got %#v", missing)
+	}
+}
@@ -57,6 +57,11 @@ type GormDataTypeInterface interface {
 	GormDBDataType(*gorm.DB, *schema.Field) string
 }
 
+// GormEnumDataTypeInterface gorm enum data type interface, returns the allowed values of the enum
+type GormEnumDataTypeInterface interface {
+	GormEnumValues() []string
+}
+
 // RunWithValue run migration with statement value
 func (m Migrator) RunWithValue(value interface{}, fc func(*gorm.Statement) error) error {
 	stmt := &gorm.Statement{DB: m.DB}
@@ -86,6 +91,14 @@ func (m Migrator) DataTypeOf(field *schema.Field) string {
 	return m.Dialector.DataTypeOf(field)
 }
 
+// EnumValuesOf return field's allowed enum values, ok is false if field isn't an enum
+func (m Migrator) EnumValuesOf(field *schema.Field) (values []string, ok bool) {
+	if enum, ok := reflect.New(field.IndirectFieldType).Interface().(GormEnumDataTypeInterface); ok {
+		return enum.GormEnumValues(), true
+	}
+	return nil, false
+}
+
 // FullDataTypeOf returns field's db full data type
 func (m Migrator) FullDataTypeOf(field *schema.Field) (expr clause.Expr) {
 	expr.SQL = m.DataTypeOf(field)
@@ -233,9 +246,9 @@ func (m Migrator) CreateTable(values ...interface{}) error {
 					createTableSQL += "? ?"
 					hasPrimaryKeyInDataType = hasPrimaryKeyInDataType || strings.Contains(strings.ToUpper(m.DataTypeOf(field)), "PRIMARY KEY")
 
-					// 建表时，兼容类型
+					// 建表时，兼容类型，enum 字段使用数据库原生枚举类型
 					expr := m.DB.Migrator().FullDataTypeOf(field)
-					if m.DB.IsPgDriver() {
+					if _, isEnum := m.EnumValuesOf(field); m.DB.IsPgDriver() && !isEnum {
 						expr.SQL = gorm.PgDBTypeMap(stmt.Table, field)
 					}
 
@@ -529,6 +542,15 @@ func (m Migrator) MigrateColumn(value interface{}, field *schema.Field, columnTy
 		}
 	}
 
+	// check enum values, e.g. enum('a','b') of mysql
+	if values, ok := m.EnumValuesOf(field); ok {
+		if columnType, ok := columnType.ColumnType(); ok && strings.HasPrefix(strings.ToLower(columnType), "enum(") {
+			if len(MissingEnumValues(ParseEnumValues(columnType), values)) > 0 {
+				alterColumn = true
+			}
+		}
+	}
+
 	// check nullable
 	if nullable, ok := columnType.Nullable(); ok && nullable == field.NotNull {
 		// not primary key & current database is non-nullable(to be nullable)
@@ -835,7 +857,8 @@ func (m Migrator) CreateIndex(value interface{}, name string) error {
 			}
 			createIndexSQL += "INDEX ? ON ??"
 
-			if idx.Type != "" {
+			// spatial indexes have no index types, e.g. R-tree of mysql
+			if idx.Type != "" && idx.Class != "SPATIAL" {
 				createIndexSQL += " USING " + idx.Type
 			}
 
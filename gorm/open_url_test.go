@@ -0,0 +1,64 @@
+package gorm_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/driver/sqlite"
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/logger"
+)
+
+func TestOpenURL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "open_url.db")
+	db, err := gorm.OpenURL("sqlite://"+path+"?max_open_conns=3&conn_max_lifetime=1h&prepare_stmt=true&translate_error=true&log_level=silent", &gorm.Config{CreateBatchSize: 10})
+	if err != nil {
+		t.Fatalf("failed to open url, got error %v", err)
+	}
+
+	if db.DBType != gorm.DBTypeSqlite || db.Dialector.Name() != "sqlite" {
+		t.Errorf("dialector should be sqlite, got %v %v", db.DBType, db.Dialector.Name())
+	}
+	if dsn := db.Dialector.(*sqlite.Dialector).DSN; dsn != path {
+		t.Errorf("url parameters should be removed from dsn, got %v", dsn)
+	}
+	if !db.PrepareStmt || !db.TranslateError || db.CreateBatchSize != 10 {
+		t.Errorf("config should be set by url parameters and options, got %+v", db.Config)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB, got error %v", err)
+	}
+	if stats := sqlDB.Stats(); stats.MaxOpenConnections != 3 {
+		t.Errorf("max open conns should be 3, got %v", stats.MaxOpenConnections)
+	}
+	if err := sqlDB.Ping(); err != nil {
+		t.Errorf("failed to ping, got error %v", err)
+	}
+
+	db, err = gorm.OpenURL("file:"+path+"?_busy_timeout=5000&log_level=info&cache=shared", &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open file url, got error %v", err)
+	}
+	if dsn := db.Dialector.(*sqlite.Dialector).DSN; dsn != "file:"+path+"?_busy_timeout=5000&cache=shared" {
+		t.Errorf("driver parameters should be kept in dsn, got %v", dsn)
+	}
+}
+
+func TestOpenURLFailed(t *testing.T) {
+	if _, err := gorm.OpenURL("oracle://localhost/gorm"); !errors.Is(err, gorm.ErrUnsupportedDriver) {
+		t.Errorf("unregistered scheme should fail, got error %v", err)
+	}
+	if _, err := gorm.OpenURL("gorm.db"); !errors.Is(err, gorm.ErrUnsupportedDriver) {
+		t.Errorf("url without scheme should fail, got error %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "open_url.db")
+	for _, query := range []string{"max_open_conns=many", "conn_max_lifetime=1", "prepare_stmt=yes", "log_level=debug"} {
+		if _, err := gorm.OpenURL("sqlite://" + path + "?" + query); !errors.Is(err, gorm.ErrInvalidData) {
+			t.Errorf("invalid parameter %v should fail, got error %v", query, err)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+// Status delivery status of outbox messages
+type Status string
+
+const (
+	// StatusPending the message is waiting to be published
+	StatusPending Status = "pending"
+	// StatusDelivered the message has been published
+	StatusDelivered Status = "delivered"
+	// StatusDead the message failed to be published after Config.MaxAttempts attempts
+	StatusDead Status = "dead"
+)
+
+// Message outbox message, messages with the same AggregateKey are published in order,
+// messages without AggregateKey are published in any order
+type Message struct {
+	ID           uint64 `gorm:"primaryKey"`
+	Topic        string `gorm:"size:191"`
+	AggregateKey string `gorm:"size:191;index:idx_outbox_status,priority:2"`
+	Payload      []byte
+	Status       Status `gorm:"size:16;index:idx_outbox_status,priority:1"`
+	Attempts     int
+	LastError    string
+	AvailableAt  time.Time
+	CreatedAt    time.Time
+	DeliveredAt  *time.Time
+}
+
+// TableName the outbox table
+func (Message) TableName() string {
+	return "outbox"
+}
+
+// Migrate creates or migrates the outbox table
+func Migrate(db *gorm.DB) error {
+	return db.AutoMigrate(&Message{})
+}
+
+// Enqueue inserts messages into the outbox, call it with the transaction of the business write
+// so the messages are committed or rolled back together with it
+//
+//	db.Transaction(func(tx *gorm.DB) error {
+//		if err := tx.Create(&order).Error; err != nil {
+//			return err
+//		}
+//		return outbox.Enqueue(tx, &outbox.Message{Topic: "order.created", AggregateKey: order.Number, Payload: payload})
+//	})
+func Enqueue(db *gorm.DB, messages ...*Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	now := db.NowFunc()
+	for _, message := range messages {
+		message.Status = StatusPending
+		if message.AvailableAt.IsZero() {
+			message.AvailableAt = now
+		}
+	}
+	return db.Create(messages).Error
+}
+
+// Requeue moves dead-lettered messages back to pending with attempts reset
+func Requeue(db *gorm.DB, ids ...uint64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return db.Model(&Message{}).Where("id IN ? AND status = ?", ids, StatusDead).Updates(map[string]interface{}{
+		"status":       StatusPending,
+		"attempts":     0,
+		"last_error":   "",
+		"available_at": db.NowFunc(),
+	}).Error
+}
@@ -0,0 +1,180 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/internal/testdb"
+	"github.com/fangxing98/jx-gorm/gorm/outbox"
+)
+
+type Order struct {
+	ID     uint
+	Number string
+}
+
+func openDB(t *testing.T) *gorm.DB {
+	db := testdb.Open(t, nil)
+
+	if err := outbox.Migrate(db); err != nil {
+		t.Fatalf("failed to migrate outbox, got error %v", err)
+	}
+	if err := db.AutoMigrate(&Order{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+	return db
+}
+
+func payloads(messages []outbox.Message) (results []string) {
+	for _, message := range messages {
+		results = append(results, string(message.Payload))
+	}
+	return
+}
+
+func drain(t *testing.T, relay *outbox.Relay) {
+	for i := 0; i < 10; i++ {
+		count, err := relay.RunOnce(context.Background())
+		if err != nil {
+			t.Fatalf("failed to relay messages, got error %v", err)
+		}
+		if count == 0 {
+			return
+		}
+	}
+	t.Fatalf("outbox should be drained")
+}
+
+func TestEnqueue(t *testing.T) {
+	db := openDB(t)
+
+	db.Transaction(func(tx *gorm.DB) error {
+		order := Order{Number: "1"}
+		if err := tx.Create(&order).Error; err != nil {
+			return err
+		}
+		return outbox.Enqueue(tx, &outbox.Message{Topic: "order.created", AggregateKey: order.Number, Payload: []byte("1")})
+	})
+
+	db.Transaction(func(tx *gorm.DB) error {
+		tx.Create(&Order{Number: "2"})
+		outbox.Enqueue(tx, &outbox.Message{Topic: "order.created", AggregateKey: "2", Payload: []byte("2")})
+		return errors.New("rollback")
+	})
+
+	var messages []outbox.Message
+	db.Find(&messages)
+	if len(messages) != 1 || string(messages[0].Payload) != "1" || messages[0].Status != outbox.StatusPending {
+		t.Errorf("messages should be enqueued with the business write, got %+v", messages)
+	}
+}
+
+func TestRelayOrderingPerAggregate(t *testing.T) {
+	db := openDB(t)
+
+	outbox.Enqueue(db,
+		&outbox.Message{AggregateKey: "a", Payload: []byte("a1")},
+		&outbox.Message{AggregateKey: "b", Payload: []byte("b1")},
+		&outbox.Message{AggregateKey: "a", Payload: []byte("a2")},
+		&outbox.Message{Payload: []byte("x")},
+		&outbox.Message{Payload: []byte("y")},
+		&outbox.Message{AggregateKey: "a", Payload: []byte("a3")},
+	)
+
+	publisher := outbox.NewMemoryPublisher()
+	relay := outbox.NewRelay(db, outbox.Config{Publisher: publisher})
+
+	count, err := relay.RunOnce(context.Background())
+	if err != nil || count != 4 {
+		t.Fatalf("only the oldest message of aggregates should be polled, got count %v, error %v", count, err)
+	}
+	drain(t, relay)
+
+	if got := payloads(publisher.Messages()); !reflect.DeepEqual(got, []string{"a1", "b1", "x", "y", "a2", "a3"}) {
+		t.Errorf("messages should be published in order of aggregate, got %v", got)
+	}
+
+	var pending int64
+	db.Model(&outbox.Message{}).Where("status <> ?", outbox.StatusDelivered).Count(&pending)
+	if pending != 0 {
+		t.Errorf("messages should be marked as delivered, got %v pending", pending)
+	}
+}
+
+func TestRelayRetryAndDeadLetter(t *testing.T) {
+	db := openDB(t)
+
+	outbox.Enqueue(db,
+		&outbox.Message{AggregateKey: "a", Payload: []byte("poison")},
+		&outbox.Message{AggregateKey: "a", Payload: []byte("a2")},
+	)
+
+	errPoison := errors.New("poison message")
+	publisher := outbox.NewMemoryPublisher()
+	publisher.Err = func(messages []outbox.Message) error {
+		for _, message := range messages {
+			if string(message.Payload) == "poison" {
+				return errPoison
+			}
+		}
+		return nil
+	}
+	relay := outbox.NewRelay(db, outbox.Config{Publisher: publisher, MaxAttempts: 3, RetryDelay: time.Nanosecond})
+
+	for i := 0; i < 3; i++ {
+		if _, err := relay.RunOnce(context.Background()); !errors.Is(err, errPoison) {
+			t.Fatalf("should return publish error, got %v", err)
+		}
+		if len(publisher.Messages()) != 0 {
+			t.Fatalf("later messages of the aggregate should wait for the failed message")
+		}
+	}
+
+	var dead outbox.Message
+	db.First(&dead, "status = ?", outbox.StatusDead)
+	if string(dead.Payload) != "poison" || dead.Attempts != 3 || dead.LastError != errPoison.Error() {
+		t.Errorf("message should be dead-lettered after max attempts, got %+v", dead)
+	}
+
+	drain(t, relay)
+	if got := payloads(publisher.Messages()); !reflect.DeepEqual(got, []string{"a2"}) {
+		t.Errorf("messages after dead letter should be published, got %v", got)
+	}
+
+	publisher.Err = nil
+	if err := outbox.Requeue(db, dead.ID); err != nil {
+		t.Fatalf("failed to requeue, got error %v", err)
+	}
+	drain(t, relay)
+	if got := payloads(publisher.Messages()); !reflect.DeepEqual(got, []string{"a2", "poison"}) {
+		t.Errorf("requeued message should be published, got %v", got)
+	}
+}
+
+func TestRelayRun(t *testing.T) {
+	db := openDB(t)
+	outbox.Enqueue(db, &outbox.Message{Payload: []byte("1")})
+
+	publisher := outbox.NewMemoryPublisher()
+	relay := outbox.NewRelay(db, outbox.Config{Publisher: publisher, PollInterval: time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- relay.Run(ctx) }()
+
+	for i := 0; i < 1000 && len(publisher.Messages()) == 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("relay should stop after context canceled, got %v", err)
+	}
+	if got := payloads(publisher.Messages()); !reflect.DeepEqual(got, []string{"1"}) {
+		t.Errorf("messages should be published by relay, got %v", got)
+	}
+}
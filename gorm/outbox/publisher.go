@@ -0,0 +1,55 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+)
+
+// Publisher publishes outbox messages to the message broker, messages of a batch are considered
+// failed if an error returned, they will be published again, so consumers should be idempotent
+type Publisher interface {
+	Publish(ctx context.Context, messages []Message) error
+}
+
+// PublisherFunc adapts a function to Publisher
+type PublisherFunc func(ctx context.Context, messages []Message) error
+
+// Publish implements Publisher
+func (fc PublisherFunc) Publish(ctx context.Context, messages []Message) error {
+	return fc(ctx, messages)
+}
+
+// MemoryPublisher keeps published messages in memory, used for tests
+type MemoryPublisher struct {
+	// Err returns the error of publishing messages if not nil
+	Err func(messages []Message) error
+
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemoryPublisher returns a MemoryPublisher
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+// Publish implements Publisher
+func (p *MemoryPublisher) Publish(_ context.Context, messages []Message) error {
+	if p.Err != nil {
+		if err := p.Err(messages); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.messages = append(p.messages, messages...)
+	return nil
+}
+
+// Messages returns the published messages
+func (p *MemoryPublisher) Messages() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Message(nil), p.messages...)
+}
@@ -0,0 +1,168 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/clause"
+)
+
+const (
+	// DefaultBatchSize default max messages published in a batch
+	DefaultBatchSize = 100
+	// DefaultMaxAttempts default attempts before a message is dead-lettered
+	DefaultMaxAttempts = 10
+)
+
+// Config relay config
+type Config struct {
+	// Publisher publishes the messages, required
+	Publisher Publisher
+	// BatchSize max messages published in a batch, defaults to DefaultBatchSize
+	BatchSize int
+	// PollInterval interval to poll messages when the outbox is drained, defaults to 1s
+	PollInterval time.Duration
+	// MaxAttempts messages are dead-lettered after failed MaxAttempts times, defaults to DefaultMaxAttempts
+	MaxAttempts int
+	// RetryDelay delay before retrying a failed message, doubled after every attempt, defaults to 1s
+	RetryDelay time.Duration
+	// DisableSkipLocked polls with FOR UPDATE without SKIP LOCKED, e.g. MySQL before 8.0
+	DisableSkipLocked bool
+}
+
+// Relay polls pending messages from the outbox and hands them to the Publisher, messages are marked as
+// delivered after published, so they are delivered at least once. Relays could run concurrently, messages
+// are claimed with SELECT ... FOR UPDATE SKIP LOCKED on MySQL 8 and PostgreSQL, READPAST on SQL Server,
+// and the database write lock on SQLite.
+//
+//	relay := outbox.NewRelay(db, outbox.Config{Publisher: publisher})
+//	go relay.Run(ctx)
+type Relay struct {
+	Config
+	db *gorm.DB
+}
+
+// NewRelay returns a relay publishing messages of db
+func NewRelay(db *gorm.DB, config Config) *Relay {
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = time.Second
+	}
+	return &Relay{Config: config, db: db}
+}
+
+// Run publishes messages until ctx is done
+func (r *Relay) Run(ctx context.Context) error {
+	for {
+		count, err := r.RunOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.db.Logger.Error(ctx, "outbox relay failed: %v", err)
+		}
+
+		if err != nil || count < r.BatchSize {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(r.PollInterval):
+			}
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// RunOnce publishes a batch of available messages, returns the count of the batch. Only the oldest pending
+// message of an aggregate is polled, so later messages wait until it is delivered or dead-lettered.
+// If the Publisher failed, attempts of the messages are recorded and the error is returned.
+func (r *Relay) RunOnce(ctx context.Context) (count int, err error) {
+	if r.Publisher == nil {
+		return 0, fmt.Errorf("outbox: publisher is required")
+	}
+
+	var publishErr error
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query, err := r.lock(tx)
+		if err != nil {
+			return err
+		}
+
+		now := tx.NowFunc()
+		heads := tx.Model(&Message{}).Select("MIN(id)").Where("status = ?", StatusPending).Group("aggregate_key")
+
+		var messages []Message
+		if err := query.Where("status = ? AND available_at <= ?", StatusPending, now).
+			Where(tx.Where("aggregate_key = ?", "").Or("id IN (?)", heads)).
+			Order("id").Limit(r.BatchSize).Find(&messages).Error; err != nil || len(messages) == 0 {
+			return err
+		}
+		count = len(messages)
+
+		if publishErr = r.Publisher.Publish(ctx, messages); publishErr != nil {
+			return r.fail(tx, messages, publishErr)
+		}
+
+		ids := make([]uint64, len(messages))
+		for idx, message := range messages {
+			ids[idx] = message.ID
+		}
+		return tx.Model(&Message{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":       StatusDelivered,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"last_error":   "",
+			"delivered_at": now,
+		}).Error
+	})
+
+	if err == nil {
+		err = publishErr
+	}
+	return count, err
+}
+
+// lock claims messages for the transaction, returns the query polling messages
+func (r *Relay) lock(tx *gorm.DB) (*gorm.DB, error) {
+	switch tx.Dialector.Name() {
+	case "mysql", "postgres", "sqlserver":
+		return tx.LockForUpdate(gorm.LockOptions{SkipLocked: !r.DisableSkipLocked}), nil
+	case "sqlite":
+		// SQLite doesn't support row locks, acquire the write lock of the database before polling
+		return tx, tx.Exec("UPDATE ? SET id = id WHERE 1 = 0", clause.Table{Name: Message{}.TableName()}).Error
+	}
+	return tx, nil
+}
+
+// fail records the failed attempt of messages, messages are dead-lettered after MaxAttempts
+func (r *Relay) fail(tx *gorm.DB, messages []Message, err error) error {
+	now := tx.NowFunc()
+	for _, message := range messages {
+		attempts := message.Attempts + 1
+		values := map[string]interface{}{"attempts": attempts, "last_error": err.Error()}
+		if attempts >= r.MaxAttempts {
+			values["status"] = StatusDead
+		} else {
+			values["available_at"] = now.Add(r.backoff(attempts))
+		}
+
+		if err := tx.Model(&Message{}).Where("id = ?", message.ID).Updates(values).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	if attempts > 16 {
+		attempts = 16
+	}
+	return r.RetryDelay << (attempts - 1)
+}
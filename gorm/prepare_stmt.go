@@ -7,8 +7,10 @@ import (
 	"errors"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/fangxing98/jx-gorm/gorm/clause"
 	"github.com/fangxing98/jx-gorm/gorm/internal/stmt_store"
 )
 
@@ -16,6 +18,101 @@ type PreparedStmtDB struct {
 	Stmts stmt_store.Store
 	Mux   *sync.RWMutex
 	ConnPool
+	stats *preparedStmtStats
+}
+
+// PreparedStmtStats statistics of the prepared statement cache
+type PreparedStmtStats struct {
+	// Size count of cached statements
+	Size int
+	// Hits count of statements found in the cache
+	Hits uint64
+	// Misses count of statements prepared because not found in the cache
+	Misses uint64
+	// Evictions count of statements evicted because of PrepareStmtMaxSize or PrepareStmtTTL
+	Evictions uint64
+}
+
+type preparedStmtStats struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// NoPrepare executes the statement without the prepared statement cache, e.g. statements with dynamic SQL
+//
+//	db.Clauses(gorm.NoPrepare{}).Where(dynamicConditions).Find(&users)
+type NoPrepare struct{}
+
+// ModifyStatement implements StatementModifier
+func (NoPrepare) ModifyStatement(stmt *Statement) {
+	stmt.Settings.Store(noPrepareSettingKey, true)
+}
+
+// Build implements clause.Expression
+func (NoPrepare) Build(clause.Builder) {}
+
+const noPrepareSettingKey = "gorm:no_prepare"
+
+type noPrepareCtxKey struct{}
+
+// noPrepareOption how statements are executed without the prepared statement cache
+type noPrepareOption struct {
+	// invalidate invalidates cached statements after the statement executed, e.g. statements altering tables
+	invalidate bool
+}
+
+// withoutPrepare returns a context executing statements without the prepared statement cache
+func withoutPrepare(ctx context.Context, opt noPrepareOption) context.Context {
+	return context.WithValue(ctx, noPrepareCtxKey{}, opt)
+}
+
+func noPrepareFrom(ctx context.Context) (noPrepareOption, bool) {
+	if ctx == nil {
+		return noPrepareOption{}, false
+	}
+	opt, ok := ctx.Value(noPrepareCtxKey{}).(noPrepareOption)
+	return opt, ok
+}
+
+// maxInValuesBucket IN lists longer than it are not padded, to avoid exceeding the limit of bind variables
+const maxInValuesBucket = 1024
+
+// PadInValues implements clause.InValuesPadder, IN lists executed with prepared statements are padded to
+// the next power of two with the last value, e.g. 3 values to 4, so IN lists of different lengths share statements
+func (stmt *Statement) PadInValues(values []interface{}) []interface{} {
+	size := 1
+	for size < len(values) {
+		size <<= 1
+	}
+	if len(values) == 0 || size == len(values) || size > maxInValuesBucket || !stmt.usePreparedStmt() {
+		return values
+	}
+
+	padded := make([]interface{}, size)
+	copy(padded, values)
+	for idx := len(values); idx < size; idx++ {
+		padded[idx] = values[len(values)-1]
+	}
+	return padded
+}
+
+// usePreparedStmt reports whether the statement is executed with prepared statements, conn pools wrapping
+// PreparedStmtDB or PreparedStmtTX, e.g. by plugins, are unwrapped
+func (stmt *Statement) usePreparedStmt() bool {
+	for connPool := stmt.ConnPool; connPool != nil; {
+		switch connPool.(type) {
+		case *PreparedStmtDB, *PreparedStmtTX:
+			_, skipped := noPrepareFrom(stmt.Context)
+			return !skipped
+		}
+
+		unwrapper, ok := connPool.(ConnPoolUnwrapper)
+		if !ok {
+			break
+		}
+		connPool = unwrapper.Unwrap()
+	}
+	return false
 }
 
 // NewPreparedStmtDB creates and initializes a new instance of PreparedStmtDB.
@@ -32,6 +129,7 @@ func NewPreparedStmtDB(connPool ConnPool, maxSize int, ttl time.Duration) *Prepa
 		ConnPool: connPool,                     // Assigns the provided connection pool to manage database connections.
 		Stmts:    stmt_store.New(maxSize, ttl), // Initializes a new statement store with the specified maximum size and TTL.
 		Mux:      &sync.RWMutex{},              // Sets up a read-write mutex for synchronizing access to the statement store.
+		stats:    &preparedStmtStats{},         // Counts hits and misses of the statement store, shared by sessions.
 	}
 }
 
@@ -63,11 +161,36 @@ func (db *PreparedStmtDB) Reset() {
 	db.Close()
 }
 
+// Stats returns statistics of the prepared statement cache
+func (db *PreparedStmtDB) Stats() PreparedStmtStats {
+	var stats PreparedStmtStats
+	if db.Stmts != nil {
+		stats.Size = db.Stmts.Len()
+		stats.Evictions = db.Stmts.Evictions()
+	}
+	if db.stats != nil {
+		stats.Hits = db.stats.hits.Load()
+		stats.Misses = db.stats.misses.Load()
+	}
+	return stats
+}
+
+func (db *PreparedStmtDB) hit(hit bool) {
+	if db.stats == nil {
+		return
+	} else if hit {
+		db.stats.hits.Add(1)
+	} else {
+		db.stats.misses.Add(1)
+	}
+}
+
 func (db *PreparedStmtDB) prepare(ctx context.Context, conn ConnPool, isTransaction bool, query string) (_ *stmt_store.Stmt, err error) {
 	db.Mux.RLock()
 	if db.Stmts != nil {
 		if stmt, ok := db.Stmts.Get(query); ok && (!stmt.Transaction || isTransaction) {
 			db.Mux.RUnlock()
+			db.hit(true)
 			return stmt, stmt.Error()
 		}
 	}
@@ -78,10 +201,12 @@ func (db *PreparedStmtDB) prepare(ctx context.Context, conn ConnPool, isTransact
 	if db.Stmts != nil {
 		if stmt, ok := db.Stmts.Get(query); ok && (!stmt.Transaction || isTransaction) {
 			db.Mux.Unlock()
+			db.hit(true)
 			return stmt, stmt.Error()
 		}
 	}
 
+	db.hit(false)
 	return db.Stmts.New(ctx, query, isTransaction, conn, db.Mux)
 }
 
@@ -107,6 +232,14 @@ func (db *PreparedStmtDB) BeginTx(ctx context.Context, opt *sql.TxOptions) (Conn
 }
 
 func (db *PreparedStmtDB) ExecContext(ctx context.Context, query string, args ...interface{}) (result sql.Result, err error) {
+	if opt, ok := noPrepareFrom(ctx); ok {
+		result, err = db.ConnPool.ExecContext(ctx, query, args...)
+		if err == nil && opt.invalidate {
+			db.Close()
+		}
+		return result, err
+	}
+
 	stmt, err := db.prepare(ctx, db.ConnPool, false, query)
 	if err == nil {
 		result, err = stmt.ExecContext(ctx, args...)
@@ -118,6 +251,10 @@ func (db *PreparedStmtDB) ExecContext(ctx context.Context, query string, args ..
 }
 
 func (db *PreparedStmtDB) QueryContext(ctx context.Context, query string, args ...interface{}) (rows *sql.Rows, err error) {
+	if _, ok := noPrepareFrom(ctx); ok {
+		return db.ConnPool.QueryContext(ctx, query, args...)
+	}
+
 	stmt, err := db.prepare(ctx, db.ConnPool, false, query)
 	if err == nil {
 		rows, err = stmt.QueryContext(ctx, args...)
@@ -129,6 +266,10 @@ func (db *PreparedStmtDB) QueryContext(ctx context.Context, query string, args .
 }
 
 func (db *PreparedStmtDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if _, ok := noPrepareFrom(ctx); ok {
+		return db.ConnPool.QueryRowContext(ctx, query, args...)
+	}
+
 	stmt, err := db.prepare(ctx, db.ConnPool, false, query)
 	if err == nil {
 		return stmt.QueryRowContext(ctx, args...)
@@ -149,6 +290,22 @@ type PreparedStmtTX struct {
 	PreparedStmtDB *PreparedStmtDB
 }
 
+// unwrapPreparedStmtTX returns the prepared statement transaction of connPool, conn pools wrapped by plugins are unwrapped
+func unwrapPreparedStmtTX(connPool ConnPool) (*PreparedStmtTX, bool) {
+	for connPool != nil {
+		if tx, ok := connPool.(*PreparedStmtTX); ok {
+			return tx, true
+		}
+
+		unwrapper, ok := connPool.(ConnPoolUnwrapper)
+		if !ok {
+			break
+		}
+		connPool = unwrapper.Unwrap()
+	}
+	return nil, false
+}
+
 func (db *PreparedStmtTX) GetDBConn() (*sql.DB, error) {
 	return db.PreparedStmtDB.GetDBConn()
 }
@@ -168,6 +325,14 @@ func (tx *PreparedStmtTX) Rollback() error {
 }
 
 func (tx *PreparedStmtTX) ExecContext(ctx context.Context, query string, args ...interface{}) (result sql.Result, err error) {
+	if opt, ok := noPrepareFrom(ctx); ok {
+		result, err = tx.Tx.ExecContext(ctx, query, args...)
+		if err == nil && opt.invalidate {
+			tx.PreparedStmtDB.Close()
+		}
+		return result, err
+	}
+
 	stmt, err := tx.PreparedStmtDB.prepare(ctx, tx.Tx, true, query)
 	if err == nil {
 		result, err = tx.Tx.StmtContext(ctx, stmt.Stmt).ExecContext(ctx, args...)
@@ -179,6 +344,10 @@ func (tx *PreparedStmtTX) ExecContext(ctx context.Context, query string, args ..
 }
 
 func (tx *PreparedStmtTX) QueryContext(ctx context.Context, query string, args ...interface{}) (rows *sql.Rows, err error) {
+	if _, ok := noPrepareFrom(ctx); ok {
+		return tx.Tx.QueryContext(ctx, query, args...)
+	}
+
 	stmt, err := tx.PreparedStmtDB.prepare(ctx, tx.Tx, true, query)
 	if err == nil {
 		rows, err = tx.Tx.StmtContext(ctx, stmt.Stmt).QueryContext(ctx, args...)
@@ -190,6 +359,10 @@ func (tx *PreparedStmtTX) QueryContext(ctx context.Context, query string, args .
 }
 
 func (tx *PreparedStmtTX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if _, ok := noPrepareFrom(ctx); ok {
+		return tx.Tx.QueryRowContext(ctx, query, args...)
+	}
+
 	stmt, err := tx.PreparedStmtDB.prepare(ctx, tx.Tx, true, query)
 	if err == nil {
 		return tx.Tx.StmtContext(ctx, stmt.Stmt).QueryRowContext(ctx, args...)
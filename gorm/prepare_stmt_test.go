@@ -0,0 +1,106 @@
+package gorm_test
+
+import (
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/internal/testdb"
+)
+
+type PreparedUser struct {
+	ID   uint
+	Name string
+}
+
+func openPreparedDB(t *testing.T, config *gorm.Config) (*gorm.DB, *gorm.PreparedStmtDB) {
+	config.PrepareStmt = true
+	db := testdb.Open(t, config, &PreparedUser{})
+
+	pdb, ok := db.ConnPool.(*gorm.PreparedStmtDB)
+	if !ok {
+		t.Fatalf("conn pool should be prepared statement db, got %T", db.ConnPool)
+	}
+	return db, pdb
+}
+
+func TestPreparedStmtStats(t *testing.T) {
+	db, pdb := openPreparedDB(t, &gorm.Config{})
+	if stats := pdb.Stats(); stats.Size != 0 {
+		t.Errorf("migration statements should not be cached, got %+v", stats)
+	}
+
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		db.Create(&PreparedUser{Name: name})
+	}
+	if stats := pdb.Stats(); stats.Size != 1 || stats.Misses != 1 || stats.Hits != 4 {
+		t.Errorf("insert statement should be cached once, got %+v", stats)
+	}
+
+	var users []PreparedUser
+	db.Where("id IN ?", []uint{1, 2, 3}).Find(&users)
+	if len(users) != 3 {
+		t.Errorf("padded IN list should find 3 users, got %v", len(users))
+	}
+	db.Where("id IN (?)", []uint{1, 2, 3, 4}).Find(&users)
+	db.Where("id IN ?", []uint{1, 2, 3, 4}).Find(&users)
+	db.Where(map[string]interface{}{"name": []string{"a", "b", "c"}}).Find(&users)
+	db.Where(map[string]interface{}{"name": []string{"a", "b", "c", "d"}}).Find(&users)
+	if len(users) != 4 {
+		t.Errorf("IN list should find 4 users, got %v", len(users))
+	}
+
+	if stats := pdb.Stats(); stats.Size != 3 || stats.Misses != 3 || stats.Hits != 7 {
+		t.Errorf("IN lists of 3 and 4 values should share statements, got %+v", stats)
+	}
+
+	db.Clauses(gorm.NoPrepare{}).Where("id IN ?", []uint{1, 2, 3, 4, 5}).Find(&users)
+	if len(users) != 5 {
+		t.Errorf("statement without prepare should find 5 users, got %v", len(users))
+	}
+	if stats := pdb.Stats(); stats.Size != 3 || stats.Misses != 3 || stats.Hits != 7 {
+		t.Errorf("statement without prepare should not be cached, got %+v", stats)
+	}
+
+	sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("id IN ?", []uint{1, 2, 3}).Find(&users)
+	})
+	if sql != "SELECT * FROM `prepared_users` WHERE id IN (1,2,3,3)" {
+		t.Errorf("IN list should be padded, got %v", sql)
+	}
+	sql = db.Clauses(gorm.NoPrepare{}).ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("id IN ?", []uint{1, 2, 3}).Find(&users)
+	})
+	if sql != "SELECT * FROM `prepared_users` WHERE id IN (1,2,3)" {
+		t.Errorf("IN list should not be padded without prepare, got %v", sql)
+	}
+}
+
+func TestPreparedStmtInvalidatedByMigrator(t *testing.T) {
+	db, pdb := openPreparedDB(t, &gorm.Config{})
+
+	db.Create(&PreparedUser{Name: "a"})
+	var users []PreparedUser
+	db.Find(&users)
+	if stats := pdb.Stats(); stats.Size != 2 {
+		t.Fatalf("statements should be cached, got %+v", stats)
+	}
+
+	if err := db.Migrator().RenameColumn(&PreparedUser{}, "name", "nick"); err != nil {
+		t.Fatalf("failed to rename column, got error %v", err)
+	}
+	if stats := pdb.Stats(); stats.Size != 0 || stats.Evictions != 0 {
+		t.Errorf("statements should be invalidated after table altered, got %+v", stats)
+	}
+}
+
+func TestPreparedStmtEvictions(t *testing.T) {
+	db, pdb := openPreparedDB(t, &gorm.Config{PrepareStmtMaxSize: 1})
+
+	var users []PreparedUser
+	db.Find(&users)
+	db.Where("name = ?", "a").Find(&users)
+	db.Find(&users)
+	if stats := pdb.Stats(); stats.Size != 1 || stats.Evictions != 2 || stats.Misses != 3 {
+		t.Errorf("statements should be evicted by max size, got %+v", stats)
+	}
+}
@@ -0,0 +1,182 @@
+package gorm_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+)
+
+func TestWithSavePoint(t *testing.T) {
+	db := openHooksDB(t)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if depth := tx.SavePointDepth(); depth != 0 {
+			t.Errorf("depth should be 0 without savepoints, got %v", depth)
+		}
+
+		if err := tx.WithSavePoint(func(tx *gorm.DB) error {
+			if depth := tx.SavePointDepth(); depth != 1 {
+				t.Errorf("depth should be 1 in savepoint, got %v", depth)
+			}
+			return tx.WithSavePoint(func(tx *gorm.DB) error {
+				if depth := tx.SavePointDepth(); depth != 2 {
+					t.Errorf("depth should be 2 in nested savepoint, got %v", depth)
+				}
+				return tx.Create(&HookOrder{Name: "kept"}).Error
+			})
+		}); err != nil {
+			t.Errorf("savepoint should succeed, got error %v", err)
+		}
+
+		errRollback := errors.New("rollback")
+		if err := tx.WithSavePoint(func(tx *gorm.DB) error {
+			tx.Create(&HookOrder{Name: "discarded"})
+			return errRollback
+		}); !errors.Is(err, errRollback) {
+			t.Errorf("savepoint should return error of the block, got %v", err)
+		}
+
+		if depth := tx.SavePointDepth(); depth != 0 {
+			t.Errorf("savepoints should be released, got depth %v", depth)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("transaction should succeed, got error %v", err)
+	}
+
+	var names []string
+	db.Model(&HookOrder{}).Order("id").Pluck("name", &names)
+	if len(names) != 1 || names[0] != "kept" {
+		t.Errorf("changes of the failed savepoint should be rolled back, got %v", names)
+	}
+
+	if err := db.WithSavePoint(func(tx *gorm.DB) error { return nil }); !errors.Is(err, gorm.ErrInvalidTransaction) {
+		t.Errorf("savepoint outside transaction should fail, got error %v", err)
+	}
+	if depth := db.SavePointDepth(); depth != 0 {
+		t.Errorf("depth should be 0 outside transaction, got %v", depth)
+	}
+}
+
+func TestWithSavePointNames(t *testing.T) {
+	db := openHooksDB(t)
+
+	var savePoints []string
+	db.Callback().Raw().After("gorm:raw").Register("test:savepoints", func(db *gorm.DB) {
+		if sql := db.Statement.SQL.String(); strings.Contains(sql, "SAVEPOINT") {
+			savePoints = append(savePoints, sql)
+		}
+	})
+
+	db.Transaction(func(tx *gorm.DB) error {
+		tx.WithSavePoint(func(tx *gorm.DB) error {
+			return tx.WithSavePoint(func(tx *gorm.DB) error { return errors.New("rollback") })
+		})
+		return tx.WithSavePoint(func(tx *gorm.DB) error { return nil })
+	})
+
+	expects := []string{
+		"SAVEPOINT sp_1", "SAVEPOINT sp_2", "ROLLBACK TO SAVEPOINT sp_2", "RELEASE SAVEPOINT sp_2",
+		"ROLLBACK TO SAVEPOINT sp_1", "RELEASE SAVEPOINT sp_1",
+		"SAVEPOINT sp_1", "RELEASE SAVEPOINT sp_1",
+	}
+	if !reflect.DeepEqual(savePoints, expects) {
+		t.Errorf("savepoints should be named after the depth, got %v", savePoints)
+	}
+}
+
+func TestWithSavePointPanic(t *testing.T) {
+	db := openHooksDB(t)
+
+	tx := db.Begin()
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("panic should be propagated")
+			}
+		}()
+		tx.WithSavePoint(func(tx *gorm.DB) error {
+			tx.Create(&HookOrder{Name: "panicked"})
+			panic("savepoint")
+		})
+	}()
+
+	if depth := tx.SavePointDepth(); depth != 0 {
+		t.Errorf("savepoint should be released after panic, got depth %v", depth)
+	}
+	if err := tx.Commit().Error; err != nil {
+		t.Fatalf("failed to commit, got error %v", err)
+	}
+
+	var count int64
+	db.Model(&HookOrder{}).Count(&count)
+	if count != 0 {
+		t.Errorf("changes of the panicked savepoint should be rolled back, got %v rows", count)
+	}
+}
+
+func TestReleaseSavePoint(t *testing.T) {
+	db := openHooksDB(t)
+
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	tx.SavePoint("sp1")
+	tx.SavePoint("sp2")
+	if depth := tx.SavePointDepth(); depth != 2 {
+		t.Errorf("depth should be 2, got %v", depth)
+	}
+
+	tx.Create(&HookOrder{Name: "released"})
+	if err := tx.ReleaseSavePoint("sp1").Error; err != nil {
+		t.Fatalf("failed to release savepoint, got error %v", err)
+	}
+	if depth := tx.SavePointDepth(); depth != 0 {
+		t.Errorf("savepoints created after the released one should be released, got depth %v", depth)
+	}
+
+	var count int64
+	tx.Model(&HookOrder{}).Where("name = ?", "released").Count(&count)
+	if count != 1 {
+		t.Errorf("changes should be kept after released, got %v rows", count)
+	}
+}
+
+func TestSavePointWithPreparedStmt(t *testing.T) {
+	db := openHooksDB(t)
+
+	err := db.Session(&gorm.Session{PrepareStmt: true}).Transaction(func(tx *gorm.DB) error {
+		if _, ok := tx.Statement.ConnPool.(*gorm.PreparedStmtTX); !ok {
+			t.Fatalf("conn pool should be prepared statement transaction, got %T", tx.Statement.ConnPool)
+		}
+
+		tx.WithSavePoint(func(tx *gorm.DB) error {
+			if depth := tx.SavePointDepth(); depth != 1 {
+				t.Errorf("depth should be 1 in savepoint, got %v", depth)
+			}
+			tx.Create(&HookOrder{Name: "discarded"})
+			return errors.New("rollback")
+		})
+
+		if _, ok := tx.Statement.ConnPool.(*gorm.PreparedStmtTX); !ok {
+			t.Errorf("prepared statement transaction should be restored, got %T", tx.Statement.ConnPool)
+		}
+		if depth := tx.SavePointDepth(); depth != 0 {
+			t.Errorf("depth should be 0 after savepoint, got %v", depth)
+		}
+		return tx.Create(&HookOrder{Name: "kept"}).Error
+	})
+	if err != nil {
+		t.Fatalf("transaction should succeed, got error %v", err)
+	}
+
+	var names []string
+	db.Model(&HookOrder{}).Pluck("name", &names)
+	if len(names) != 1 || names[0] != "kept" {
+		t.Errorf("changes of the failed savepoint should be rolled back, got %v", names)
+	}
+}
@@ -50,7 +50,7 @@ func scanIntoMap(mapValue map[string]interface{}, values []interface{}, columns
 	}
 }
 
-func (db *DB) scanIntoStruct(rows Rows, reflectValue reflect.Value, values []interface{}, fields []*schema.Field, joinFields [][]*schema.Field) {
+func (db *DB) scanIntoStruct(rows Rows, reflectValue reflect.Value, values []interface{}, fields []*schema.Field, joinFields [][]*schema.Field, hookTx *DB) {
 	for idx, field := range fields {
 		if field != nil {
 			values[idx] = field.NewValuePool.Get()
@@ -66,6 +66,7 @@ func (db *DB) scanIntoStruct(rows Rows, reflectValue reflect.Value, values []int
 	db.RowsAffected++
 	db.AddError(rows.Scan(values...))
 	joinedNestedSchemaMap := make(map[string]interface{})
+	nilJoinedRelations := nilJoinedRelations(values, joinFields)
 	for idx, field := range fields {
 		if field == nil {
 			continue
@@ -88,7 +89,10 @@ func (db *DB) scanIntoStruct(rows Rows, reflectValue reflect.Value, values []int
 					fullRelsName := utils.JoinNestedRelationNames(fullRels)
 					// same nested structure
 					if _, ok := joinedNestedSchemaMap[fullRelsName]; !ok {
-						if value := reflect.ValueOf(values[idx]).Elem(); value.Kind() == reflect.Ptr && value.IsNil() {
+						if nilJoinedRelations[fullRelsName] {
+							if !relValue.IsNil() {
+								relValue.Set(reflect.Zero(relValue.Type()))
+							}
 							isNilPtrValue = true
 							break
 						}
@@ -109,6 +113,47 @@ func (db *DB) scanIntoStruct(rows Rows, reflectValue reflect.Value, values []int
 		// release data to pool
 		field.NewValuePool.Put(values[idx])
 	}
+
+	if hookTx != nil {
+		if reflectValue.Kind() != reflect.Ptr && reflectValue.CanAddr() {
+			reflectValue = reflectValue.Addr()
+		}
+
+		if i, ok := reflectValue.Interface().(AfterScanRowInterface); ok {
+			db.AddError(NewHookError("AfterScanRow", i.AfterScanRow(hookTx)))
+		}
+	}
+}
+
+// nilJoinedRelations returns the joined relations whose scanned columns are all NULL, e.g. the missing rows of LEFT JOIN,
+// they are scanned into nil pointers instead of zero structs, columns can't be checked (e.g. serializers) are ignored
+func nilJoinedRelations(values []interface{}, joinFields [][]*schema.Field) map[string]bool {
+	if len(joinFields) == 0 {
+		return nil
+	}
+
+	relations := map[string]bool{}
+	for idx, relFields := range joinFields {
+		if len(relFields) < 2 {
+			continue
+		}
+
+		value := reflect.ValueOf(values[idx])
+		if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Ptr {
+			continue
+		}
+
+		isNil := value.Elem().IsNil()
+		names := make([]string, 0, len(relFields)-1)
+		for _, relField := range relFields[:len(relFields)-1] {
+			names = append(names, relField.Name)
+			name := utils.JoinNestedRelationNames(names)
+			if v, ok := relations[name]; !ok || v {
+				relations[name] = isNil
+			}
+		}
+	}
+	return relations
 }
 
 // ScanMode scan data mode
@@ -250,20 +295,24 @@ func Scan(rows Rows, db *DB, mode ScanMode) {
 							relFields := make([]*schema.Field, 0, subNameCount-1)
 							relFields = append(relFields, rel.Field)
 							for _, name := range names[1 : subNameCount-1] {
-								rel = rel.FieldSchema.Relationships.Relations[name]
+								if rel = rel.FieldSchema.Relationships.Relations[name]; rel == nil {
+									break
+								}
 								relFields = append(relFields, rel.Field)
 							}
 							// latest name is raw dbname
 							dbName := names[subNameCount-1]
-							if field := rel.FieldSchema.LookUpField(dbName); field != nil && field.Readable {
-								fields[idx] = field
+							if rel != nil {
+								if field := rel.FieldSchema.LookUpField(dbName); field != nil && field.Readable {
+									fields[idx] = field
 
-								if len(joinFields) == 0 {
-									joinFields = make([][]*schema.Field, len(columns))
+									if len(joinFields) == 0 {
+										joinFields = make([][]*schema.Field, len(columns))
+									}
+									relFields = append(relFields, field)
+									joinFields[idx] = relFields
+									continue
 								}
-								relFields = append(relFields, field)
-								joinFields[idx] = relFields
-								continue
 							}
 						}
 						var val interface{}
@@ -276,6 +325,20 @@ func Scan(rows Rows, db *DB, mode ScanMode) {
 			}
 		}
 
+		// collect the status of upserted rows
+		if statuses, ok := db.InstanceGet(upsertStatusesKey); ok {
+			for idx, column := range columns {
+				if column == UpsertStatusColumn && fields[idx] == nil {
+					values[idx] = (*upsertStatusScanner)(statuses.(*[]UpsertStatus))
+				}
+			}
+		}
+
+		var hookTx *DB
+		if sch != nil && sch.AfterScanRow && !db.Statement.SkipHooks {
+			hookTx = db.Session(&Session{NewDB: true})
+		}
+
 		switch reflectValue.Kind() {
 		case reflect.Slice, reflect.Array:
 			var (
@@ -319,7 +382,7 @@ func Scan(rows Rows, db *DB, mode ScanMode) {
 					elem = reflect.New(reflectValueType)
 				}
 
-				db.scanIntoStruct(rows, elem, values, fields, joinFields)
+				db.scanIntoStruct(rows, elem, values, fields, joinFields, hookTx)
 
 				if !update {
 					if !isPtr {
@@ -343,7 +406,7 @@ func Scan(rows Rows, db *DB, mode ScanMode) {
 				if mode == ScanInitialized && reflectValue.Kind() == reflect.Struct {
 					db.Statement.ReflectValue.Set(reflect.Zero(reflectValue.Type()))
 				}
-				db.scanIntoStruct(rows, reflectValue, values, fields, joinFields)
+				db.scanIntoStruct(rows, reflectValue, values, fields, joinFields, hookTx)
 			}
 		default:
 			db.AddError(rows.Scan(dest))
@@ -1,6 +1,7 @@
 package schema_test
 
 import (
+	"context"
 	"reflect"
 	"sync"
 	"testing"
@@ -31,9 +32,34 @@ func TestCallback(t *testing.T) {
 		}
 	}
 
-	for _, str := range []string{"BeforeCreate", "BeforeUpdate", "AfterUpdate", "AfterSave", "BeforeDelete", "AfterDelete", "AfterFind"} {
+	for _, str := range []string{"BeforeCreate", "BeforeUpdate", "AfterUpdate", "AfterSave", "BeforeDelete", "AfterDelete", "BeforeFind", "AfterFind", "AfterScanRow"} {
 		if reflect.Indirect(reflect.ValueOf(user)).FieldByName(str).Interface().(bool) {
 			t.Errorf("%v should be false", str)
 		}
 	}
 }
+
+type UserWithContextCallback struct{}
+
+func (UserWithContextCallback) BeforeCreateContext(context.Context, *gorm.DB) error {
+	return nil
+}
+
+func (UserWithContextCallback) AfterScanRow(*gorm.DB) error {
+	return nil
+}
+
+func TestContextCallback(t *testing.T) {
+	user, err := schema.Parse(&UserWithContextCallback{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse user with callback, got error %v", err)
+	}
+
+	if !user.BeforeCreate || !user.AfterScanRow {
+		t.Errorf("BeforeCreate and AfterScanRow should be true")
+	}
+
+	if user.AfterCreate || user.BeforeFind {
+		t.Errorf("AfterCreate and BeforeFind should be false")
+	}
+}
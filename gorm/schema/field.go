@@ -90,6 +90,8 @@ type Field struct {
 	Set                    func(context.Context, reflect.Value, interface{}) error
 	Serializer             SerializerInterface
 	NewValuePool           FieldNewValuePool
+	BlindIndex             *Field
+	Aggregate              string
 
 	// In some db (e.g. MySQL), Unique and UniqueIndex are indistinguishable.
 	// When a column has a (not Mul) UniqueIndex, Migrator always reports its gorm.ColumnType is Unique.
@@ -369,6 +371,15 @@ func (schema *Schema) ParseField(fieldStruct reflect.StructField) *Field {
 		}
 	}
 
+	// aggregate of relation records, e.g. `gorm:"->;aggregate:Orders.count"`, not a column
+	if v, ok := field.TagSettings["AGGREGATE"]; ok {
+		field.Aggregate = v
+		field.Creatable = false
+		field.Updatable = false
+		field.DataType = ""
+		field.IgnoreMigration = true
+	}
+
 	if v, ok := field.TagSettings["<-"]; ok {
 		field.Creatable = true
 		field.Updatable = true
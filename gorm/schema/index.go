@@ -73,10 +73,30 @@ func (schema *Schema) ParseIndexes() []*Index {
 		if index.Class == "UNIQUE" && len(index.Fields) == 1 {
 			index.Fields[0].Field.UniqueIndex = index.Name
 		}
+
+		// spatial indexes, `index:,class:SPATIAL` or `index:,type:gist` of geometry fields, migrators create them with
+		// the spatial index of dialects, e.g. USING gist in postgres
+		if strings.EqualFold(index.Class, "SPATIAL") || (index.Class == "" && isSpatialIndex(index)) {
+			index.Class = "SPATIAL"
+		}
 	}
 	return indexes
 }
 
+// isSpatialIndex returns whether the index is gist/spgist index of geometry fields
+func isSpatialIndex(index *Index) bool {
+	if indexType := strings.ToLower(index.Type); indexType != "gist" && indexType != "spgist" {
+		return false
+	}
+
+	for _, option := range index.Fields {
+		if option.Field == nil || option.Field.GORMDataType != "geometry" {
+			return false
+		}
+	}
+	return len(index.Fields) > 0
+}
+
 func (schema *Schema) LookIndex(name string) *Index {
 	if schema != nil {
 		indexes := schema.ParseIndexes()
@@ -273,3 +273,41 @@ func CheckIndices(t *testing.T, expected, actual []*schema.Index) {
 		})
 	}
 }
+
+type spatialPoint []byte
+
+func (spatialPoint) GormDataType() string {
+	return "geometry"
+}
+
+func TestParseSpatialIndex(t *testing.T) {
+	type SpatialIndex struct {
+		Location spatialPoint `gorm:"index:,class:spatial"`
+		Area     spatialPoint `gorm:"index:,type:gist"`
+		Period   string       `gorm:"index:,type:gist"`
+	}
+
+	indexSchema, err := schema.Parse(&SpatialIndex{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse spatial index, got error %v", err)
+	}
+
+	CheckIndices(t, []*schema.Index{
+		{
+			Name:   "idx_spatial_indices_location",
+			Class:  "SPATIAL",
+			Fields: []schema.IndexOption{{Field: &schema.Field{Name: "Location"}}},
+		},
+		{
+			Name:   "idx_spatial_indices_area",
+			Class:  "SPATIAL",
+			Type:   "gist",
+			Fields: []schema.IndexOption{{Field: &schema.Field{Name: "Area"}}},
+		},
+		{
+			Name:   "idx_spatial_indices_period",
+			Type:   "gist",
+			Fields: []schema.IndexOption{{Field: &schema.Field{Name: "Period"}}},
+		},
+	}, indexSchema.ParseIndexes())
+}
@@ -25,6 +25,24 @@ const (
 	callbackTypeBeforeDelete callbackType = "BeforeDelete"
 	callbackTypeAfterDelete  callbackType = "AfterDelete"
 	callbackTypeAfterFind    callbackType = "AfterFind"
+	callbackTypeBeforeFind   callbackType = "BeforeFind"
+	callbackTypeAfterScanRow callbackType = "AfterScanRow"
+	callbackTypeBeforeUpsert callbackType = "BeforeUpsert"
+
+	callbackTypeAfterCommit   callbackType = "AfterCommit"
+	callbackTypeAfterRollback callbackType = "AfterRollback"
+
+	callbackTypeBeforeCreateContext callbackType = "BeforeCreateContext"
+	callbackTypeBeforeUpdateContext callbackType = "BeforeUpdateContext"
+	callbackTypeAfterCreateContext  callbackType = "AfterCreateContext"
+	callbackTypeAfterUpdateContext  callbackType = "AfterUpdateContext"
+	callbackTypeBeforeSaveContext   callbackType = "BeforeSaveContext"
+	callbackTypeAfterSaveContext    callbackType = "AfterSaveContext"
+	callbackTypeBeforeDeleteContext callbackType = "BeforeDeleteContext"
+	callbackTypeAfterDeleteContext  callbackType = "AfterDeleteContext"
+	callbackTypeBeforeFindContext   callbackType = "BeforeFindContext"
+	callbackTypeAfterFindContext    callbackType = "AfterFindContext"
+	callbackTypeBeforeUpsertContext callbackType = "BeforeUpsertContext"
 )
 
 // ErrUnsupportedDataType unsupported data type
@@ -52,7 +70,11 @@ type Schema struct {
 	BeforeUpdate, AfterUpdate bool
 	BeforeDelete, AfterDelete bool
 	BeforeSave, AfterSave     bool
-	AfterFind                 bool
+	BeforeFind, AfterFind     bool
+	AfterScanRow              bool
+	BeforeUpsert              bool
+	AfterCommit               bool
+	AfterRollback             bool
 	err                       error
 	initialized               chan struct{}
 	namer                     Namer
@@ -287,6 +309,16 @@ func ParseWithSpecialTableName(dest interface{}, cacheStore *sync.Map, namer Nam
 		if field.DataType != "" && field.HasDefaultValue && field.DefaultValueInterface == nil {
 			schema.FieldsWithDefaultDBValue = append(schema.FieldsWithDefaultDBValue, field)
 		}
+
+		if name, ok := field.TagSettings["BLINDINDEX"]; ok {
+			if blindIndex := schema.LookUpField(name); blindIndex == nil || blindIndex.DBName == "" {
+				schema.err = fmt.Errorf("invalid blind index %s for field %s in %s", name, field.Name, schema)
+			} else if _, ok := field.Serializer.(BlindIndexer); !ok {
+				schema.err = fmt.Errorf("serializer of field %s in %s doesn't support blind index", field.Name, schema)
+			} else {
+				field.setupBlindIndex(blindIndex)
+			}
+		}
 	}
 
 	if field := schema.PrioritizedPrimaryField; field != nil {
@@ -308,15 +340,34 @@ func ParseWithSpecialTableName(dest interface{}, cacheStore *sync.Map, namer Nam
 		callbackTypeBeforeUpdate, callbackTypeAfterUpdate,
 		callbackTypeBeforeSave, callbackTypeAfterSave,
 		callbackTypeBeforeDelete, callbackTypeAfterDelete,
-		callbackTypeAfterFind,
+		callbackTypeBeforeFind, callbackTypeAfterFind,
+		callbackTypeAfterScanRow, callbackTypeBeforeUpsert,
+		callbackTypeAfterCommit, callbackTypeAfterRollback,
+		callbackTypeBeforeCreateContext, callbackTypeAfterCreateContext,
+		callbackTypeBeforeUpdateContext, callbackTypeAfterUpdateContext,
+		callbackTypeBeforeSaveContext, callbackTypeAfterSaveContext,
+		callbackTypeBeforeDeleteContext, callbackTypeAfterDeleteContext,
+		callbackTypeBeforeFindContext, callbackTypeAfterFindContext,
+		callbackTypeBeforeUpsertContext,
 	}
 	for _, cbName := range callbackTypes {
 		if methodValue := callBackToMethodValue(modelValue, cbName); methodValue.IsValid() {
+			// context-first hooks share the flag of the hooks in the same phase
+			name, isContext := strings.CutSuffix(string(cbName), "Context")
+			params, results := "*gorm.DB", " error"
+			if isContext {
+				params = "context.Context, *gorm.DB"
+			}
+			// transaction hooks are called after the transaction finished, no error could be returned
+			if cbName == callbackTypeAfterCommit || cbName == callbackTypeAfterRollback {
+				results = ""
+			}
+
 			switch methodValue.Type().String() {
-			case "func(*gorm.DB) error": // TODO hack
-				reflect.Indirect(reflect.ValueOf(schema)).FieldByName(string(cbName)).SetBool(true)
+			case "func(" + params + ")" + results: // TODO hack
+				reflect.Indirect(reflect.ValueOf(schema)).FieldByName(name).SetBool(true)
 			default:
-				logger.Default.Warn(context.Background(), "Model %v don't match %vInterface, should be `%v(*gorm.DB) error`. Please see https://gorm.io/docs/hooks.html", schema, cbName, cbName)
+				logger.Default.Warn(context.Background(), "Model %v don't match %vInterface, should be `%v(%v)%v`. Please see https://gorm.io/docs/hooks.html", schema, cbName, cbName, params, results)
 			}
 		}
 	}
@@ -398,6 +449,38 @@ func callBackToMethodValue(modelType reflect.Value, cbType callbackType) reflect
 		return modelType.MethodByName(string(callbackTypeAfterDelete))
 	case callbackTypeAfterFind:
 		return modelType.MethodByName(string(callbackTypeAfterFind))
+	case callbackTypeBeforeFind:
+		return modelType.MethodByName(string(callbackTypeBeforeFind))
+	case callbackTypeAfterScanRow:
+		return modelType.MethodByName(string(callbackTypeAfterScanRow))
+	case callbackTypeBeforeUpsert:
+		return modelType.MethodByName(string(callbackTypeBeforeUpsert))
+	case callbackTypeAfterCommit:
+		return modelType.MethodByName(string(callbackTypeAfterCommit))
+	case callbackTypeAfterRollback:
+		return modelType.MethodByName(string(callbackTypeAfterRollback))
+	case callbackTypeBeforeCreateContext:
+		return modelType.MethodByName(string(callbackTypeBeforeCreateContext))
+	case callbackTypeAfterCreateContext:
+		return modelType.MethodByName(string(callbackTypeAfterCreateContext))
+	case callbackTypeBeforeUpdateContext:
+		return modelType.MethodByName(string(callbackTypeBeforeUpdateContext))
+	case callbackTypeAfterUpdateContext:
+		return modelType.MethodByName(string(callbackTypeAfterUpdateContext))
+	case callbackTypeBeforeSaveContext:
+		return modelType.MethodByName(string(callbackTypeBeforeSaveContext))
+	case callbackTypeAfterSaveContext:
+		return modelType.MethodByName(string(callbackTypeAfterSaveContext))
+	case callbackTypeBeforeDeleteContext:
+		return modelType.MethodByName(string(callbackTypeBeforeDeleteContext))
+	case callbackTypeAfterDeleteContext:
+		return modelType.MethodByName(string(callbackTypeAfterDeleteContext))
+	case callbackTypeBeforeFindContext:
+		return modelType.MethodByName(string(callbackTypeBeforeFindContext))
+	case callbackTypeAfterFindContext:
+		return modelType.MethodByName(string(callbackTypeAfterFindContext))
+	case callbackTypeBeforeUpsertContext:
+		return modelType.MethodByName(string(callbackTypeBeforeUpsertContext))
 	default:
 		return reflect.ValueOf(nil)
 	}
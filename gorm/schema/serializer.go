@@ -34,6 +34,7 @@ func init() {
 	RegisterSerializer("json", JSONSerializer{})
 	RegisterSerializer("unixtime", UnixSecondSerializer{})
 	RegisterSerializer("gob", GobSerializer{})
+	RegisterSerializer("encrypt", EncryptSerializer{})
 }
 
 // Serializer field value serializer
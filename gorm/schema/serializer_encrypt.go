@@ -0,0 +1,409 @@
+package schema
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+var (
+	// ErrMissingKeyProvider the encrypt serializer is used without a key provider
+	ErrMissingKeyProvider = errors.New("missing key provider for encrypt serializer")
+	// ErrInvalidCiphertext the value can't be decrypted by the encrypt serializer
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+)
+
+const (
+	encryptVersion = 1
+	dataKeySize    = 32
+)
+
+// KeyProvider provides the keys of EncryptSerializer, keys must be 16, 24 or 32 bytes to select AES-128, AES-192 or AES-256
+type KeyProvider interface {
+	// CurrentKey returns the key used to encrypt values and its id, the id is stored in the ciphertext
+	CurrentKey(ctx context.Context) (id string, key []byte, err error)
+	// Key returns the key of id, used to decrypt values encrypted by current or rotated keys
+	Key(ctx context.Context, id string) (key []byte, err error)
+}
+
+// BlindIndexKeyProvider provides the key of blind indexes, it should never be rotated
+type BlindIndexKeyProvider interface {
+	BlindIndexKey(ctx context.Context) ([]byte, error)
+}
+
+// BlindIndexer computes the blind index of field values
+type BlindIndexer interface {
+	BlindIndex(ctx context.Context, field *Field, fieldValue interface{}) (string, error)
+}
+
+// StaticKeyProvider key provider with in-memory keys
+type StaticKeyProvider struct {
+	CurrentKeyID string
+	Keys         map[string][]byte
+	IndexKey     []byte
+}
+
+// CurrentKey implements KeyProvider interface
+func (p StaticKeyProvider) CurrentKey(ctx context.Context) (string, []byte, error) {
+	key, err := p.Key(ctx, p.CurrentKeyID)
+	return p.CurrentKeyID, key, err
+}
+
+// Key implements KeyProvider interface
+func (p StaticKeyProvider) Key(ctx context.Context, id string) ([]byte, error) {
+	if key, ok := p.Keys[id]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("encryption key %q not found", id)
+}
+
+// BlindIndexKey implements BlindIndexKeyProvider interface
+func (p StaticKeyProvider) BlindIndexKey(ctx context.Context) ([]byte, error) {
+	if len(p.IndexKey) == 0 {
+		return nil, errors.New("blind index key not set")
+	}
+	return p.IndexKey, nil
+}
+
+// EncryptSerializer encrypts field values with AES-GCM envelope encryption, every value is encrypted by a random data
+// key, and the data key is encrypted by the current key of KeyProvider, whose id is stored in the ciphertext, so values
+// encrypted by rotated keys are still readable and re-encrypted with the current key when saved.
+//
+//	schema.RegisterSerializer("encrypt", schema.EncryptSerializer{KeyProvider: provider})
+//
+//	type User struct {
+//		ID        uint
+//		Email     string `gorm:"serializer:encrypt;blindIndex:EmailIndex"`
+//		EmailIndex string `gorm:"size:64;index"` // HMAC of Email, used by db.Where(&User{Email: email})
+//	}
+type EncryptSerializer struct {
+	KeyProvider KeyProvider
+}
+
+// Scan implements serializer interface
+func (s EncryptSerializer) Scan(ctx context.Context, field *Field, dst reflect.Value, dbValue interface{}) (err error) {
+	fieldValue := reflect.New(field.FieldType)
+
+	if dbValue != nil {
+		var encoded []byte
+		switch v := dbValue.(type) {
+		case []byte:
+			encoded = v
+		case string:
+			encoded = []byte(v)
+		default:
+			return fmt.Errorf("failed to decrypt value: %#v", dbValue)
+		}
+
+		if len(encoded) > 0 {
+			var plaintext []byte
+			if plaintext, err = s.decrypt(ctx, encoded); err != nil {
+				return err
+			}
+
+			if err = decodePlaintext(plaintext, fieldValue.Elem()); err != nil {
+				return err
+			}
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return
+}
+
+// Value implements serializer interface
+func (s EncryptSerializer) Value(ctx context.Context, field *Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plaintext, ok, err := encodePlaintext(fieldValue)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return s.encrypt(ctx, plaintext)
+}
+
+// BlindIndex implements BlindIndexer interface, returns hex encoded HMAC-SHA256 of the field value
+func (s EncryptSerializer) BlindIndex(ctx context.Context, field *Field, fieldValue interface{}) (string, error) {
+	if s.KeyProvider == nil {
+		return "", ErrMissingKeyProvider
+	}
+
+	indexKeyProvider, ok := s.KeyProvider.(BlindIndexKeyProvider)
+	if !ok {
+		return "", fmt.Errorf("key provider %T doesn't provide blind index key", s.KeyProvider)
+	}
+
+	key, err := indexKeyProvider.BlindIndexKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, _, err := encodePlaintext(fieldValue)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(field.DBName))
+	mac.Write([]byte{0})
+	mac.Write(plaintext)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// KeyIDOf returns the key id of a value encrypted by EncryptSerializer
+func (EncryptSerializer) KeyIDOf(ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	keyID, _, err := parseEncryptHeader(data)
+	return keyID, err
+}
+
+// ciphertext layout, encoded by base64:
+//
+//	version(1) | len(key id)(1) | key id | len(encrypted data key)(2) | encrypted data key | nonce | encrypted value
+//
+// the data key is encrypted by the key of key id with AES-GCM, the header is used as additional data of both.
+func (s EncryptSerializer) encrypt(ctx context.Context, plaintext []byte) (string, error) {
+	if s.KeyProvider == nil {
+		return "", ErrMissingKeyProvider
+	}
+
+	keyID, key, err := s.KeyProvider.CurrentKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if len(keyID) > 255 {
+		return "", fmt.Errorf("encryption key id %q too long", keyID)
+	}
+
+	header := append([]byte{encryptVersion, byte(len(keyID))}, keyID...)
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err = io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+
+	encryptedKey, err := sealAESGCM(key, dataKey, header)
+	if err != nil {
+		return "", err
+	}
+
+	encryptedValue, err := sealAESGCM(dataKey, plaintext, header)
+	if err != nil {
+		return "", err
+	}
+
+	data := make([]byte, 0, len(header)+2+len(encryptedKey)+len(encryptedValue))
+	data = append(data, header...)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(encryptedKey)))
+	data = append(data, encryptedKey...)
+	data = append(data, encryptedValue...)
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+func (s EncryptSerializer) decrypt(ctx context.Context, encoded []byte) ([]byte, error) {
+	if s.KeyProvider == nil {
+		return nil, ErrMissingKeyProvider
+	}
+
+	data := make([]byte, base64.StdEncoding.DecodedLen(len(encoded)))
+	n, err := base64.StdEncoding.Decode(data, encoded)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	data = data[:n]
+
+	keyID, headerLen, err := parseEncryptHeader(data)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.KeyProvider.Key(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	header, rest := data[:headerLen], data[headerLen:]
+	if len(rest) < 2 {
+		return nil, ErrInvalidCiphertext
+	}
+
+	keyLen := int(binary.BigEndian.Uint16(rest))
+	if rest = rest[2:]; len(rest) < keyLen {
+		return nil, ErrInvalidCiphertext
+	}
+
+	dataKey, err := openAESGCM(key, rest[:keyLen], header)
+	if err != nil {
+		return nil, err
+	}
+
+	return openAESGCM(dataKey, rest[keyLen:], header)
+}
+
+func parseEncryptHeader(data []byte) (keyID string, headerLen int, err error) {
+	if len(data) < 2 || data[0] != encryptVersion {
+		return "", 0, ErrInvalidCiphertext
+	}
+
+	headerLen = 2 + int(data[1])
+	if len(data) < headerLen {
+		return "", 0, ErrInvalidCiphertext
+	}
+	return string(data[2:headerLen]), headerLen, nil
+}
+
+func sealAESGCM(key, plaintext, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func openAESGCM(key, ciphertext, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+
+	plaintext, err := aead.Open(nil, ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():], additionalData)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plaintext, nil
+}
+
+// encodePlaintext strings and bytes are encrypted as it is, other values are encoded as json, ok is false for nil values
+func encodePlaintext(fieldValue interface{}) (plaintext []byte, ok bool, err error) {
+	rv := reflect.ValueOf(fieldValue)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, false, nil
+		}
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return nil, false, nil
+	}
+
+	switch {
+	case rv.Kind() == reflect.String:
+		return []byte(rv.String()), true, nil
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		return rv.Bytes(), true, nil
+	default:
+		plaintext, err = json.Marshal(rv.Interface())
+		return plaintext, err == nil, err
+	}
+}
+
+func decodePlaintext(plaintext []byte, rv reflect.Value) error {
+	for rv.Kind() == reflect.Ptr {
+		rv.Set(reflect.New(rv.Type().Elem()))
+		rv = rv.Elem()
+	}
+
+	switch {
+	case rv.Kind() == reflect.String:
+		rv.SetString(string(plaintext))
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		rv.SetBytes(plaintext)
+	default:
+		return json.Unmarshal(plaintext, rv.Addr().Interface())
+	}
+	return nil
+}
+
+// blindIndexValuer computes the blind index of the encrypted field value when executing
+type blindIndexValuer struct {
+	Field      *Field
+	Context    context.Context
+	fieldValue interface{}
+}
+
+// Value implements driver.Valuer interface
+func (v blindIndexValuer) Value() (driver.Value, error) {
+	indexer, ok := v.Field.Serializer.(BlindIndexer)
+	if !ok {
+		return nil, fmt.Errorf("serializer of field %s doesn't support blind index", v.Field.Name)
+	}
+
+	if _, ok, err := encodePlaintext(v.fieldValue); err != nil || !ok {
+		return nil, err
+	}
+	return indexer.BlindIndex(v.Context, v.Field, v.fieldValue)
+}
+
+// setupBlindIndex makes blindIndex field store the blind index of the field, the blind index is used as condition
+// instead of the field for struct conditions
+func (field *Field) setupBlindIndex(blindIndex *Field) {
+	field.BlindIndex = blindIndex
+
+	blindIndex.ValueOf = func(ctx context.Context, v reflect.Value) (interface{}, bool) {
+		value, zero := field.ValueOf(ctx, v)
+		if zero {
+			return reflect.Zero(blindIndex.FieldType).Interface(), true
+		}
+
+		if s, ok := value.(*serializer); ok {
+			value = s.fieldValue
+		}
+		return blindIndexValuer{Field: field, Context: ctx, fieldValue: value}, false
+	}
+}
+
+// SerializedValueOf returns the value of field serialized by its serializer, used when the field value is not
+// read from the model, e.g. updating with map
+func (field *Field) SerializedValueOf(ctx context.Context, value interface{}) interface{} {
+	if field.Serializer == nil {
+		return value
+	}
+
+	return &serializer{
+		Field:           field,
+		SerializeValuer: field.Serializer,
+		Context:         ctx,
+		fieldValue:      value,
+	}
+}
+
+// BlindIndexValueOf returns the blind index value of field value
+func (field *Field) BlindIndexValueOf(ctx context.Context, value interface{}) interface{} {
+	return blindIndexValuer{Field: field, Context: ctx, fieldValue: value}
+}
@@ -0,0 +1,111 @@
+package schema_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/internal/testdb"
+	"github.com/fangxing98/jx-gorm/gorm/schema"
+)
+
+type EncryptedUser struct {
+	ID         uint
+	Name       string
+	Email      string   `gorm:"serializer:encrypt;blindIndex:EmailIndex"`
+	EmailIndex string   `gorm:"size:64;index"`
+	Phone      *string  `gorm:"serializer:encrypt"`
+	Tags       []string `gorm:"serializer:encrypt"`
+}
+
+func TestEncryptSerializer(t *testing.T) {
+	provider := &schema.StaticKeyProvider{
+		CurrentKeyID: "v1",
+		Keys:         map[string][]byte{"v1": []byte(strings.Repeat("k", 32))},
+		IndexKey:     []byte("blind index key"),
+	}
+	schema.RegisterSerializer("encrypt", schema.EncryptSerializer{KeyProvider: provider})
+	defer schema.RegisterSerializer("encrypt", schema.EncryptSerializer{})
+
+	db := testdb.Open(t, nil, &EncryptedUser{})
+
+	phone := "+1 555 0100"
+	user := EncryptedUser{Name: "jinzhu", Email: "jinzhu@example.org", Phone: &phone, Tags: []string{"a", "b"}}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user, got error %v", err)
+	}
+
+	var raw map[string]interface{}
+	db.Table("encrypted_users").Where("id = ?", user.ID).Take(&raw)
+	if email, _ := raw["email"].(string); email == "" || strings.Contains(email, "jinzhu") {
+		t.Fatalf("email should be stored encrypted, got %v", raw["email"])
+	}
+	if keyID, err := (schema.EncryptSerializer{}).KeyIDOf(raw["email"].(string)); err != nil || keyID != "v1" {
+		t.Errorf("key id should be v1, got %v, error %v", keyID, err)
+	}
+
+	var result EncryptedUser
+	if err := db.Where(&EncryptedUser{Email: "jinzhu@example.org"}).First(&result).Error; err != nil {
+		t.Fatalf("failed to find user by blind index, got error %v", err)
+	}
+	if result.Email != user.Email || result.Phone == nil || *result.Phone != phone || len(result.Tags) != 2 {
+		t.Errorf("decrypted user should be same, expects %+v, got %+v", user, result)
+	}
+
+	if err := db.Where(&EncryptedUser{Email: "unknown@example.org"}).First(&EncryptedUser{}).Error; err != gorm.ErrRecordNotFound {
+		t.Errorf("should not find user with unknown email, got %v", err)
+	}
+
+	// rotate key, values encrypted by old keys are still readable and re-encrypted when saved
+	provider.Keys["v2"] = []byte(strings.Repeat("n", 32))
+	provider.CurrentKeyID = "v2"
+
+	if err := db.First(&result, user.ID).Error; err != nil || result.Email != user.Email {
+		t.Fatalf("failed to read value encrypted by rotated key, got %v, error %v", result.Email, err)
+	}
+
+	if err := db.Save(&result).Error; err != nil {
+		t.Fatalf("failed to save user, got error %v", err)
+	}
+
+	raw = map[string]interface{}{}
+	db.Table("encrypted_users").Where("id = ?", user.ID).Take(&raw)
+	if keyID, _ := (schema.EncryptSerializer{}).KeyIDOf(raw["email"].(string)); keyID != "v2" {
+		t.Errorf("value should be re-encrypted with current key, got %v", keyID)
+	}
+
+	if err := db.Model(&result).Updates(map[string]interface{}{"email": "hello@example.org"}).Error; err != nil {
+		t.Fatalf("failed to update email, got error %v", err)
+	}
+
+	raw = map[string]interface{}{}
+	db.Table("encrypted_users").Where("id = ?", user.ID).Take(&raw)
+	if email, _ := raw["email"].(string); strings.Contains(email, "hello") {
+		t.Errorf("updated email should be stored encrypted, got %v", email)
+	}
+
+	if err := db.Where(&EncryptedUser{Email: "hello@example.org"}).First(&result).Error; err != nil || result.ID != user.ID {
+		t.Errorf("failed to find user by updated blind index, got error %v", err)
+	}
+}
+
+func TestEncryptSerializerInvalidBlindIndex(t *testing.T) {
+	type InvalidBlindIndex struct {
+		ID    uint
+		Email string `gorm:"serializer:encrypt;blindIndex:Unknown"`
+	}
+
+	if _, err := schema.Parse(&InvalidBlindIndex{}, &sync.Map{}, schema.NamingStrategy{}); err == nil {
+		t.Errorf("should return error for unknown blind index field")
+	}
+}
+
+func TestEncryptSerializerWithoutKeyProvider(t *testing.T) {
+	_, err := schema.EncryptSerializer{}.Value(context.Background(), &schema.Field{}, reflect.Value{}, "value")
+	if err != schema.ErrMissingKeyProvider {
+		t.Errorf("should return ErrMissingKeyProvider, got %v", err)
+	}
+}
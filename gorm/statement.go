@@ -35,6 +35,7 @@ type Statement struct {
 	ColumnMapping        map[string]string // map columns
 	Joins                []join
 	Preloads             map[string][]interface{}
+	Aggregates           []Aggregate
 	Settings             sync.Map
 	ConnPool             ConnPool
 	Schema               *schema.Schema
@@ -47,6 +48,17 @@ type Statement struct {
 	attrs                []interface{}
 	assigns              []interface{}
 	scopes               []func(*DB) *DB
+	transaction          *txState
+}
+
+// Aggregate aggregate of relation records, loaded by WithCount and WithAggregate
+type Aggregate struct {
+	Relation string
+	Func     string
+	Column   string
+	// Dest pointer to a value of a single record, or to a map keyed by the primary keys of records
+	Dest  interface{}
+	Conds []interface{}
 }
 
 type join struct {
@@ -402,6 +414,11 @@ func (stmt *Statement) BuildCondition(query interface{}, args ...interface{}) []
 				switch reflectValue.Kind() {
 				case reflect.Struct:
 					for _, field := range s.Fields {
+						if field.BlindIndex != nil {
+							// encrypted values are randomized, query with its blind index
+							continue
+						}
+
 						selected := selectedColumns[field.DBName] || selectedColumns[field.Name]
 						if selected || (!restricted && field.Readable) {
 							if v, isZero := field.ValueOf(stmt.Context, reflectValue); !isZero || selected {
@@ -416,6 +433,11 @@ func (stmt *Statement) BuildCondition(query interface{}, args ...interface{}) []
 				case reflect.Slice, reflect.Array:
 					for i := 0; i < reflectValue.Len(); i++ {
 						for _, field := range s.Fields {
+							if field.BlindIndex != nil {
+								// encrypted values are randomized, query with its blind index
+								continue
+							}
+
 							selected := selectedColumns[field.DBName] || selectedColumns[field.Name]
 							if selected || (!restricted && field.Readable) {
 								if v, isZero := field.ValueOf(stmt.Context, reflectValue.Index(i)); !isZero || selected {
@@ -521,6 +543,7 @@ func (stmt *Statement) clone() *Statement {
 		Context:              stmt.Context,
 		RaiseErrorOnNotFound: stmt.RaiseErrorOnNotFound,
 		SkipHooks:            stmt.SkipHooks,
+		transaction:          stmt.transaction,
 	}
 
 	if stmt.SQL.Len() > 0 {
@@ -537,6 +560,11 @@ func (stmt *Statement) clone() *Statement {
 		newStmt.Preloads[k] = p
 	}
 
+	if len(stmt.Aggregates) > 0 {
+		newStmt.Aggregates = make([]Aggregate, len(stmt.Aggregates))
+		copy(newStmt.Aggregates, stmt.Aggregates)
+	}
+
 	if len(stmt.Joins) > 0 {
 		newStmt.Joins = make([]join, len(stmt.Joins))
 		copy(newStmt.Joins, stmt.Joins)
@@ -0,0 +1,33 @@
+package gorm
+
+// AfterCommit registers fc to be called after the current transaction committed,
+// fc registered after a savepoint is discarded if the transaction rolled back to the savepoint,
+// fc is called immediately if db is not in a transaction
+//
+//	db.Transaction(func(tx *gorm.DB) error {
+//		tx.Create(&order)
+//		tx.AfterCommit(func() { notify(order) })
+//		return nil
+//	})
+func (db *DB) AfterCommit(fc func()) *DB {
+	if state := db.txState(); state != nil {
+		state.mu.Lock()
+		state.afterCommit = append(state.afterCommit, fc)
+		state.mu.Unlock()
+	} else {
+		fc()
+	}
+	return db
+}
+
+// AfterRollback registers fc to be called after the current transaction rolled back or failed to commit,
+// fc registered after a savepoint is discarded if the transaction rolled back to the savepoint,
+// fc is ignored if db is not in a transaction
+func (db *DB) AfterRollback(fc func()) *DB {
+	if state := db.txState(); state != nil {
+		state.mu.Lock()
+		state.afterRollback = append(state.afterRollback, fc)
+		state.mu.Unlock()
+	}
+	return db
+}
@@ -0,0 +1,170 @@
+package gorm_test
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/internal/testdb"
+)
+
+type HookOrder struct {
+	ID     uint
+	Name   string
+	events *[]string
+}
+
+func (o *HookOrder) AfterCommit(tx *gorm.DB) {
+	var count int64
+	tx.Model(&HookOrder{}).Where("name = ?", o.Name).Count(&count)
+	if o.events != nil {
+		*o.events = append(*o.events, "commit:"+o.Name)
+		if count != 1 {
+			*o.events = append(*o.events, "not visible:"+o.Name)
+		}
+	}
+}
+
+func (o *HookOrder) AfterRollback(*gorm.DB) {
+	if o.events != nil {
+		*o.events = append(*o.events, "rollback:"+o.Name)
+	}
+}
+
+func openHooksDB(t *testing.T) *gorm.DB {
+	db := testdb.Open(t, nil, &HookOrder{})
+	return db
+}
+
+func TestTransactionAfterCommitAndRollback(t *testing.T) {
+	db := openHooksDB(t)
+
+	var events []string
+	db.Transaction(func(tx *gorm.DB) error {
+		tx.AfterCommit(func() { events = append(events, "commit") })
+		tx.AfterRollback(func() { events = append(events, "rollback") })
+		if len(events) != 0 {
+			t.Errorf("hooks should not be called before commit, got %v", events)
+		}
+		return nil
+	})
+	if !reflect.DeepEqual(events, []string{"commit"}) {
+		t.Errorf("after commit hooks should be called, got %v", events)
+	}
+
+	events = nil
+	db.Transaction(func(tx *gorm.DB) error {
+		tx.AfterCommit(func() { events = append(events, "commit") })
+		tx.AfterRollback(func() { events = append(events, "rollback") })
+		return errors.New("rollback")
+	})
+	if !reflect.DeepEqual(events, []string{"rollback"}) {
+		t.Errorf("after rollback hooks should be called, got %v", events)
+	}
+
+	events = nil
+	tx := db.Begin()
+	tx.AfterCommit(func() { events = append(events, "commit") })
+	tx.Rollback()
+	tx.Rollback()
+	if len(events) != 0 {
+		t.Errorf("after commit hooks should be discarded after rollback, got %v", events)
+	}
+
+	events = nil
+	db.AfterCommit(func() { events = append(events, "commit") })
+	db.AfterRollback(func() { events = append(events, "rollback") })
+	if !reflect.DeepEqual(events, []string{"commit"}) {
+		t.Errorf("after commit hooks should be called immediately without transaction, got %v", events)
+	}
+}
+
+func TestTransactionHooksWithSavePoint(t *testing.T) {
+	for _, prepareStmt := range []bool{false, true} {
+		db := openHooksDB(t).Session(&gorm.Session{PrepareStmt: prepareStmt})
+
+		var events []string
+		db.Transaction(func(tx *gorm.DB) error {
+			tx.AfterCommit(func() { events = append(events, "outer") })
+
+			tx.Transaction(func(tx2 *gorm.DB) error {
+				tx2.AfterCommit(func() { events = append(events, "discarded") })
+				tx2.AfterRollback(func() { events = append(events, "discarded rollback") })
+				return errors.New("rollback to savepoint")
+			})
+
+			tx.Transaction(func(tx2 *gorm.DB) error {
+				tx2.AfterCommit(func() { events = append(events, "nested") })
+				return nil
+			})
+			return nil
+		})
+
+		if !reflect.DeepEqual(events, []string{"outer", "nested"}) {
+			t.Errorf("hooks registered in rolled back savepoint should be discarded with PrepareStmt %v, got %v", prepareStmt, events)
+		}
+	}
+}
+
+func TestTransactionStateReleasedWithSession(t *testing.T) {
+	db := openHooksDB(t)
+
+	released := make(chan struct{})
+	func() {
+		tx := db.Begin()
+		order := &HookOrder{Name: "abandoned"}
+		runtime.SetFinalizer(order, func(*HookOrder) { close(released) })
+		tx.AfterCommit(func() { order.Name = "committed" })
+
+		// finished outside of the session, the hooks are never called
+		if err := tx.Statement.ConnPool.(gorm.TxCommitter).Commit(); err != nil {
+			t.Fatalf("failed to commit, got error %v", err)
+		}
+	}()
+
+	for i := 0; i < 10; i++ {
+		runtime.GC()
+		select {
+		case <-released:
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	t.Errorf("state of the transaction should be released with its sessions")
+}
+
+func TestModelTransactionHooks(t *testing.T) {
+	db := openHooksDB(t)
+
+	var events []string
+	if err := db.Create(&HookOrder{Name: "default", events: &events}).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+	if !reflect.DeepEqual(events, []string{"commit:default"}) {
+		t.Errorf("model after commit hook should be called after default transaction, got %v", events)
+	}
+
+	events = nil
+	db.Transaction(func(tx *gorm.DB) error {
+		tx.Create(&[]HookOrder{{Name: "a", events: &events}, {Name: "b", events: &events}})
+		if len(events) != 0 {
+			t.Errorf("model hooks should not be called before commit, got %v", events)
+		}
+		return nil
+	})
+	if !reflect.DeepEqual(events, []string{"commit:a", "commit:b"}) {
+		t.Errorf("model after commit hooks should be called after commit, got %v", events)
+	}
+
+	events = nil
+	db.Transaction(func(tx *gorm.DB) error {
+		tx.Create(&HookOrder{Name: "c", events: &events})
+		return errors.New("rollback")
+	})
+	if !reflect.DeepEqual(events, []string{"rollback:c"}) {
+		t.Errorf("model after rollback hook should be called after rollback, got %v", events)
+	}
+}
@@ -0,0 +1,186 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// TxLockMode SQLite transaction lock mode
+type TxLockMode string
+
+const (
+	TxLockModeDeferred  TxLockMode = "DEFERRED"
+	TxLockModeImmediate TxLockMode = "IMMEDIATE"
+	TxLockModeExclusive TxLockMode = "EXCLUSIVE"
+)
+
+// TxOptions transaction options, translated by the dialector into the statements beginning the transaction,
+// set it with Config.TxOptions or Session.TxOptions
+//
+//	db.Session(&gorm.Session{TxOptions: &gorm.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true, Deferrable: true}}).Transaction(func(tx *gorm.DB) error {
+//		return tx.Find(&reports).Error
+//	})
+type TxOptions struct {
+	// Isolation transaction isolation level, e.g. sql.LevelSnapshot for SQL Server
+	Isolation sql.IsolationLevel
+	// ReadOnly read-only transaction
+	ReadOnly bool
+	// Deferrable PostgreSQL SERIALIZABLE READ ONLY DEFERRABLE transaction
+	Deferrable bool
+	// LockMode SQLite BEGIN DEFERRED, IMMEDIATE or EXCLUSIVE
+	LockMode TxLockMode
+}
+
+// TxBeginPlan how to begin a transaction with TxOptions
+type TxBeginPlan struct {
+	// Options passed to BeginTx of database/sql
+	Options *sql.TxOptions
+	// Setup statements executed on the connection before the transaction began, e.g. SET TRANSACTION ISOLATION LEVEL SNAPSHOT
+	Setup []string
+	// Begin statement beginning the transaction instead of BeginTx, e.g. BEGIN IMMEDIATE
+	Begin string
+	// Statements executed in the transaction after it began, e.g. SET TRANSACTION DEFERRABLE
+	Statements []string
+	// Teardown statements executed on the connection after the transaction finished to restore the connection,
+	// e.g. PRAGMA query_only = OFF, the connection is discarded if failed to execute them
+	Teardown []string
+}
+
+// TxOptionsDialectorInterface translates TxOptions into the plan beginning transactions,
+// returns ErrUnsupportedTxOptions if the options are not supported
+type TxOptionsDialectorInterface interface {
+	TranslateTxOptions(opts TxOptions) (TxBeginPlan, error)
+}
+
+// with overrides isolation level and read-only mode with options passed to Begin
+func (opts TxOptions) with(opt *sql.TxOptions) TxOptions {
+	if opt != nil {
+		opts.Isolation = opt.Isolation
+		opts.ReadOnly = opt.ReadOnly
+	}
+	return opts
+}
+
+// txBeginPlan translates opts with the dialector, options of database/sql are used if the dialector doesn't translate
+func (db *DB) txBeginPlan(opts TxOptions) (TxBeginPlan, error) {
+	if translator, ok := db.Dialector.(TxOptionsDialectorInterface); ok {
+		return translator.TranslateTxOptions(opts)
+	}
+
+	if opts.Deferrable || opts.LockMode != "" {
+		return TxBeginPlan{}, fmt.Errorf("%w: deferrable and lock mode are not supported by %s", ErrUnsupportedTxOptions, db.Dialector.Name())
+	}
+	return TxBeginPlan{Options: &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly}}, nil
+}
+
+// beginOnConn begins the transaction on a dedicated connection, the connection is released after the transaction finished
+func beginOnConn(ctx context.Context, connPool ConnPool, plan TxBeginPlan) (ConnPool, error) {
+	if _, ok := connPool.(TxCommitter); ok {
+		// already in a transaction
+		return nil, ErrInvalidTransaction
+	}
+
+	var sqlDB *sql.DB
+	if dbConnector, ok := connPool.(GetDBConnector); ok && dbConnector != nil {
+		sqlDB, _ = dbConnector.GetDBConn()
+	} else {
+		sqlDB, _ = connPool.(*sql.DB)
+	}
+	if sqlDB == nil {
+		return nil, ErrInvalidTransaction
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &connTx{db: sqlDB, conn: conn, teardown: plan.Teardown}
+	for _, stmt := range plan.Setup {
+		if _, err = conn.ExecContext(ctx, stmt); err != nil {
+			tx.close()
+			return nil, err
+		}
+	}
+
+	if plan.Begin != "" {
+		_, err = conn.ExecContext(ctx, plan.Begin)
+		tx.ConnPool = conn
+	} else if tx.tx, err = conn.BeginTx(ctx, plan.Options); err == nil {
+		tx.ConnPool = tx.tx
+	}
+
+	if err != nil {
+		tx.close()
+		return nil, err
+	}
+	return tx, nil
+}
+
+// connTx transaction on a dedicated connection
+type connTx struct {
+	ConnPool
+	db       *sql.DB
+	conn     *sql.Conn
+	tx       *sql.Tx
+	teardown []string
+	closed   bool
+}
+
+// GetDBConn implements GetDBConnector
+func (tx *connTx) GetDBConn() (*sql.DB, error) {
+	return tx.db, nil
+}
+
+// Commit commits the transaction and releases the connection
+func (tx *connTx) Commit() error {
+	if tx.tx != nil {
+		err := tx.tx.Commit()
+		tx.close()
+		return err
+	}
+
+	if tx.closed {
+		return sql.ErrTxDone
+	}
+	// the transaction is still in progress if failed to commit, release the connection after rollback
+	if _, err := tx.conn.ExecContext(context.Background(), "COMMIT"); err != nil {
+		return err
+	}
+	return tx.close()
+}
+
+// Rollback rollbacks the transaction and releases the connection
+func (tx *connTx) Rollback() error {
+	var err error
+	if tx.tx != nil {
+		err = tx.tx.Rollback()
+	} else if tx.closed {
+		return sql.ErrTxDone
+	} else {
+		_, err = tx.conn.ExecContext(context.Background(), "ROLLBACK")
+	}
+
+	if closeErr := tx.close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (tx *connTx) close() error {
+	if tx.closed {
+		return nil
+	}
+	tx.closed = true
+
+	for _, stmt := range tx.teardown {
+		if _, err := tx.conn.ExecContext(context.Background(), stmt); err != nil {
+			// the state of the connection can't be restored, discard it instead of releasing it to the pool
+			tx.conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+			return nil
+		}
+	}
+	return tx.conn.Close()
+}
@@ -0,0 +1,152 @@
+package gorm_test
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/driver/sqlite"
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/internal/testdb"
+)
+
+type TxOptionsUser struct {
+	ID   uint
+	Name string
+}
+
+func TestTxOptionsLockMode(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tx_options.db")
+	db, err := gorm.Open(sqlite.Open(file), gorm.DBTypeSqlite, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+	db.AutoMigrate(&TxOptionsUser{})
+
+	db2, err := gorm.Open(sqlite.Open(file+"?_busy_timeout=10"), gorm.DBTypeSqlite, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	immediate := db.Session(&gorm.Session{TxOptions: &gorm.TxOptions{LockMode: gorm.TxLockModeImmediate}})
+	for i := 0; i < 3; i++ {
+		err = immediate.Transaction(func(tx *gorm.DB) error {
+			if err := db2.Create(&TxOptionsUser{Name: "blocked"}).Error; err == nil {
+				t.Errorf("immediate transaction should hold the write lock")
+			}
+
+			return tx.Transaction(func(tx2 *gorm.DB) error {
+				return tx2.Create(&TxOptionsUser{Name: "immediate"}).Error
+			})
+		})
+		if err != nil {
+			t.Fatalf("failed to run immediate transaction, got error %v", err)
+		}
+	}
+
+	immediate.Transaction(func(tx *gorm.DB) error {
+		tx.Create(&TxOptionsUser{Name: "rollback"})
+		return errors.New("rollback")
+	})
+
+	deferred := db.Session(&gorm.Session{TxOptions: &gorm.TxOptions{LockMode: gorm.TxLockModeDeferred}})
+	err = deferred.Transaction(func(tx *gorm.DB) error {
+		if err := db2.Create(&TxOptionsUser{Name: "deferred"}).Error; err != nil {
+			t.Errorf("deferred transaction should not hold the write lock before writing, got %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to run deferred transaction, got error %v", err)
+	}
+
+	var names []string
+	db.Model(&TxOptionsUser{}).Order("id").Pluck("name", &names)
+	if len(names) != 4 || names[0] != "immediate" || names[3] != "deferred" {
+		t.Errorf("transactions should be committed or rolled back, got %v", names)
+	}
+}
+
+func TestTxOptionsValidation(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tx_options.db")
+	db, err := gorm.Open(sqlite.Open(file), gorm.DBTypeSqlite, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+
+	for _, opts := range []gorm.TxOptions{
+		{Deferrable: true},
+		{Isolation: sql.LevelSnapshot},
+		{ReadOnly: true, LockMode: gorm.TxLockModeImmediate},
+		{LockMode: "SHARED"},
+	} {
+		tx := db.Session(&gorm.Session{TxOptions: &opts})
+		if !errors.Is(tx.Error, gorm.ErrUnsupportedTxOptions) {
+			t.Errorf("session with %+v should be invalid, got %v", opts, tx.Error)
+		}
+
+		called := false
+		if err := tx.Transaction(func(*gorm.DB) error { called = true; return nil }); !errors.Is(err, gorm.ErrUnsupportedTxOptions) || called {
+			t.Errorf("transaction with %+v should fail before running, got %v", opts, err)
+		}
+	}
+
+	if _, err := gorm.Open(sqlite.Open(file), gorm.DBTypeSqlite, &gorm.Config{TxOptions: &gorm.TxOptions{Deferrable: true}}); !errors.Is(err, gorm.ErrUnsupportedTxOptions) {
+		t.Errorf("open with invalid transaction options should fail, got %v", err)
+	}
+
+	tx := db.Session(&gorm.Session{TxOptions: &gorm.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}})
+	if err := tx.Transaction(func(*gorm.DB) error { return nil }); err != nil {
+		t.Errorf("serializable transaction should be supported, got %v", err)
+	}
+}
+
+func TestTxOptionsReadOnly(t *testing.T) {
+	db := testdb.Open(t, nil, &TxOptionsUser{})
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	for _, opts := range []gorm.TxOptions{{ReadOnly: true}, {ReadOnly: true, LockMode: gorm.TxLockModeDeferred}} {
+		err := db.Session(&gorm.Session{TxOptions: &opts}).Transaction(func(tx *gorm.DB) error {
+			var count int64
+			if err := tx.Model(&TxOptionsUser{}).Count(&count).Error; err != nil {
+				t.Errorf("read-only transaction should be able to read, got error %v", err)
+			}
+			return tx.Create(&TxOptionsUser{Name: "read-only"}).Error
+		})
+		if err == nil {
+			t.Errorf("read-only transaction with %+v should not write", opts)
+		}
+
+		if err := db.Create(&TxOptionsUser{Name: "writable"}).Error; err != nil {
+			t.Errorf("connection should be writable after read-only transaction, got error %v", err)
+		}
+	}
+}
+
+func TestTxOptionsBegin(t *testing.T) {
+	db := testdb.Open(t, nil, &TxOptionsUser{})
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	tx := db.Begin(&sql.TxOptions{ReadOnly: true})
+	if tx.Error != nil {
+		t.Fatalf("failed to begin read-only transaction, got error %v", tx.Error)
+	}
+	if err := tx.Create(&TxOptionsUser{Name: "read-only"}).Error; err == nil {
+		t.Errorf("read-only transaction began with sql.TxOptions should not write")
+	}
+	tx.Rollback()
+
+	if err := db.Create(&TxOptionsUser{Name: "writable"}).Error; err != nil {
+		t.Errorf("connection should be writable after read-only transaction, got error %v", err)
+	}
+
+	if tx := db.Begin(&sql.TxOptions{Isolation: sql.LevelSnapshot}); !errors.Is(tx.Error, gorm.ErrUnsupportedTxOptions) {
+		t.Errorf("isolation level passed to Begin should be translated, got %v", tx.Error)
+	}
+}
@@ -0,0 +1,122 @@
+package gorm_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fangxing98/jx-gorm/driver/sqlite"
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/internal/testdb"
+)
+
+type RetryAccount struct {
+	ID      uint
+	Balance int
+}
+
+var errRetryable = errors.New("retryable")
+
+func TestTransactionWithRetry(t *testing.T) {
+	db := testdb.Open(t, nil, &RetryAccount{})
+
+	policy := gorm.RetryPolicy{BaseDelay: time.Millisecond, Retryable: func(err error) bool { return errors.Is(err, errRetryable) }}
+
+	var attempts int
+	err := db.TransactionWithRetry(func(tx *gorm.DB) error {
+		attempts++
+		if err := tx.Create(&RetryAccount{Balance: attempts}).Error; err != nil {
+			return err
+		}
+		if attempts < 3 {
+			return errRetryable
+		}
+		return nil
+	}, policy)
+	if err != nil || attempts != 3 {
+		t.Fatalf("transaction should succeed after 3 attempts, got attempts %v, error %v", attempts, err)
+	}
+
+	var accounts []RetryAccount
+	db.Find(&accounts)
+	if len(accounts) != 1 || accounts[0].Balance != 3 {
+		t.Errorf("failed attempts should be rolled back, got %+v", accounts)
+	}
+
+	attempts = 0
+	err = db.TransactionWithRetry(func(tx *gorm.DB) error {
+		attempts++
+		return errRetryable
+	}, gorm.RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, Retryable: policy.Retryable})
+	if !errors.Is(err, errRetryable) || attempts != 3 {
+		t.Errorf("transaction should stop after max retries, got attempts %v, error %v", attempts, err)
+	}
+
+	attempts = 0
+	err = db.TransactionWithRetry(func(tx *gorm.DB) error {
+		attempts++
+		return errors.New("not retryable")
+	}, policy)
+	if err == nil || attempts != 1 {
+		t.Errorf("transaction should not retry on other errors, got attempts %v, error %v", attempts, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts = 0
+	err = db.WithContext(ctx).TransactionWithRetry(func(tx *gorm.DB) error {
+		attempts++
+		cancel()
+		return errRetryable
+	}, gorm.RetryPolicy{BaseDelay: time.Hour, MaxDelay: time.Hour, Retryable: policy.Retryable})
+	if !errors.Is(err, errRetryable) || attempts != 1 {
+		t.Errorf("transaction should stop retrying when context canceled, got attempts %v, error %v", attempts, err)
+	}
+}
+
+func TestTransactionRetryNested(t *testing.T) {
+	var (
+		outer, inner int
+		policy       = gorm.RetryPolicy{BaseDelay: time.Millisecond, Retryable: func(err error) bool { return errors.Is(err, errRetryable) }}
+	)
+
+	db := testdb.Open(t, &gorm.Config{TransactionRetry: &policy}, &RetryAccount{})
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		outer++
+		tx.Create(&RetryAccount{Balance: outer})
+		return tx.Transaction(func(tx2 *gorm.DB) error {
+			inner++
+			if outer < 2 {
+				return errRetryable
+			}
+			return tx2.Create(&RetryAccount{Balance: 10}).Error
+		})
+	})
+	if err != nil || outer != 2 || inner != 2 {
+		t.Fatalf("only the outermost transaction should retry, got outer %v, inner %v, error %v", outer, inner, err)
+	}
+
+	var count int64
+	db.Model(&RetryAccount{}).Count(&count)
+	if count != 2 {
+		t.Errorf("should have 2 accounts, got %v", count)
+	}
+}
+
+func TestSQLiteRetryableError(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "busy.db")
+	db1, _ := gorm.Open(sqlite.Open(file), gorm.DBTypeSqlite, &gorm.Config{})
+	db2, _ := gorm.Open(sqlite.Open(file+"?_busy_timeout=10"), gorm.DBTypeSqlite, &gorm.Config{})
+	db1.AutoMigrate(&RetryAccount{})
+
+	tx := db1.Begin()
+	defer tx.Rollback()
+	tx.Create(&RetryAccount{Balance: 1})
+
+	err := db2.Create(&RetryAccount{Balance: 2}).Error
+	if err == nil || !(sqlite.Dialector{}).Retryable(err) {
+		t.Errorf("database locked error should be retryable, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package gorm
+
+import (
+	"reflect"
+	"sync"
+)
+
+// txState hooks and savepoints of a transaction, kept on the statements of the transaction sessions, so it is
+// released with them even if the transaction is never committed or rolled back with the session
+type txState struct {
+	mu            sync.Mutex
+	afterCommit   []func()
+	afterRollback []func()
+	savePoints    []savePointState
+	finished      bool
+}
+
+// savePointState savepoint with the count of hooks registered before it
+type savePointState struct {
+	name          string
+	afterCommit   int
+	afterRollback int
+}
+
+// txState returns the state of the current transaction, nil if not in a transaction
+func (db *DB) txState() *txState {
+	state := db.Statement.transaction
+	if state == nil || !inTransaction(db.Statement.ConnPool) {
+		return nil
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.finished {
+		return nil
+	}
+	return state
+}
+
+// UseTransaction runs the statement in the transaction began by tx, e.g. the default transaction began by callbacks,
+// switching only the conn pool loses hooks and savepoints of the transaction
+func (stmt *Statement) UseTransaction(tx *DB) {
+	stmt.ConnPool = tx.Statement.ConnPool
+	stmt.transaction = tx.Statement.transaction
+}
+
+func inTransaction(connPool interface{}) bool {
+	committer, ok := connPool.(TxCommitter)
+	return ok && committer != nil && reflect.ValueOf(committer).Kind() == reflect.Ptr && !reflect.ValueOf(committer).IsNil()
+}
+
+// lastSavePoint returns the index of the latest savepoint named name, -1 if not found
+func (state *txState) lastSavePoint(name string) int {
+	for idx := len(state.savePoints) - 1; idx >= 0; idx-- {
+		if state.savePoints[idx].name == name {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (state *txState) savePoint(name string) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.savePoints = append(state.savePoints, savePointState{name: name, afterCommit: len(state.afterCommit), afterRollback: len(state.afterRollback)})
+}
+
+// rollbackTo discards hooks registered after the savepoint, savepoints created after it are destroyed
+func (state *txState) rollbackTo(name string) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if idx := state.lastSavePoint(name); idx >= 0 {
+		savePoint := state.savePoints[idx]
+		state.afterCommit = state.afterCommit[:savePoint.afterCommit]
+		state.afterRollback = state.afterRollback[:savePoint.afterRollback]
+		state.savePoints = state.savePoints[:idx+1]
+	}
+}
+
+// release destroys the savepoint and savepoints created after it, hooks are kept
+func (state *txState) release(name string) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if idx := state.lastSavePoint(name); idx >= 0 {
+		state.savePoints = state.savePoints[:idx]
+	}
+}
+
+func (state *txState) depth() int {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return len(state.savePoints)
+}
+
+// finishTransaction finishes the state of the transaction, run after commit hooks if committed, otherwise after rollback hooks
+func (db *DB) finishTransaction(committed bool) {
+	state := db.Statement.transaction
+	if state == nil {
+		return
+	}
+
+	state.mu.Lock()
+	if state.finished {
+		state.mu.Unlock()
+		return
+	}
+	state.finished = true
+	fcs := state.afterRollback
+	if committed {
+		fcs = state.afterCommit
+	}
+	state.mu.Unlock()
+
+	for _, fc := range fcs {
+		fc()
+	}
+}
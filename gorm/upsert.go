@@ -0,0 +1,378 @@
+package gorm
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fangxing98/jx-gorm/gorm/clause"
+	"github.com/fangxing98/jx-gorm/gorm/schema"
+	"github.com/fangxing98/jx-gorm/gorm/utils"
+)
+
+// UpsertStatus status of an upserted row
+type UpsertStatus uint8
+
+const (
+	UpsertUnknown UpsertStatus = iota
+	UpsertInserted
+	UpsertUpdated
+)
+
+// UpsertStatusColumn the returning column reports the status of upserted rows
+const UpsertStatusColumn = "gorm_upsert_status"
+
+// upsertStatusesKey instance setting key of the upsert statuses
+const upsertStatusesKey = "gorm:upsert_statuses"
+
+// UpsertOptions options of Upsert
+type UpsertOptions struct {
+	// Columns conflict target, inferred from primary keys, unique indexes and unique constraints
+	// whose values are provided if empty
+	Columns []string
+	// Updates columns updated on conflict, all inserting columns if empty
+	Updates []string
+	// Except columns not updated on conflict
+	Except []string
+	// Increments columns increased by the inserting value on conflict, e.g. counters
+	Increments []string
+	// OnlyIfChanged only updates the conflicting rows if any updating column changed,
+	// MySQL never updates unchanged rows
+	OnlyIfChanged bool
+	// Statuses reports whether the rows are inserted or updated, in the order of returned rows,
+	// only supported by postgres, kingbase (RETURNING xmax = 0) and sqlserver (MERGE OUTPUT $action)
+	Statuses *[]UpsertStatus
+}
+
+// Upsert inserts value, updates the rows conflict with the conflict target
+//
+//	// INSERT INTO `users` ... ON CONFLICT (`email`) DO UPDATE SET `name`="excluded"."name",`logins`="users"."logins"+"excluded"."logins"
+//	db.Upsert(&users, gorm.UpsertOptions{Except: []string{"created_at"}, Increments: []string{"logins"}})
+func (db *DB) Upsert(value interface{}, opts UpsertOptions) (tx *DB) {
+	tx = db.getInstance()
+
+	model := tx.Statement.Model
+	if model == nil {
+		model = value
+	}
+
+	if err := tx.Statement.Parse(model); err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	onConflict, err := tx.Statement.upsertClause(value, opts)
+	if err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	if opts.Statuses != nil {
+		*opts.Statuses = (*opts.Statuses)[:0]
+
+		if tx.IsPgDriver() {
+			columns := make([]clause.Column, 0, len(tx.Statement.Schema.FieldsWithDefaultDBValue)+1)
+			for _, field := range tx.Statement.Schema.FieldsWithDefaultDBValue {
+				columns = append(columns, clause.Column{Name: field.DBName})
+			}
+			// xmax is 0 for inserted rows
+			columns = append(columns, clause.Column{Name: "(xmax = 0)", Alias: UpsertStatusColumn, Raw: true})
+			tx.Statement.AddClause(clause.Returning{Columns: columns})
+		}
+		tx.InstanceSet(upsertStatusesKey, opts.Statuses)
+	}
+
+	return tx.Clauses(onConflict).Create(value)
+}
+
+func (stmt *Statement) upsertClause(value interface{}, opts UpsertOptions) (onConflict clause.OnConflict, err error) {
+	s := stmt.Schema
+
+	conflictColumns := opts.Columns
+	if len(conflictColumns) == 0 {
+		var target *upsertTarget
+		if target, err = stmt.inferUpsertTarget(value); err != nil {
+			return
+		}
+
+		conflictColumns = target.columns
+		if target.where != "" {
+			onConflict.TargetWhere = clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: target.where}}}
+		}
+	}
+
+	for _, name := range conflictColumns {
+		if field := s.LookUpField(name); field != nil {
+			name = field.DBName
+		}
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: name})
+	}
+
+	lookUpDBNames := func(names []string) map[string]bool {
+		dbNames := make(map[string]bool, len(names))
+		for _, name := range names {
+			if field := s.LookUpField(name); field != nil {
+				name = field.DBName
+			}
+			dbNames[name] = true
+		}
+		return dbNames
+	}
+
+	var (
+		conflicts  = lookUpDBNames(conflictColumns)
+		excepts    = lookUpDBNames(opts.Except)
+		increments = lookUpDBNames(opts.Increments)
+		updates    []string
+	)
+
+	if len(opts.Updates) > 0 {
+		for _, name := range opts.Updates {
+			if field := s.LookUpField(name); field != nil {
+				name = field.DBName
+			}
+			if !excepts[name] && !increments[name] {
+				updates = append(updates, name)
+			}
+		}
+	} else {
+		selectColumns, restricted := stmt.SelectAndOmitColumns(true, false)
+		provided := upsertProvidedColumns(value)
+		for _, dbName := range s.DBNames {
+			field := s.FieldsByDBName[dbName]
+			if conflicts[dbName] || excepts[dbName] || increments[dbName] || field.PrimaryKey || !field.Updatable || field.AutoCreateTime > 0 {
+				continue
+			}
+
+			// skip columns not inserted, the excluded value would be the database default
+			if v, ok := selectColumns[dbName]; (ok && !v) || (!ok && restricted) {
+				continue
+			}
+
+			if provided != nil && !provided[dbName] && !provided[field.Name] {
+				continue
+			}
+
+			if field.HasDefaultValue && field.DefaultValueInterface == nil && !strings.EqualFold(field.DefaultValue, "NULL") && field.AutoUpdateTime == 0 {
+				continue
+			}
+
+			updates = append(updates, dbName)
+		}
+	}
+
+	onConflict.DoUpdates = clause.AssignmentColumns(updates)
+
+	incrementColumns := make([]string, 0, len(increments))
+	for name := range increments {
+		incrementColumns = append(incrementColumns, name)
+	}
+	sort.Strings(incrementColumns)
+
+	for _, name := range incrementColumns {
+		onConflict.DoUpdates = append(onConflict.DoUpdates, clause.Assignment{
+			Column: clause.Column{Name: name}, Value: stmt.upsertIncrement(name),
+		})
+	}
+
+	if len(onConflict.DoUpdates) == 0 {
+		onConflict.DoNothing = true
+	} else if opts.OnlyIfChanged {
+		changes := make([]clause.Expression, 0, len(updates)+len(incrementColumns))
+		for _, name := range updates {
+			changes = append(changes, stmt.upsertChanged(name))
+		}
+
+		for _, name := range incrementColumns {
+			changes = append(changes, clause.Neq{Column: clause.Column{Table: "excluded", Name: name}, Value: 0})
+		}
+		onConflict.Where = clause.Where{Exprs: []clause.Expression{clause.Or(changes...)}}
+	}
+
+	return
+}
+
+func (stmt *Statement) upsertIncrement(name string) clause.Expression {
+	if stmt.DB.DBType == DBTypeMySQL {
+		return clause.Expr{SQL: "? + VALUES(?)", Vars: []interface{}{clause.Column{Name: name}, clause.Column{Name: name}}}
+	}
+	return clause.Expr{SQL: "? + ?", Vars: []interface{}{clause.Column{Table: clause.CurrentTable, Name: name}, clause.Column{Table: "excluded", Name: name}}}
+}
+
+// upsertChanged null-safe comparison of the current and excluded values, so changes from or to NULL are detected
+func (stmt *Statement) upsertChanged(name string) clause.Expression {
+	current, excluded := clause.Column{Table: clause.CurrentTable, Name: name}, clause.Column{Table: "excluded", Name: name}
+	switch stmt.DB.DBType {
+	case DBTypePostgres, DBTypeKingBase:
+		return clause.Expr{SQL: "? IS DISTINCT FROM ?", Vars: []interface{}{current, excluded}}
+	case DBTypeSqlite:
+		return clause.Expr{SQL: "? IS NOT ?", Vars: []interface{}{current, excluded}}
+	case DBTypeMySQL:
+		return clause.Expr{SQL: "NOT (? <=> VALUES(?))", Vars: []interface{}{clause.Column{Name: name}, clause.Column{Name: name}}}
+	default:
+		return clause.Expr{
+			SQL:  "(? <> ? OR (? IS NULL AND ? IS NOT NULL) OR (? IS NOT NULL AND ? IS NULL))",
+			Vars: []interface{}{current, excluded, current, excluded, current, excluded},
+		}
+	}
+}
+
+type upsertTarget struct {
+	columns []string
+	where   string
+}
+
+// inferUpsertTarget infers the conflict target from primary keys, unique indexes and unique constraints,
+// the first one whose values are provided is used, primary keys are used if none of them provided
+func (stmt *Statement) inferUpsertTarget(value interface{}) (*upsertTarget, error) {
+	var (
+		s       = stmt.Schema
+		targets []*upsertTarget
+		fields  [][]*schema.Field
+	)
+
+	if len(s.PrimaryFields) > 0 {
+		targets = append(targets, &upsertTarget{columns: s.PrimaryFieldDBNames})
+		fields = append(fields, s.PrimaryFields)
+	}
+
+	for _, idx := range s.ParseIndexes() {
+		if idx.Class != "UNIQUE" {
+			continue
+		}
+
+		target, indexFields := &upsertTarget{where: idx.Where}, make([]*schema.Field, 0, len(idx.Fields))
+		for _, opt := range idx.Fields {
+			if opt.Expression != "" || opt.Field == nil {
+				indexFields = nil
+				break
+			}
+			target.columns = append(target.columns, opt.DBName)
+			indexFields = append(indexFields, opt.Field)
+		}
+
+		if len(indexFields) > 0 {
+			targets = append(targets, target)
+			fields = append(fields, indexFields)
+		}
+	}
+
+	uniques := s.ParseUniqueConstraints()
+	names := make([]string, 0, len(uniques))
+	for name := range uniques {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		field := uniques[name].Field
+		targets = append(targets, &upsertTarget{columns: []string{field.DBName}})
+		fields = append(fields, []*schema.Field{field})
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("%w: failed to infer upsert conflict target of %s", ErrPrimaryKeyRequired, s)
+	}
+
+	provided := upsertProvidedColumns(value)
+	for idx, target := range targets {
+		matched := true
+		for _, field := range fields[idx] {
+			if provided != nil {
+				matched = provided[field.DBName] || provided[field.Name]
+			} else {
+				matched = !stmt.upsertZeroValue(value, field)
+			}
+
+			if !matched {
+				break
+			}
+		}
+
+		if matched {
+			return target, nil
+		}
+	}
+
+	return targets[0], nil
+}
+
+// upsertZeroValue checks the field value of the first record is zero
+func (stmt *Statement) upsertZeroValue(value interface{}, field *schema.Field) bool {
+	reflectValue := reflect.Indirect(reflect.ValueOf(value))
+	switch reflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		if reflectValue.Len() == 0 {
+			return true
+		}
+		reflectValue = reflect.Indirect(reflectValue.Index(0))
+	}
+
+	if reflectValue.Kind() != reflect.Struct {
+		return true
+	}
+
+	fieldValue, zero := field.ValueOf(stmt.Context, reflectValue)
+	if valuer, ok := fieldValue.(driver.Valuer); ok && !zero {
+		v, err := valuer.Value()
+		return err != nil || v == nil
+	}
+	return zero
+}
+
+// upsertProvidedColumns returns the keys of map values, nil for struct values
+func upsertProvidedColumns(value interface{}) map[string]bool {
+	var values map[string]interface{}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		values = v
+	case *map[string]interface{}:
+		values = *v
+	case []map[string]interface{}:
+		if len(v) > 0 {
+			values = v[0]
+		}
+	case *[]map[string]interface{}:
+		if len(*v) > 0 {
+			values = (*v)[0]
+		}
+	default:
+		return nil
+	}
+
+	provided := make(map[string]bool, len(values))
+	for key := range values {
+		provided[key] = true
+	}
+	return provided
+}
+
+// upsertStatusScanner appends the status of scanned rows
+type upsertStatusScanner []UpsertStatus
+
+// Scan implements sql.Scanner interface
+func (statuses *upsertStatusScanner) Scan(src interface{}) error {
+	status := UpsertUnknown
+	switch v := src.(type) {
+	case bool:
+		if status = UpsertUpdated; v {
+			status = UpsertInserted
+		}
+	case int64:
+		if status = UpsertUpdated; v != 0 {
+			status = UpsertInserted
+		}
+	case string, []byte:
+		switch strings.ToUpper(utils.ToStringKey(v)) {
+		case "INSERT":
+			status = UpsertInserted
+		case "UPDATE":
+			status = UpsertUpdated
+		}
+	}
+
+	*statuses = append(*statuses, status)
+	return nil
+}
@@ -0,0 +1,99 @@
+package gorm_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/driver/postgres"
+	"github.com/fangxing98/jx-gorm/driver/sqlserver"
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/internal/testdb"
+)
+
+type UpsertUser struct {
+	ID     uint
+	Email  string `gorm:"size:100;uniqueIndex"`
+	Name   string
+	Logins int
+}
+
+func TestUpsert(t *testing.T) {
+	db := testdb.Open(t, nil, &UpsertUser{})
+
+	users := []UpsertUser{{Email: "a@example.org", Name: "a", Logins: 1}, {Email: "b@example.org", Name: "b", Logins: 1}}
+	if err := db.Upsert(&users, gorm.UpsertOptions{Increments: []string{"Logins"}}).Error; err != nil {
+		t.Fatalf("failed to upsert, got error %v", err)
+	}
+
+	again := []UpsertUser{{Email: "a@example.org", Name: "a2", Logins: 2}, {Email: "c@example.org", Name: "c", Logins: 1}}
+	if err := db.Upsert(&again, gorm.UpsertOptions{Except: []string{"name"}, Increments: []string{"Logins"}}).Error; err != nil {
+		t.Fatalf("failed to upsert, got error %v", err)
+	}
+
+	var result []UpsertUser
+	db.Order("email").Find(&result)
+	if len(result) != 3 {
+		t.Fatalf("should have 3 users, got %v", len(result))
+	}
+
+	if result[0].Name != "a" || result[0].Logins != 3 || result[0].ID != users[0].ID {
+		t.Errorf("conflicting row should be updated by unique index, got %+v", result[0])
+	}
+
+	tx := db.Upsert(&UpsertUser{Email: "b@example.org", Name: "b", Logins: 1}, gorm.UpsertOptions{OnlyIfChanged: true})
+	if tx.Error != nil || tx.RowsAffected != 0 {
+		t.Errorf("unchanged row should not be updated, got affected %v, error %v", tx.RowsAffected, tx.Error)
+	}
+
+	tx = db.Model(&UpsertUser{}).Upsert(map[string]interface{}{"email": "b@example.org", "name": "b2"}, gorm.UpsertOptions{OnlyIfChanged: true})
+	if tx.Error != nil || tx.RowsAffected != 1 {
+		t.Errorf("changed row should be updated, got affected %v, error %v", tx.RowsAffected, tx.Error)
+	}
+
+	var user UpsertUser
+	db.First(&user, "email = ?", "b@example.org")
+	if user.Name != "b2" || user.Logins != 1 {
+		t.Errorf("only provided columns should be updated, got %+v", user)
+	}
+
+	db.Model(&UpsertUser{}).Create(map[string]interface{}{"email": "d@example.org", "logins": 1})
+	tx = db.Model(&UpsertUser{}).Upsert(map[string]interface{}{"email": "d@example.org", "name": "d"}, gorm.UpsertOptions{OnlyIfChanged: true})
+	if tx.Error != nil || tx.RowsAffected != 1 {
+		t.Errorf("row changed from NULL should be updated, got affected %v, error %v", tx.RowsAffected, tx.Error)
+	}
+
+	var changed UpsertUser
+	db.First(&changed, "email = ?", "d@example.org")
+	if changed.Name != "d" {
+		t.Errorf("NULL column should be updated, got %+v", changed)
+	}
+}
+
+func TestUpsertSQL(t *testing.T) {
+	config := &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true}
+	var statuses []gorm.UpsertStatus
+
+	pg, err := gorm.Open(postgres.Open("user=gorm dbname=gorm"), gorm.DBTypePostgres, config)
+	if err != nil {
+		t.Fatalf("failed to open postgres, got error %v", err)
+	}
+
+	stmt := pg.Upsert(&UpsertUser{Email: "a@example.org", Logins: 1}, gorm.UpsertOptions{
+		Increments: []string{"logins"}, OnlyIfChanged: true, Statuses: &statuses,
+	}).Statement
+	expected := `INSERT INTO "upsert_users" ("email","name","logins") VALUES ($1,$2,$3) ON CONFLICT ("email") DO UPDATE SET "name"="excluded"."name","logins"="upsert_users"."logins" + "excluded"."logins" WHERE ("upsert_users"."name" IS DISTINCT FROM "excluded"."name" OR "excluded"."logins" <> $4)  RETURNING "id",(xmax = 0) AS gorm_upsert_status`
+	if sql := strings.TrimSpace(stmt.SQL.String()); sql != expected {
+		t.Errorf("expects sql\n%v\ngot\n%v", expected, sql)
+	}
+
+	mssql, err := gorm.Open(sqlserver.Open("sqlserver://gorm@localhost"), gorm.DBTypeSQLServer, config)
+	if err != nil {
+		t.Fatalf("failed to open sqlserver, got error %v", err)
+	}
+
+	stmt = mssql.Upsert(&UpsertUser{Email: "a@example.org", Name: "a"}, gorm.UpsertOptions{Statuses: &statuses}).Statement
+	expected = `MERGE INTO "upsert_users" USING (VALUES(@p1,@p2,@p3)) AS excluded ("email","name","logins") ON "upsert_users"."email" = "excluded"."email" WHEN MATCHED THEN UPDATE SET "name"="excluded"."name","logins"="excluded"."logins" WHEN NOT MATCHED THEN INSERT ("email","name","logins") VALUES ("excluded"."email","excluded"."name","excluded"."logins") OUTPUT INSERTED."id", $action AS "gorm_upsert_status";`
+	if sql := stmt.SQL.String(); sql != expected {
+		t.Errorf("expects sql\n%v\ngot\n%v", expected, sql)
+	}
+}
@@ -0,0 +1,147 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/fangxing98/jx-gorm/gorm/clause"
+	"github.com/fangxing98/jx-gorm/gorm/schema"
+	"github.com/fangxing98/jx-gorm/gorm/utils"
+)
+
+// WhereHas add conditions that the relation records with given conditions exist, nested relations are separated
+// by dots, the relation tables are aliased with the relation names like joins, e.g. `Orders`, `Orders__Items`
+//
+//	// SELECT * FROM `users` WHERE EXISTS (SELECT 1 FROM `orders` `Orders` WHERE `Orders`.`user_id` = `users`.`id` AND state = "paid")
+//	db.WhereHas("Orders", "state = ?", "paid").Find(&users)
+//	// users have orders of the product
+//	db.WhereHas("Orders.Items", func(tx *gorm.DB) *gorm.DB {
+//		return tx.Where("product_id = ?", productID)
+//	}).Find(&users)
+func (db *DB) WhereHas(name string, args ...interface{}) (tx *DB) {
+	return db.Where(existsExpression{names: strings.Split(name, "."), conds: args})
+}
+
+// WhereDoesntHave add conditions that the relation records with given conditions don't exist
+//
+//	// SELECT * FROM `users` WHERE NOT EXISTS (SELECT 1 FROM `orders` `Orders` WHERE `Orders`.`user_id` = `users`.`id`)
+//	db.WhereDoesntHave("Orders").Find(&users)
+func (db *DB) WhereDoesntHave(name string, args ...interface{}) (tx *DB) {
+	return db.Where(existsExpression{names: strings.Split(name, "."), conds: args, not: true})
+}
+
+// OrWhereHas add OR conditions that the relation records with given conditions exist
+//
+//	db.Where("role = ?", "admin").OrWhereHas("Orders").Find(&users)
+func (db *DB) OrWhereHas(name string, args ...interface{}) (tx *DB) {
+	return db.Or(existsExpression{names: strings.Split(name, "."), conds: args})
+}
+
+// existsExpression correlated EXISTS subquery of the relation, built with the schema of the statement
+type existsExpression struct {
+	names []string
+	conds []interface{}
+	not   bool
+	// parent schema and table of nested relations, the statement's if empty
+	schema *schema.Schema
+	table  string
+	alias  string
+}
+
+// Build implements clause.Expression interface
+func (exists existsExpression) Build(builder clause.Builder) {
+	stmt, ok := builder.(*Statement)
+	if !ok {
+		return
+	}
+
+	s, table := exists.schema, exists.table
+	if s == nil {
+		if s, table = stmt.Schema, stmt.Table; s == nil {
+			stmt.AddError(fmt.Errorf("%w when using WhereHas", ErrModelValueRequired))
+			return
+		}
+	}
+
+	rel := s.Relationships.Relations[exists.names[0]]
+	if rel == nil {
+		stmt.AddError(fmt.Errorf("%s: %w for schema %s", exists.names[0], ErrUnsupportedRelation, s.Name))
+		return
+	}
+
+	alias := rel.Name
+	if exists.alias != "" {
+		alias = utils.NestedRelationName(exists.alias, rel.Name)
+	}
+
+	tx := stmt.DB.Session(&Session{NewDB: true, Context: stmt.Context}).Model(reflect.New(rel.FieldSchema.ModelType).Interface())
+	tx.Statement.TableExpr = &clause.Expr{SQL: "?", Vars: []interface{}{clause.Table{Name: rel.FieldSchema.Table, Alias: alias}}}
+	tx.Statement.Table = alias
+	tx.Statement.Unscoped = stmt.Unscoped
+
+	if rel.JoinTable != nil {
+		var (
+			joinAlias = utils.NestedRelationName(alias, rel.JoinTable.Table)
+			joinConds = make([]clause.Expression, 0, len(rel.References))
+		)
+
+		for _, ref := range rel.References {
+			if ref.OwnPrimaryKey {
+				tx = tx.Where(clause.Eq{
+					Column: clause.Column{Table: joinAlias, Name: ref.ForeignKey.DBName},
+					Value:  clause.Column{Table: table, Name: ref.PrimaryKey.DBName},
+				})
+			} else if ref.PrimaryValue != "" {
+				joinConds = append(joinConds, clause.Eq{Column: clause.Column{Table: joinAlias, Name: ref.ForeignKey.DBName}, Value: ref.PrimaryValue})
+			} else {
+				joinConds = append(joinConds, clause.Eq{
+					Column: clause.Column{Table: joinAlias, Name: ref.ForeignKey.DBName},
+					Value:  clause.Column{Table: alias, Name: ref.PrimaryKey.DBName},
+				})
+			}
+		}
+		tx = tx.Joins("INNER JOIN ? ON ?", clause.Table{Name: rel.JoinTable.Table, Alias: joinAlias}, clause.And(joinConds...))
+	} else {
+		for _, ref := range rel.References {
+			if ref.OwnPrimaryKey {
+				tx = tx.Where(clause.Eq{
+					Column: clause.Column{Table: alias, Name: ref.ForeignKey.DBName},
+					Value:  clause.Column{Table: table, Name: ref.PrimaryKey.DBName},
+				})
+			} else if ref.PrimaryValue != "" {
+				tx = tx.Where(clause.Eq{Column: clause.Column{Table: alias, Name: ref.ForeignKey.DBName}, Value: ref.PrimaryValue})
+			} else {
+				tx = tx.Where(clause.Eq{
+					Column: clause.Column{Table: table, Name: ref.ForeignKey.DBName},
+					Value:  clause.Column{Table: alias, Name: ref.PrimaryKey.DBName},
+				})
+			}
+		}
+	}
+
+	if len(exists.names) > 1 {
+		// conditions apply to the last relation of nested relations
+		tx = tx.Where(existsExpression{names: exists.names[1:], conds: exists.conds, schema: rel.FieldSchema, table: alias, alias: alias})
+	} else {
+		var inlineConds []interface{}
+		for _, cond := range exists.conds {
+			if fc, ok := cond.(func(*DB) *DB); ok {
+				tx = fc(tx)
+			} else {
+				inlineConds = append(inlineConds, cond)
+			}
+		}
+
+		if len(inlineConds) > 0 {
+			tx = tx.Where(inlineConds[0], inlineConds[1:]...)
+		}
+	}
+
+	if exists.not {
+		builder.WriteString("NOT ")
+	}
+	builder.WriteString("EXISTS (")
+	stmt.AddVar(builder, tx.Select("1"))
+	builder.WriteByte(')')
+}
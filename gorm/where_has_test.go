@@ -0,0 +1,107 @@
+package gorm_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fangxing98/jx-gorm/driver/postgres"
+	"github.com/fangxing98/jx-gorm/gorm"
+	"github.com/fangxing98/jx-gorm/gorm/internal/testdb"
+)
+
+type HasItem struct {
+	ID         uint
+	HasOrderID uint
+	Product    string
+}
+
+type HasOrder struct {
+	ID        uint
+	HasUserID uint
+	State     string
+	Items     []HasItem
+	DeletedAt gorm.DeletedAt
+}
+
+type HasTag struct {
+	ID   uint
+	Name string
+}
+
+type HasComment struct {
+	ID        uint
+	OwnerID   uint
+	OwnerType string
+}
+
+type HasUser struct {
+	ID        uint
+	Name      string
+	Orders    []HasOrder
+	Tags      []HasTag     `gorm:"many2many:has_user_tags"`
+	Comments  []HasComment `gorm:"polymorphic:Owner"`
+	ManagerID *uint
+	Manager   *HasUser
+	Assistant *HasUser `gorm:"foreignKey:ManagerID"`
+}
+
+func TestWhereHas(t *testing.T) {
+	db := testdb.Open(t, nil, &HasUser{}, &HasOrder{}, &HasItem{}, &HasTag{}, &HasComment{})
+
+	boss := HasUser{Name: "boss", Tags: []HasTag{{Name: "vip"}}}
+	db.Create(&boss)
+	users := []HasUser{
+		{Name: "a", Manager: &boss, Orders: []HasOrder{{State: "paid", Items: []HasItem{{Product: "book"}}}}, Comments: []HasComment{{}}},
+		{Name: "b", Orders: []HasOrder{{State: "cancelled", Items: []HasItem{{Product: "pen"}}}}},
+		{Name: "c"},
+	}
+	db.Create(&users)
+	db.Delete(&users[1].Orders[0])
+
+	names := func(tx *gorm.DB) string {
+		var result []string
+		if err := tx.Model(&HasUser{}).Order("id").Pluck("name", &result).Error; err != nil {
+			t.Fatalf("failed to query, got error %v", err)
+		}
+		return strings.Join(result, ",")
+	}
+
+	cases := []struct {
+		tx       *gorm.DB
+		expected string
+	}{
+		{db.WhereHas("Orders"), "a"},
+		{db.Unscoped().WhereHas("Orders"), "a,b"},
+		{db.WhereHas("Orders", "state = ?", "cancelled"), ""},
+		{db.WhereDoesntHave("Orders"), "boss,b,c"},
+		{db.WhereHas("Orders.Items", func(tx *gorm.DB) *gorm.DB { return tx.Where("product = ?", "book") }), "a"},
+		{db.Unscoped().WhereHas("Orders.Items", "product = ?", "pen"), "b"},
+		{db.WhereHas("Tags", "name = ?", "vip"), "boss"},
+		{db.WhereHas("Comments"), "a"},
+		{db.WhereHas("Manager", "name = ?", "boss"), "a"},
+		{db.Where("name = ?", "c").OrWhereHas("Tags"), "boss,c"},
+	}
+
+	for idx, c := range cases {
+		if got := names(c.tx); got != c.expected {
+			t.Errorf("#%d expects %q, got %q", idx, c.expected, got)
+		}
+	}
+
+	if err := db.WhereHas("Unknown").Find(&users).Error; err == nil {
+		t.Errorf("unknown relation should return error")
+	}
+}
+
+func TestWhereHasSQL(t *testing.T) {
+	db, err := gorm.Open(postgres.Open("user=gorm dbname=gorm"), gorm.DBTypePostgres, &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatalf("failed to open postgres, got error %v", err)
+	}
+
+	stmt := db.WhereDoesntHave("Tags", "name = ?", "vip").Find(&[]HasUser{}).Statement
+	expected := `SELECT * FROM "has_users" WHERE NOT EXISTS (SELECT 1 FROM "has_tags" "Tags" INNER JOIN "has_user_tags" "Tags__has_user_tags" ON "Tags__has_user_tags"."has_tag_id" = "Tags"."id" WHERE "Tags__has_user_tags"."has_user_id" = "has_users"."id" AND name = $1)`
+	if sql := stmt.SQL.String(); sql != expected {
+		t.Errorf("expects sql\n%v\ngot\n%v", expected, sql)
+	}
+}